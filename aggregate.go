@@ -0,0 +1,225 @@
+package xyt
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+)
+
+// An aggregator keeps running totals for a set of records, so that
+// aggregations can be calculated without holding onto the records
+// themselves
+type aggregator struct {
+	count    uint64
+	sum      float64
+	min, max float64
+
+	// mean and m2 are maintained with Welford's algorithm, which avoids
+	// the catastrophic cancellation a naive sum-of-squares suffers from
+	// over large numbers of readings
+	mean, m2 float64
+
+	first, last         float64
+	firstWhen, lastWhen time.Time
+}
+
+func (a *aggregator) add(r *server.Record) {
+	a.addValue(r.Value, r.Meta.When.AsTime())
+}
+
+func (a *aggregator) addValue(v float64, when time.Time) {
+	a.count++
+	a.sum += v
+
+	if a.count == 1 {
+		a.min, a.max = v, v
+		a.first, a.firstWhen = v, when
+		a.last, a.lastWhen = v, when
+	}
+
+	a.min = min(a.min, v)
+	a.max = max(a.max, v)
+
+	delta := v - a.mean
+	a.mean += delta / float64(a.count)
+	a.m2 += delta * (v - a.mean)
+
+	if when.Before(a.firstWhen) {
+		a.first, a.firstWhen = v, when
+	}
+
+	if !when.Before(a.lastWhen) {
+		a.last, a.lastWhen = v, when
+	}
+}
+
+// merge folds b into a, as though every record added to b had
+// been added to a instead
+func (a *aggregator) merge(b *aggregator) {
+	if b.count == 0 {
+		return
+	}
+
+	if a.count == 0 {
+		*a = *b
+
+		return
+	}
+
+	n := a.count + b.count
+
+	// Chan et al's method for combining Welford's running
+	// mean and m2 across two sets of readings
+	delta := b.mean - a.mean
+	a.mean += delta * float64(b.count) / float64(n)
+	a.m2 += b.m2 + delta*delta*float64(a.count)*float64(b.count)/float64(n)
+
+	a.count = n
+	a.sum += b.sum
+	a.min = min(a.min, b.min)
+	a.max = max(a.max, b.max)
+
+	if b.firstWhen.Before(a.firstWhen) {
+		a.first, a.firstWhen = b.first, b.firstWhen
+	}
+
+	if !b.lastWhen.Before(a.lastWhen) {
+		a.last, a.lastWhen = b.last, b.lastWhen
+	}
+}
+
+// visit adds whatever walk passes; rolled up buckets are merged
+// whole, rather than added as a single reading
+func (a *aggregator) visit(r *server.Record, rolled *aggregator) {
+	if rolled != nil {
+		a.merge(rolled)
+
+		return
+	}
+
+	a.add(r)
+}
+
+// value returns the result of the aggregation ag. Aggregations other than
+// Count and Sum are undefined over no records, and so return NaN
+func (a *aggregator) value(ag server.Aggregation) float64 {
+	switch ag {
+	case server.Aggregation_Count:
+		return float64(a.count)
+
+	case server.Aggregation_Sum:
+		return a.sum
+	}
+
+	if a.count == 0 {
+		return math.NaN()
+	}
+
+	switch ag {
+	case server.Aggregation_Min:
+		return a.min
+
+	case server.Aggregation_Max:
+		return a.max
+
+	case server.Aggregation_Mean:
+		return a.mean
+
+	case server.Aggregation_Stddev:
+		// Population, rather than sample, standard deviation; we
+		// have every reading, not a sample of them
+		return math.Sqrt(a.m2 / float64(a.count))
+
+	case server.Aggregation_First:
+		return a.first
+
+	case server.Aggregation_Last:
+		return a.last
+
+	default:
+		return math.NaN()
+	}
+}
+
+func (a *aggregator) result(name string, aggregations []server.Aggregation) *server.AggregateResult {
+	res := &server.AggregateResult{
+		Name:   name,
+		Count:  a.count,
+		Values: make([]*server.AggregateValue, len(aggregations)),
+	}
+
+	for i, ag := range aggregations {
+		res.Values[i] = &server.AggregateValue{
+			Aggregation: ag,
+			Value:       a.value(ag),
+		}
+	}
+
+	return res
+}
+
+// Aggregate runs each of aggregations over every record matching q, without
+// ever building a slice of those records.
+//
+// When byName is false a single result is returned, covering every matching record.
+// When byName is true a result is returned per record Name, sorted by name, and names
+// with no matching records are left out.
+//
+// First and Last are the values of the records with the earliest and latest `When`
+// values respectively, and Stddev is the population standard deviation.
+func (d *Database) Aggregate(q *server.Query, aggregations []server.Aggregation, byName bool) (results []*server.AggregateResult, err error) {
+	for _, ag := range aggregations {
+		if !validAggregation(ag) {
+			return nil, UnknownAggregationError
+		}
+	}
+
+	unlock := d.rlock(q.GetDataset())
+	defer unlock()
+
+	if !byName {
+		a := new(aggregator)
+
+		err = d.walk(q, a.visit)
+		if err != nil {
+			return
+		}
+
+		return []*server.AggregateResult{a.result("", aggregations)}, nil
+	}
+
+	byNames := make(map[string]*aggregator)
+
+	err = d.walk(q, func(r *server.Record, rolled *aggregator) {
+		a, ok := byNames[r.Name]
+		if !ok {
+			a = new(aggregator)
+			byNames[r.Name] = a
+		}
+
+		a.visit(r, rolled)
+	})
+	if err != nil {
+		return
+	}
+
+	results = make([]*server.AggregateResult, 0, len(byNames))
+	for name, a := range byNames {
+		results = append(results, a.result(name, aggregations))
+	}
+
+	slices.SortFunc(results, func(a, b *server.AggregateResult) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return
+}
+
+func validAggregation(ag server.Aggregation) bool {
+	_, ok := server.Aggregation_name[int32(ag)]
+
+	return ok
+}
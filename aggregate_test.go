@@ -0,0 +1,205 @@
+package xyt
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var allAggregations = []server.Aggregation{
+	server.Aggregation_Count,
+	server.Aggregation_Sum,
+	server.Aggregation_Min,
+	server.Aggregation_Max,
+	server.Aggregation_Mean,
+	server.Aggregation_Stddev,
+	server.Aggregation_First,
+	server.Aggregation_Last,
+}
+
+func TestDatabase_Aggregate(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.CreateDataset(&server.Schema{
+		Dataset: "site-a",
+		XMax:    10,
+		YMax:    10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := time.Now()
+
+	// Insert values out of time order, so first/last can't just
+	// be the first and last records we happen to walk
+	for i, v := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		for _, name := range []string{"temperature", "voltage"} {
+			value := v
+			if name == "voltage" {
+				value *= 10
+			}
+
+			err = d.InsertRecord(&server.Record{
+				Meta: &server.Metadata{
+					When: timestamppb.New(ts.Add(time.Duration(8-i) * time.Second)),
+				},
+				Dataset: "site-a",
+				Name:    name,
+				Value:   value,
+				X:       int32(i),
+				Y:       int32(i),
+				T:       90,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	for _, test := range []struct {
+		name        string
+		query       *server.Query
+		byName      bool
+		expect      map[string][]float64
+		expectError bool
+	}{
+		{"Invalid queries error", &server.Query{}, false, nil, true},
+		{"Empty queries aggregate nothing", &server.Query{Dataset: "site-a", Names: []string{"flurbles"}}, false,
+			map[string][]float64{"": {0, 0, math.NaN(), math.NaN(), math.NaN(), math.NaN(), math.NaN(), math.NaN()}}, false},
+		{"A single name aggregates that name", &server.Query{Dataset: "site-a", Names: []string{"temperature"}}, false,
+			map[string][]float64{"": {8, 40, 2, 9, 5, 2, 9, 2}}, false},
+		{"Results can be split by name", &server.Query{Dataset: "site-a"}, true,
+			map[string][]float64{
+				"temperature": {8, 40, 2, 9, 5, 2, 9, 2},
+				"voltage":     {8, 400, 20, 90, 50, 20, 90, 20},
+			}, false},
+		{"Aggregations respect the query", &server.Query{Dataset: "site-a", X: &server.Query_XRange{XRange: &server.QueryRange{Start: 0, End: 2}}}, true,
+			map[string][]float64{
+				"temperature": {2, 6, 2, 4, 3, 1, 4, 2},
+				"voltage":     {2, 60, 20, 40, 30, 10, 40, 20},
+			}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			results, err := d.Aggregate(test.query, allAggregations, test.byName)
+			if err == nil && test.expectError {
+				t.Errorf("expected error, received none")
+			} else if err != nil && !test.expectError {
+				t.Errorf("unexpected error %#v", err)
+			}
+
+			if len(test.expect) != len(results) {
+				t.Fatalf("expected %d results, received %d", len(test.expect), len(results))
+			}
+
+			for _, res := range results {
+				expect, ok := test.expect[res.Name]
+				if !ok {
+					t.Errorf("unexpected result for %q", res.Name)
+
+					continue
+				}
+
+				for i, v := range res.Values {
+					if v.Aggregation != allAggregations[i] {
+						t.Errorf("expected %s, received %s", allAggregations[i], v.Aggregation)
+					}
+
+					if !floatsMatch(expect[i], v.Value) {
+						t.Errorf("%s: %s: expected %v, received %v", res.Name, v.Aggregation, expect[i], v.Value)
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("Unknown aggregations error", func(t *testing.T) {
+		_, err := d.Aggregate(&server.Query{Dataset: "site-a"}, []server.Aggregation{100}, false)
+		if err != UnknownAggregationError {
+			t.Errorf("expected UnknownAggregationError, received %#v", err)
+		}
+	})
+}
+
+func TestAggregator_merge(t *testing.T) {
+	ts := time.Now()
+	values := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+
+	var all, a, b, empty aggregator
+	for i, v := range values {
+		when := ts.Add(time.Duration(len(values)-i) * time.Second)
+
+		all.addValue(v, when)
+		if i%3 == 0 {
+			a.addValue(v, when)
+		} else {
+			b.addValue(v, when)
+		}
+	}
+
+	empty.merge(&a)
+	empty.merge(&b)
+	a.merge(&b)
+
+	for _, merged := range []aggregator{a, empty} {
+		for _, ag := range allAggregations {
+			if !floatsMatch(all.value(ag), merged.value(ag)) {
+				t.Errorf("%s: expected %v, received %v", ag, all.value(ag), merged.value(ag))
+			}
+		}
+	}
+}
+
+func floatsMatch(a, b float64) bool {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.IsNaN(a) && math.IsNaN(b)
+	}
+
+	return math.Abs(a-b) < 1e-9
+}
+
+func BenchmarkDatabase_Aggregate(b *testing.B) {
+	d, err := New()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	err = d.CreateDataset(&server.Schema{
+		Dataset:   "site-a",
+		XMax:      100,
+		YMax:      100,
+		Frequency: server.Frequency_F100Hz,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	ts := timestamppb.Now()
+	for x := int32(0); x < 100; x++ {
+		for y := int32(0); y < 100; y++ {
+			d.InsertRecord(&server.Record{
+				Meta:    &server.Metadata{When: ts},
+				Dataset: "site-a",
+				Name:    "a-value",
+				Value:   float64(x * y),
+				X:       x,
+				Y:       y,
+			})
+		}
+	}
+
+	b.ResetTimer()
+
+	for j := 0; j < b.N; j++ {
+		_, err = d.Aggregate(&server.Query{Dataset: "site-a"}, allAggregations, false)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package xyt
+
+import (
+	"math"
+	"slices"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/proto"
+)
+
+// AlterSchema changes an existing dataset's schema, changing only the fields
+// set on a:
+//
+//	Bounds: X, Y, and Z bounds may grow, but never shrink. Growing X or Y reallocates the
+//		dataset's grid, moving every location across to it
+//	Frequency: applies to each location as it next grows
+//	SortOnInsert: turning it on sorts the records already at each location, and its indices
+//	Retention: may be lengthened or shortened; shortening it means the janitor drops more
+//		   records on its next run, just as it would for a new dataset
+//
+// Changes which would drop records, such as shrinking the dataset's bounds,
+// are rejected with SchemaShrinkError, and the resulting schema must be as
+// valid as one passed to CreateDataset.
+//
+// Every dataset is locked for the duration, as per CreateDataset; growing a
+// large, dense, dataset takes about as long as creating one of the new size
+func (d *Database) AlterSchema(a *server.AlterSchemaRequest) (err error) {
+	if a == nil || a.Dataset == "" {
+		return MissingDatasetError
+	}
+
+	d.mutx.Lock()
+	defer d.mutx.Unlock()
+
+	old, ok := d.schemata[a.Dataset]
+	if !ok {
+		return UnknownDatasetError
+	}
+
+	// Changes are made to a copy, so that those which fail
+	// validation leave the dataset exactly as it was
+	s := proto.Clone(old).(*server.Schema)
+	alterSchema(s, a)
+
+	err = d.validateSchema(s)
+	if err != nil {
+		return
+	}
+
+	oldZMin, oldZMax := zBounds(old)
+	newZMin, newZMax := zBounds(s)
+
+	if s.XMin > old.XMin || s.XMax < old.XMax || s.YMin > old.YMin || s.YMax < old.YMax ||
+		newZMin > oldZMin || newZMax < oldZMax {
+		return SchemaShrinkError
+	}
+
+	if d.wal != nil {
+		err = d.wal.appendAlterSchema(a)
+		if err != nil {
+			return
+		}
+	}
+
+	if s.XMin != old.XMin || s.XMax != old.XMax || s.YMin != old.YMin || s.YMax != old.YMax {
+		d.data[a.Dataset] = d.data[a.Dataset].resize(s)
+		d.tiles[a.Dataset] = rebuildTiles(s, d.data[a.Dataset])
+	}
+
+	if s.SortOnInsert && !old.SortOnInsert {
+		d.sortDataset(s)
+	}
+
+	d.schemata[a.Dataset] = s
+
+	// Subscriptions' ranges are clamped to the schema's bounds,
+	// so need working out again now those bounds may have grown.
+	// Their polygons are kept as they are, since the zones they
+	// came from may have been deleted since
+	for sub := range d.subscriptions[a.Dataset] {
+		sub.m.sorted = s.SortOnInsert
+		sub.m.bound(s, sub.query)
+	}
+
+	return
+}
+
+// alterSchema applies the changes in a to s
+func alterSchema(s *server.Schema, a *server.AlterSchemaRequest) {
+	for _, f := range []struct {
+		from *int32
+		to   *int32
+	}{
+		{a.XMin, &s.XMin},
+		{a.XMax, &s.XMax},
+		{a.YMin, &s.YMin},
+		{a.YMax, &s.YMax},
+		{a.ZMin, &s.ZMin},
+		{a.ZMax, &s.ZMax},
+	} {
+		if f.from != nil {
+			*f.to = *f.from
+		}
+	}
+
+	if a.Frequency != nil {
+		s.Frequency = *a.Frequency
+	}
+
+	if a.SortOnInsert != nil {
+		s.SortOnInsert = *a.SortOnInsert
+	}
+
+	if a.Retention != nil {
+		s.Retention = a.Retention
+	}
+}
+
+// rebuildTiles returns a fresh quadtree for s, covering every record in g
+func rebuildTiles(s *server.Schema, g grid) *quadtree {
+	tiles := newQuadtree(s)
+
+	g.each(s.XMin, s.XMax, s.YMin, s.YMax, func(x, y int32, c *cell) {
+		for _, series := range c.series {
+			if series.columns != nil {
+				series.columns.each(math.MinInt64, math.MaxInt64, false, func(when int64, _ int32, _ float64) bool {
+					tiles.add(x, y, when)
+
+					return true
+				})
+
+				continue
+			}
+
+			for _, r := range series.records {
+				tiles.add(x, y, unixNano(r.Meta.When.AsTime()))
+			}
+		}
+	})
+
+	return tiles
+}
+
+// sortDataset sorts the records at every location in the dataset described
+// by s, along with its indices, by `When`, for datasets which weren't sorted on
+// insert. Sorted copies replace the originals, since queries may be reading them.
+//
+// It must be called with d.mutx held for writing
+func (d *Database) sortDataset(s *server.Schema) {
+	sorted := func(records []*server.Record) []*server.Record {
+		return slices.SortedStableFunc(slices.Values(records), compareWhen)
+	}
+
+	d.data[s.Dataset].each(s.XMin, s.XMax, s.YMin, s.YMax, func(_, _ int32, c *cell) {
+		for _, series := range c.series {
+			if series.columns != nil {
+				series.columns.sort()
+
+				continue
+			}
+
+			series.records = sorted(series.records)
+		}
+	})
+
+	for _, idx := range d.indices[s.Dataset] {
+		for _, p := range idx.values {
+			for k, records := range p.cells {
+				p.cells[k] = sorted(records)
+			}
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package xyt
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestDatabase_AlterSchema(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		a           *server.AlterSchemaRequest
+		expectError error
+	}{
+		{"Nil requests fail", nil, MissingDatasetError},
+		{"Missing datasets fail", &server.AlterSchemaRequest{}, MissingDatasetError},
+		{"Unknown datasets fail", &server.AlterSchemaRequest{Dataset: "site-b"}, UnknownDatasetError},
+		{"Shrinking X fails", &server.AlterSchemaRequest{Dataset: "site-a", XMax: proto.Int32(5)}, SchemaShrinkError},
+		{"Shrinking Y fails", &server.AlterSchemaRequest{Dataset: "site-a", YMin: proto.Int32(1)}, SchemaShrinkError},
+		{"Shrinking Z fails", &server.AlterSchemaRequest{Dataset: "site-a", ZMax: proto.Int32(-1)}, SchemaShrinkError},
+		{"Inverted bounds fail", &server.AlterSchemaRequest{Dataset: "site-a", XMin: proto.Int32(20)}, InvalidCoordRangeError{"site-a", positionX, coordRangeErrorReasonMinMax}},
+		{"Negative retention fails", &server.AlterSchemaRequest{Dataset: "site-a", Retention: durationpb.New(-time.Hour)}, InvalidRetentionError},
+		{"Empty changes are fine", &server.AlterSchemaRequest{Dataset: "site-a"}, nil},
+		{"Frequency changes", &server.AlterSchemaRequest{Dataset: "site-a", Frequency: server.Frequency_F1000Hz.Enum()}, nil},
+		{"Retention changes", &server.AlterSchemaRequest{Dataset: "site-a", Retention: durationpb.New(time.Hour)}, nil},
+		{"Bounds grow", &server.AlterSchemaRequest{Dataset: "site-a", XMin: proto.Int32(-10), YMax: proto.Int32(20)}, nil},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			d := alterDatabase(t, false)
+
+			before := d.Datasets()["site-a"]
+
+			err := d.AlterSchema(test.a)
+			if err != test.expectError {
+				t.Fatalf("expected %#v, received %#v", test.expectError, err)
+			}
+
+			after := d.Datasets()["site-a"]
+
+			switch {
+			case err != nil:
+				if !proto.Equal(before, after) {
+					t.Errorf("expected schema to be unchanged, received %#v", after)
+				}
+
+			default:
+				expect := proto.Clone(before).(*server.Schema)
+				alterSchema(expect, test.a)
+
+				if !proto.Equal(expect, after) {
+					t.Errorf("expected %#v, received %#v", expect, after)
+				}
+			}
+
+			records, err := d.RetrieveRecords(&server.Query{Dataset: "site-a"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(records) != 10 {
+				t.Errorf("expected %d records, received %d", 10, len(records))
+			}
+		})
+	}
+}
+
+func TestDatabase_AlterSchema_Grow(t *testing.T) {
+	for _, sparse := range []bool{false, true} {
+		t.Run(map[bool]string{false: "dense", true: "sparse"}[sparse], func(t *testing.T) {
+			d := alterDatabase(t, sparse)
+
+			s, err := d.Subscribe(&server.SubscribeRequest{Query: &server.Query{Dataset: "site-a"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer s.Close()
+
+			err = d.AlterSchema(&server.AlterSchemaRequest{
+				Dataset: "site-a",
+				XMin:    proto.Int32(-5),
+				XMax:    proto.Int32(20),
+				YMax:    proto.Int32(20),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Records from before the change stay where they were
+			for i := int32(0); i < 10; i++ {
+				records, err := d.RetrieveRecords(&server.Query{
+					Dataset: "site-a",
+					X:       &server.Query_XValue{XValue: i},
+					Y:       &server.Query_YValue{YValue: i},
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(records) != 1 || records[0].Value != float64(i) {
+					t.Errorf("(%d,%d): expected a single record of %d, received %#v", i, i, i, records)
+				}
+			}
+
+			// ...whilst the newly added area can be inserted into, and
+			// queried, like any other
+			for _, loc := range [][2]int32{{-5, 0}, {19, 19}} {
+				r := alterRecord(loc[0], loc[1], 100)
+
+				err = d.InsertRecord(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+
+				received, err := s.Next(ctx)
+				cancel()
+
+				if err != nil {
+					t.Fatalf("unexpected error %#v", err)
+				}
+
+				if received.X != r.X || received.Y != r.Y {
+					t.Errorf("expected subscription to receive (%d,%d), received (%d,%d)", r.X, r.Y, received.X, received.Y)
+				}
+			}
+
+			records, err := d.RetrieveRecords(&server.Query{
+				Dataset: "site-a",
+				X:       &server.Query_XRange{XRange: &server.QueryRange{Start: 10, End: 20}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(records) != 1 {
+				t.Errorf("expected %d records, received %d", 1, len(records))
+			}
+
+			expectRecordCount(t, d, "site-a", 12)
+		})
+	}
+}
+
+func TestDatabase_AlterSchema_DeletedZones(t *testing.T) {
+	d := alterDatabase(t, false)
+
+	err := d.AddZone(&server.Zone{Dataset: "site-a", Name: "dock", Polygon: &server.Polygon{Outline: ring(0, 0, 3, 0, 3, 3, 0, 3)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := d.Subscribe(&server.SubscribeRequest{Query: &server.Query{Dataset: "site-a", Zones: []string{"dock"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer s.Close()
+
+	err = d.DeleteZone("site-a", "dock")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.AlterSchema(&server.AlterSchemaRequest{Dataset: "site-a", XMax: proto.Int32(20)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The subscription carries on matching against the deleted
+	// zone, rather than against the whole of the grown dataset
+	for _, loc := range [][2]int32{{9, 9}, {1, 1}} {
+		err = d.InsertRecord(alterRecord(loc[0], loc[1], 100))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	received, err := s.Next(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if received.X != 1 || received.Y != 1 {
+		t.Errorf("expected subscription to receive (1,1), received (%d,%d)", received.X, received.Y)
+	}
+}
+
+func TestDatabase_AlterSchema_SortOnInsert(t *testing.T) {
+	for _, columnar := range []bool{false, true} {
+		t.Run(map[bool]string{false: "records", true: "columnar"}[columnar], func(t *testing.T) {
+			// Insert, out of order, at a single location
+			var records []*server.Record
+			for _, when := range []int32{5, 1, 4, 2, 3} {
+				r := alterRecord(0, 0, when)
+				if !columnar {
+					r.Meta.Indices = map[string]string{"robot": "robo-001"}
+				}
+
+				records = append(records, r)
+			}
+
+			d := testDatabase(t, nil, []*server.Schema{{Dataset: "site-a", XMax: 10, YMax: 10, Columnar: columnar}}, records)
+
+			err := d.AlterSchema(&server.AlterSchemaRequest{Dataset: "site-a", SortOnInsert: proto.Bool(true)})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Then carry on inserting, in order, now the dataset expects it
+			err = d.InsertRecord(alterRecord(0, 0, 6))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, test := range []struct {
+				name   string
+				q      *server.Query
+				expect []float64
+				skip   bool
+			}{
+				{"Everything", &server.Query{}, []float64{1, 2, 3, 4, 5, 6}, false},
+				{"Time ranges", &server.Query{Time: &server.Query_TimeRange{TimeRange: &server.TimeRange{Start: timestamppb.New(time.Unix(2, 0)), End: timestamppb.New(time.Unix(4, 0))}}}, []float64{2, 3, 4}, false},
+				{"Indices", &server.Query{IndexKey: "robot", IndexValue: "robo-001"}, []float64{1, 2, 3, 4, 5}, columnar},
+			} {
+				if test.skip {
+					continue
+				}
+
+				t.Run(test.name, func(t *testing.T) {
+					test.q.Dataset = "site-a"
+
+					records, err := d.RetrieveRecords(test.q)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					received := make([]float64, 0, len(records))
+					for _, r := range records {
+						received = append(received, r.Value)
+					}
+
+					if !slices.Equal(test.expect, received) {
+						t.Errorf("expected %v, received %v", test.expect, received)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestDatabase_AlterSchema_WAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "xyt.wal")
+
+	writeTestWAL(t, path)
+
+	w, err := OpenWAL(path, WALOptions{Policy: SyncAlways})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.UseWAL(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.AlterSchema(&server.AlterSchemaRequest{Dataset: "site-a", XMax: proto.Int32(20), YMax: proto.Int32(20)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Records beyond the original bounds can only be replayed
+	// once the dataset has been grown again
+	r := testWALRecord(15)
+
+	err = d.InsertRecord(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rejected changes aren't logged, and so never replayed
+	err = d.AlterSchema(&server.AlterSchemaRequest{Dataset: "site-a", XMax: proto.Int32(10)})
+	if err != SchemaShrinkError {
+		t.Fatalf("expected SchemaShrinkError, received %#v", err)
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, err = OpenWAL(path, WALOptions{Policy: SyncAlways})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer w.Close()
+
+	d, err = New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.UseWAL(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := d.Datasets()["site-a"]; s.XMax != 20 || s.YMax != 20 {
+		t.Errorf("expected (20,20) bounds after replay, received (%d,%d)", s.XMax, s.YMax)
+	}
+
+	records, err := d.RetrieveRecords(&server.Query{Dataset: "site-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 11 {
+		t.Errorf("expected 11 records after replay, received %d", len(records))
+	}
+}
+
+// alterDatabase returns a database holding a single dataset, site-a, with
+// a record at each of (0,0) to (9,9), along its diagonal
+func alterDatabase(t *testing.T, sparse bool) *Database {
+	t.Helper()
+
+	var records []*server.Record
+	for i := int32(0); i < 10; i++ {
+		records = append(records, alterRecord(i, i, i))
+	}
+
+	return testDatabase(t, nil, []*server.Schema{{
+		Dataset:      "site-a",
+		XMax:         10,
+		YMax:         10,
+		ZMin:         -5,
+		ZMax:         5,
+		Sparse:       sparse,
+		SortOnInsert: true,
+	}}, records)
+}
+
+// alterRecord returns a temperature record at (x,y), with both its
+// value and its `When` set from when
+func alterRecord(x, y, when int32) *server.Record {
+	return &server.Record{
+		Meta: &server.Metadata{
+			When: timestamppb.New(time.Unix(int64(when), 0)),
+		},
+		Dataset: "site-a",
+		Name:    "temperature",
+		Value:   float64(when),
+		X:       x,
+		Y:       y,
+	}
+}
@@ -0,0 +1,164 @@
+package xyt
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// A cell holds every record for a single (X,Y) location within a dataset.
+//
+// Records are split into a series per record Name, so that queries for a
+// subset of names never have to look at records for the others
+type cell struct {
+	// series is a slice, rather than a map, because cells rarely hold more
+	// than a handful of names and a linear scan of a handful of strings is
+	// both quicker and smaller than a map
+	series []*series
+}
+
+// A series holds the records for a single Name within a cell
+type series struct {
+	name    string
+	records []*server.Record
+
+	// columns holds readings in place of records for
+	// datasets with a Columnar schema, and is nil otherwise
+	columns *columns
+
+	// rollups holds the rollups for each of the schema's rollup
+	// tiers, in the same order as the tiers themselves
+	rollups [][]*rollup
+}
+
+func newCell() *cell {
+	return &cell{
+		series: make([]*series, 0),
+	}
+}
+
+// get returns the series for name, or nil if this cell
+// holds no records for that name
+func (c *cell) get(name string) *series {
+	for _, s := range c.series {
+		if s.name == name {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// insert adds r to the relevant series, creating that series where
+// necessary.
+//
+// Series are grown according to the schema's Frequency, and where the
+// schema has SortOnInsert set r is inserted in `When` order
+func (c *cell) insert(r *server.Record, schema *server.Schema) {
+	grow, sorted := frequencyToSize(schema.Frequency), schema.SortOnInsert
+
+	s := c.get(r.Name)
+	if s == nil {
+		s = &series{name: r.Name}
+		if schema.Columnar {
+			s.columns = new(columns)
+		}
+
+		c.series = append(c.series, s)
+	}
+
+	if s.columns != nil {
+		s.columns.insert(unixNano(r.Meta.When.AsTime()), r.T, r.Value, grow, sorted)
+
+		return
+	}
+
+	// Ensure we have enough space allocated to avoid re-allocating on every write
+	// and instead do allocations roughly once per second- which is at least more predictable
+	if len(s.records) >= cap(s.records) {
+		s.records = slices.Grow(s.records, grow)
+	}
+
+	if !sorted {
+		s.records = append(s.records, r)
+
+		return
+	}
+
+	s.records = insertSorted(s.records, r)
+}
+
+// records returns every record in the cell, at (x,y) in dataset, series
+// by series. Columnar series are rehydrated into records
+func (c *cell) records(dataset string, x, y int32) (r []*server.Record) {
+	n := 0
+	for _, s := range c.series {
+		n += s.len()
+	}
+
+	r = make([]*server.Record, 0, n)
+	for _, s := range c.series {
+		if s.columns == nil {
+			r = append(r, s.records...)
+
+			continue
+		}
+
+		s.columns.each(math.MinInt64, math.MaxInt64, false, func(when int64, t int32, v float64) bool {
+			r = append(r, s.rehydrate(dataset, x, y, when, t, v))
+
+			return true
+		})
+	}
+
+	return
+}
+
+// len returns the number of records, or readings, s holds
+func (s *series) len() int {
+	if s.columns != nil {
+		return s.columns.len()
+	}
+
+	return len(s.records)
+}
+
+// rehydrate returns a record for a single reading from a columnar series.
+//
+// Columnar series only keep timestamps, thetas and values, so rehydrated
+// records never have labels or index values
+func (s *series) rehydrate(dataset string, x, y int32, when int64, t int32, v float64) *server.Record {
+	return &server.Record{
+		Meta:    &server.Metadata{When: timestamppb.New(time.Unix(0, when))},
+		Dataset: dataset,
+		Name:    s.name,
+		Value:   v,
+		X:       x,
+		Y:       y,
+		T:       t,
+	}
+}
+
+// insertSorted inserts r into records, which must already be sorted by
+// `When`. Records sharing a timestamp are kept in insertion order
+func insertSorted(records []*server.Record, r *server.Record) []*server.Record {
+	// The overwhelmingly common case is that data arrives in order, so
+	// check for that before bothering with a search
+	if len(records) == 0 || compareWhen(records[len(records)-1], r) <= 0 {
+		return append(records, r)
+	}
+
+	i, found := slices.BinarySearchFunc(records, r, compareWhen)
+	for found && i < len(records) && compareWhen(records[i], r) == 0 {
+		i++
+	}
+
+	return slices.Insert(records, i, r)
+}
+
+func compareWhen(a, b *server.Record) int {
+	return a.Meta.When.AsTime().Compare(b.Meta.When.AsTime())
+}
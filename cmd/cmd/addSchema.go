@@ -22,7 +22,10 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
+	"github.com/xyt-db/xyt/server"
 )
 
 // addSchemaCmd represents the addSchema command
@@ -47,17 +50,156 @@ var addSchemaCmd = &cobra.Command{
 		}
 
 		ints := make(map[string]int32)
-		for _, f := range []string{"xmin", "xmax", "ymin", "ymax"} {
+		for _, f := range []string{"xmin", "xmax", "ymin", "ymax", "zmin", "zmax"} {
 			ints[f], err = cmd.Flags().GetInt32(f)
 			if err != nil {
 				return
 			}
 		}
 
-		return c.addSchema(ds, ints["xmin"], ints["xmax"], ints["ymin"], ints["ymax"])
+		bools := make(map[string]bool)
+		for _, f := range []string{"columnar", "sparse"} {
+			bools[f], err = cmd.Flags().GetBool(f)
+			if err != nil {
+				return
+			}
+		}
+
+		thetaResolution, err := cmd.Flags().GetFloat64("theta-resolution")
+		if err != nil {
+			return
+		}
+
+		transform, err := transformFromFlags(cmd)
+		if err != nil {
+			return
+		}
+
+		geo, err := geoFromFlags(cmd)
+		if err != nil {
+			return
+		}
+
+		// Let the server size the grid from --geo-bounds, unless the
+		// grid's bounds have been explicitly set
+		if geo != nil && geo.Bounds != nil && !cmd.Flags().Changed("xmin") && !cmd.Flags().Changed("xmax") &&
+			!cmd.Flags().Changed("ymin") && !cmd.Flags().Changed("ymax") {
+			ints["xmin"], ints["xmax"], ints["ymin"], ints["ymax"] = 0, 0, 0, 0
+		}
+
+		return c.addSchema(&server.Schema{
+			Dataset:         ds,
+			XMin:            ints["xmin"],
+			XMax:            ints["xmax"],
+			YMin:            ints["ymin"],
+			YMax:            ints["ymax"],
+			ZMin:            ints["zmin"],
+			ZMax:            ints["zmax"],
+			Columnar:        bools["columnar"],
+			Sparse:          bools["sparse"],
+			ThetaResolution: thetaResolution,
+			Transform:       transform,
+			Geo:             geo,
+		})
 	},
 }
 
+// transformFromFlags returns the server.Transform described by the
+// --cell-size, --origin, and --rotation flags, or nil where --cell-size
+// isn't set
+func transformFromFlags(cmd *cobra.Command) (t *server.Transform, err error) {
+	size, err := cmd.Flags().GetFloat64("cell-size")
+	if err != nil || size == 0 {
+		return
+	}
+
+	origin, err := cmd.Flags().GetFloat64Slice("origin")
+	if err != nil {
+		return
+	}
+
+	t = new(server.Transform)
+	t.CellSize = size
+
+	switch len(origin) {
+	case 0:
+	case 2:
+		t.OriginX, t.OriginY = origin[0], origin[1]
+	default:
+		return nil, fmt.Errorf("--origin expects an X,Y location, received %v", origin)
+	}
+
+	t.Rotation, err = cmd.Flags().GetFloat64("rotation")
+
+	return
+}
+
+// geoFromFlags returns the server.Geo described by the --projection,
+// --geo-origin, --utm-zone, --south, and --geo-bounds flags, or nil
+// where --projection isn't set
+func geoFromFlags(cmd *cobra.Command) (g *server.Geo, err error) {
+	projection, err := cmd.Flags().GetString("projection")
+	if err != nil || projection == "" {
+		return
+	}
+
+	g = new(server.Geo)
+
+	switch projection {
+	case "enu":
+		g.Projection = server.Projection_ENU
+
+		var origin []float64
+
+		origin, err = cmd.Flags().GetFloat64Slice("geo-origin")
+		if err != nil {
+			return
+		}
+
+		if len(origin) != 2 {
+			return nil, fmt.Errorf("--geo-origin expects a LAT,LON location, received %v", origin)
+		}
+
+		g.OriginLatitude, g.OriginLongitude = origin[0], origin[1]
+
+	case "utm":
+		g.Projection = server.Projection_UTM
+
+		g.UtmZone, err = cmd.Flags().GetUint32("utm-zone")
+		if err != nil {
+			return
+		}
+
+		g.South, err = cmd.Flags().GetBool("south")
+		if err != nil {
+			return
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown projection %q; expected one of enu, or utm", projection)
+	}
+
+	bounds, err := cmd.Flags().GetFloat64Slice("geo-bounds")
+	if err != nil {
+		return
+	}
+
+	switch len(bounds) {
+	case 0:
+	case 4:
+		g.Bounds = &server.GeoBox{
+			MinLatitude:  bounds[0],
+			MinLongitude: bounds[1],
+			MaxLatitude:  bounds[2],
+			MaxLongitude: bounds[3],
+		}
+	default:
+		return nil, fmt.Errorf("--geo-bounds expects a MINLAT,MINLON,MAXLAT,MAXLON box, received %v", bounds)
+	}
+
+	return
+}
+
 func init() {
 	clientCmd.AddCommand(addSchemaCmd)
 
@@ -66,6 +208,19 @@ func init() {
 	addSchemaCmd.Flags().Int32("xmax", 10, "The highest value for the X column")
 	addSchemaCmd.Flags().Int32("ymin", 0, "The lowest value for the Y column")
 	addSchemaCmd.Flags().Int32("ymax", 10, "The highest value for the Y column")
+	addSchemaCmd.Flags().Int32("zmin", 0, "The lowest value for the Z column; leave --zmin and --zmax unset for a flat dataset")
+	addSchemaCmd.Flags().Int32("zmax", 0, "The highest value for the Z column")
+	addSchemaCmd.Flags().Bool("columnar", false, "Store readings as compressed columns, rather than records")
+	addSchemaCmd.Flags().Bool("sparse", false, "Only allocate locations which are inserted into, for huge, mostly empty, grids")
+	addSchemaCmd.Flags().Float64("theta-resolution", 0, "The size, in degrees, of each theta bucket; defaults to a degree")
+	addSchemaCmd.Flags().Float64("cell-size", 0, "The size of each cell in world units, such as metres; setting this lets records and queries use world coordinates")
+	addSchemaCmd.Flags().Float64Slice("origin", nil, "The world location, as X,Y, of the grid's (0,0); requires --cell-size")
+	addSchemaCmd.Flags().Float64("rotation", 0, "The angle, in degrees anticlockwise, from the world's X axis to the grid's; requires --cell-size")
+	addSchemaCmd.Flags().String("projection", "", "Map world coordinates onto the globe with this projection, one of enu or utm, so records can use latitude and longitude; requires --cell-size, in metres")
+	addSchemaCmd.Flags().Float64Slice("geo-origin", nil, "The latitude and longitude, as LAT,LON, of the world's origin for --projection enu")
+	addSchemaCmd.Flags().Uint32("utm-zone", 0, "The UTM zone, from 1 to 60, for --projection utm")
+	addSchemaCmd.Flags().Bool("south", false, "Use the southern hemisphere's northings for --projection utm")
+	addSchemaCmd.Flags().Float64Slice("geo-bounds", nil, "A box, as MINLAT,MINLON,MAXLAT,MAXLON, the dataset covers; the grid is sized to fit, unless its bounds are set")
 
 	// Here you will define your flags and configuration settings.
 
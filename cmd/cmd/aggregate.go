@@ -0,0 +1,108 @@
+/*
+Copyright © 2025 jspc <james@zero-internet.org.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xyt-db/xyt/server"
+)
+
+// aggregateCmd represents the aggregate command
+var aggregateCmd = &cobra.Command{
+	Use:   "aggregate",
+	Short: "Aggregate data server-side",
+	Long:  "Run count, sum, min, max, mean, stddev, first, and/or last over the records matching a query",
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		addr, err := cmd.Flags().GetString("addr")
+		if err != nil {
+			return
+		}
+
+		c, err := newClient(addr)
+		if err != nil {
+			return
+		}
+
+		q, err := queryFromFlags(cmd)
+		if err != nil {
+			return
+		}
+
+		aggregations, err := aggregationsFromFlags(cmd)
+		if err != nil {
+			return
+		}
+
+		byName, err := cmd.Flags().GetBool("by-name")
+		if err != nil {
+			return
+		}
+
+		return c.aggregate(&server.AggregateRequest{
+			Query:        q,
+			Aggregations: aggregations,
+			ByName:       byName,
+		})
+	},
+}
+
+func init() {
+	clientCmd.AddCommand(aggregateCmd)
+
+	addQueryFlags(aggregateCmd)
+	aggregateCmd.Flags().StringSlice("aggregation", []string{"count", "min", "max", "mean"}, "The aggregations to run; any of count, sum, min, max, mean, stddev, first, last")
+	aggregateCmd.Flags().Bool("by-name", false, "Return results per record name, rather than across all records")
+}
+
+// aggregationsFromFlags parses the --aggregation flag into
+// server.Aggregations
+func aggregationsFromFlags(cmd *cobra.Command) (aggregations []server.Aggregation, err error) {
+	names, err := cmd.Flags().GetStringSlice("aggregation")
+	if err != nil {
+		return
+	}
+
+	aggregations = make([]server.Aggregation, len(names))
+	for i, name := range names {
+		aggregations[i], err = parseAggregation(name)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// parseAggregation turns a case-insensitive aggregation name, such
+// as "mean", into a server.Aggregation
+func parseAggregation(name string) (server.Aggregation, error) {
+	for v, known := range server.Aggregation_name {
+		if strings.EqualFold(name, known) {
+			return server.Aggregation(v), nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown aggregation %q", name)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright © 2025 jspc <james@zero-internet.org.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// alterSchemaCmd represents the alter-schema command
+var alterSchemaCmd = &cobra.Command{
+	Use:   "alter-schema",
+	Short: "Alter an existing dataset's schema",
+	Long:  "Alter an existing dataset's schema, growing its bounds, or changing its frequency, sorting, or retention; only the flags which are set are changed",
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		addr, err := cmd.Flags().GetString("addr")
+		if err != nil {
+			return
+		}
+
+		c, err := newClient(addr)
+		if err != nil {
+			return
+		}
+
+		a := new(server.AlterSchemaRequest)
+
+		a.Dataset, err = cmd.Flags().GetString("dataset")
+		if err != nil {
+			return
+		}
+
+		for f, v := range map[string]**int32{
+			"xmin": &a.XMin,
+			"xmax": &a.XMax,
+			"ymin": &a.YMin,
+			"ymax": &a.YMax,
+			"zmin": &a.ZMin,
+			"zmax": &a.ZMax,
+		} {
+			if !cmd.Flags().Changed(f) {
+				continue
+			}
+
+			var i int32
+
+			i, err = cmd.Flags().GetInt32(f)
+			if err != nil {
+				return
+			}
+
+			*v = &i
+		}
+
+		if cmd.Flags().Changed("frequency") {
+			var f string
+
+			f, err = cmd.Flags().GetString("frequency")
+			if err != nil {
+				return
+			}
+
+			freq, ok := server.Frequency_value["F"+strings.TrimPrefix(f, "F")]
+			if !ok {
+				return fmt.Errorf("unknown frequency %q; expected one of 1Hz, 100Hz, 1000Hz, or 10000Hz", f)
+			}
+
+			a.Frequency = server.Frequency(freq).Enum()
+		}
+
+		if cmd.Flags().Changed("sort-on-insert") {
+			var sort bool
+
+			sort, err = cmd.Flags().GetBool("sort-on-insert")
+			if err != nil {
+				return
+			}
+
+			a.SortOnInsert = &sort
+		}
+
+		if cmd.Flags().Changed("retention") {
+			var retention time.Duration
+
+			retention, err = cmd.Flags().GetDuration("retention")
+			if err != nil {
+				return
+			}
+
+			a.Retention = durationpb.New(retention)
+		}
+
+		return c.alterSchema(a)
+	},
+}
+
+func init() {
+	clientCmd.AddCommand(alterSchemaCmd)
+
+	alterSchemaCmd.Flags().String("dataset", "", "The dataset to alter")
+	alterSchemaCmd.Flags().Int32("xmin", 0, "The new lowest value for the X column, which may only be lowered")
+	alterSchemaCmd.Flags().Int32("xmax", 0, "The new highest value for the X column, which may only be raised")
+	alterSchemaCmd.Flags().Int32("ymin", 0, "The new lowest value for the Y column, which may only be lowered")
+	alterSchemaCmd.Flags().Int32("ymax", 0, "The new highest value for the Y column, which may only be raised")
+	alterSchemaCmd.Flags().Int32("zmin", 0, "The new lowest value for the Z column, which may only be lowered")
+	alterSchemaCmd.Flags().Int32("zmax", 0, "The new highest value for the Z column, which may only be raised")
+	alterSchemaCmd.Flags().String("frequency", "", "The expected rate of inserts per location, one of 1Hz, 100Hz, 1000Hz, or 10000Hz")
+	alterSchemaCmd.Flags().Bool("sort-on-insert", false, "Keep each location's records sorted by time; turning this on sorts existing records")
+	alterSchemaCmd.Flags().Duration("retention", 0, "How long records are kept for; 0 keeps them forever")
+}
@@ -25,6 +25,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 
@@ -32,6 +33,8 @@ import (
 	"github.com/xyt-db/xyt/server"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -66,24 +69,22 @@ func newClient(addr string) (c client, err error) {
 	return
 }
 
-func (c client) addSchema(name string, xmin, xmax, ymin, ymax int32) (err error) {
+// addSchema adds s, filling in some sensible defaults first
+func (c client) addSchema(s *server.Schema) (err error) {
 	// Create a semi-optimised schema; it doesn't have to be awesome,
 	// there are other ways of doing that
-	_, err = c.AddSchema(context.Background(), &server.Schema{
-		Dataset:             name,
-		XMin:                xmin,
-		XMax:                xmax,
-		YMin:                ymin,
-		YMax:                ymax,
-		Frequency:           server.Frequency_F100Hz,
-		SortOnInsert:        true,
-		LazyInitialAllocate: true,
-	})
+	s.Frequency = server.Frequency_F100Hz
+	s.SortOnInsert = true
+	s.LazyInitialAllocate = true
+
+	_, err = c.AddSchema(context.Background(), s)
 
 	return
 }
 
-func (c client) insert(dataset, name string, value float64, x, y, t int32) (err error) {
+// insert sends a single record; theta, when set, is sent alongside
+// t as a precise heading, and world and geo, when set, alongside x and y
+func (c client) insert(dataset, name string, value float64, x, y, z, t int32, theta *float64, world *server.WorldPoint, geo *server.GeoPoint) (err error) {
 	cc, err := c.Insert(context.Background())
 	if err != nil {
 		return
@@ -98,7 +99,11 @@ func (c client) insert(dataset, name string, value float64, x, y, t int32) (err
 		Value:   value,
 		X:       x,
 		Y:       y,
+		Z:       z,
 		T:       t,
+		Theta:   theta,
+		World:   world,
+		Geo:     geo,
 	})
 	if err != nil {
 		return
@@ -109,14 +114,27 @@ func (c client) insert(dataset, name string, value float64, x, y, t int32) (err
 	return
 }
 
-func (c client) query(dataset string) (err error) {
-	cs, err := c.Select(context.Background(), &server.Query{
-		Dataset: dataset,
-	})
+func (c client) query(q *server.Query) (err error) {
+	cs, err := c.Select(context.Background(), q)
 	if err != nil {
 		return
 	}
 
+	return printRecords(cs)
+}
+
+func (c client) subscribe(sr *server.SubscribeRequest) (err error) {
+	cs, err := c.Subscribe(context.Background(), sr)
+	if err != nil {
+		return
+	}
+
+	return printRecords(cs)
+}
+
+// printRecords prints each record from cs, as json, until the
+// server stops sending them
+func printRecords(cs grpc.ServerStreamingClient[server.Record]) (err error) {
 	defer func() {
 		cerr := cs.CloseSend()
 
@@ -148,6 +166,222 @@ func (c client) query(dataset string) (err error) {
 	}
 }
 
+func (c client) aggregate(ar *server.AggregateRequest) (err error) {
+	resp, err := c.Aggregate(context.Background(), ar)
+	if err != nil {
+		return
+	}
+
+	// encoding/json refuses to encode NaN, which is what empty
+	// aggregations return, whereas protojson handles it fine
+	var b []byte
+	for _, result := range resp.Results {
+		b, err = protojson.Marshal(result)
+		if err != nil {
+			return
+		}
+
+		_, err = fmt.Println(string(b))
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (c client) heatmap(hr *server.HeatmapRequest) (err error) {
+	resp, err := c.Heatmap(context.Background(), hr)
+	if err != nil {
+		return
+	}
+
+	b, err := protojson.Marshal(resp)
+	if err != nil {
+		return
+	}
+
+	_, err = fmt.Println(string(b))
+
+	return
+}
+
+func (c client) timeSeries(tr *server.TimeSeriesRequest) (err error) {
+	resp, err := c.TimeSeries(context.Background(), tr)
+	if err != nil {
+		return
+	}
+
+	b, err := protojson.Marshal(resp)
+	if err != nil {
+		return
+	}
+
+	_, err = fmt.Println(string(b))
+
+	return
+}
+
+func (c client) snapshot(w io.Writer) (err error) {
+	cs, err := c.Snapshot(context.Background(), new(emptypb.Empty))
+	if err != nil {
+		return
+	}
+
+	var chunk *server.SnapshotChunk
+	for {
+		chunk, err = cs.Recv()
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+
+			return
+		}
+
+		_, err = w.Write(chunk.Data)
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (c client) exportGeoJSON(q *server.Query, w io.Writer) (err error) {
+	cs, err := c.ExportGeoJSON(context.Background(), q)
+	if err != nil {
+		return
+	}
+
+	var chunk *server.ExportChunk
+	for {
+		chunk, err = cs.Recv()
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+
+			return
+		}
+
+		_, err = w.Write(chunk.Data)
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (c client) truncate(tr *server.TruncateRequest) (err error) {
+	resp, err := c.Truncate(context.Background(), tr)
+	if err != nil {
+		return
+	}
+
+	_, err = fmt.Printf("removed %d records\n", resp.Removed)
+
+	return
+}
+
+func (c client) alterSchema(a *server.AlterSchemaRequest) (err error) {
+	_, err = c.AlterSchema(context.Background(), a)
+
+	return
+}
+
+func (c client) deleteDataset(dataset string) (err error) {
+	_, err = c.DeleteDataset(context.Background(), &server.DeleteDatasetRequest{Dataset: dataset})
+
+	return
+}
+
+func (c client) delete(dr *server.DeleteRequest) (err error) {
+	resp, err := c.Delete(context.Background(), dr)
+	if err != nil {
+		return
+	}
+
+	if dr.DryRun {
+		_, err = fmt.Printf("would remove %d records\n", resp.Removed)
+
+		return
+	}
+
+	_, err = fmt.Printf("removed %d records\n", resp.Removed)
+
+	return
+}
+
+func (c client) addZone(z *server.Zone) (err error) {
+	_, err = c.AddZone(context.Background(), z)
+
+	return
+}
+
+func (c client) listZones(dataset string) (err error) {
+	resp, err := c.ListZones(context.Background(), &server.ListZonesRequest{Dataset: dataset})
+	if err != nil {
+		return
+	}
+
+	var b []byte
+	for _, z := range resp.Zones {
+		b, err = protojson.Marshal(z)
+		if err != nil {
+			return
+		}
+
+		_, err = fmt.Println(string(b))
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (c client) deleteZone(dataset, name string) (err error) {
+	_, err = c.DeleteZone(context.Background(), &server.DeleteZoneRequest{Dataset: dataset, Name: name})
+
+	return
+}
+
+func (c client) restore(r io.Reader) (err error) {
+	cc, err := c.Restore(context.Background())
+	if err != nil {
+		return
+	}
+
+	buf := make([]byte, 64*1024)
+	for {
+		var n int
+
+		n, err = r.Read(buf)
+		if n > 0 {
+			// An io.EOF here means the server has stopped listening; the
+			// actual reason why is returned from CloseAndRecv
+			serr := cc.Send(&server.SnapshotChunk{Data: buf[:n]})
+			if serr == io.EOF {
+				break
+			}
+
+			if serr != nil {
+				return serr
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return
+		}
+	}
+
+	_, err = cc.CloseAndRecv()
+
+	return
+}
+
 func (c client) version() (ref, user, when string, err error) {
 	v, err := c.Version(context.Background(), nil)
 	if err != nil {
@@ -0,0 +1,57 @@
+/*
+Copyright © 2025 jspc <james@zero-internet.org.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// deleteDatasetCmd represents the delete-dataset command
+var deleteDatasetCmd = &cobra.Command{
+	Use:   "delete-dataset",
+	Short: "Delete a dataset",
+	Long:  "Delete a dataset entirely, along with its schema, records, rollups, indices, zones, and stats",
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		addr, err := cmd.Flags().GetString("addr")
+		if err != nil {
+			return
+		}
+
+		c, err := newClient(addr)
+		if err != nil {
+			return
+		}
+
+		dataset, err := cmd.Flags().GetString("dataset")
+		if err != nil {
+			return
+		}
+
+		return c.deleteDataset(dataset)
+	},
+}
+
+func init() {
+	clientCmd.AddCommand(deleteDatasetCmd)
+
+	deleteDatasetCmd.Flags().String("dataset", "", "The dataset to delete")
+}
@@ -0,0 +1,83 @@
+/*
+Copyright © 2025 jspc <james@zero-internet.org.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// exportGeoJSONCmd represents the export-geojson command
+var exportGeoJSONCmd = &cobra.Command{
+	Use:   "export-geojson",
+	Short: "Export matching records as GeoJSON",
+	Long:  "Export the records a query matches, from a dataset with a Geo projection, as a GeoJSON FeatureCollection of points",
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		addr, err := cmd.Flags().GetString("addr")
+		if err != nil {
+			return
+		}
+
+		c, err := newClient(addr)
+		if err != nil {
+			return
+		}
+
+		q, err := queryFromFlags(cmd)
+		if err != nil {
+			return
+		}
+
+		fn, err := cmd.Flags().GetString("file")
+		if err != nil {
+			return
+		}
+
+		if fn == "" {
+			return c.exportGeoJSON(q, os.Stdout)
+		}
+
+		// #nosec: G304
+		f, err := os.Create(fn)
+		if err != nil {
+			return
+		}
+
+		err = c.exportGeoJSON(q, f)
+		if err != nil {
+			f.Close()
+
+			return
+		}
+
+		return f.Close()
+	},
+}
+
+func init() {
+	clientCmd.AddCommand(exportGeoJSONCmd)
+
+	addQueryFlags(exportGeoJSONCmd)
+
+	exportGeoJSONCmd.Flags().StringP("file", "f", "", "The file to write GeoJSON to; defaults to stdout")
+}
@@ -0,0 +1,89 @@
+/*
+Copyright © 2025 jspc <james@zero-internet.org.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/xyt-db/xyt/server"
+)
+
+// heatmapCmd represents the heatmap command
+var heatmapCmd = &cobra.Command{
+	Use:   "heatmap",
+	Short: "Aggregate a metric across a whole dataset",
+	Long:  "Return a dense grid with a single aggregated value of a metric per location, optionally downsampled",
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		addr, err := cmd.Flags().GetString("addr")
+		if err != nil {
+			return
+		}
+
+		c, err := newClient(addr)
+		if err != nil {
+			return
+		}
+
+		strings := make(map[string]string)
+		for _, f := range []string{"dataset", "name", "aggregation"} {
+			strings[f], err = cmd.Flags().GetString(f)
+			if err != nil {
+				return
+			}
+		}
+
+		aggregation, err := parseAggregation(strings["aggregation"])
+		if err != nil {
+			return
+		}
+
+		downsample, err := cmd.Flags().GetUint32("downsample")
+		if err != nil {
+			return
+		}
+
+		tr, err := timeRangeFromFlags(cmd)
+		if err != nil {
+			return
+		}
+
+		hr := &server.HeatmapRequest{
+			Dataset:     strings["dataset"],
+			Name:        strings["name"],
+			TimeRange:   tr,
+			Aggregation: aggregation,
+			Downsample:  downsample,
+		}
+
+		return c.heatmap(hr)
+	},
+}
+
+func init() {
+	clientCmd.AddCommand(heatmapCmd)
+
+	heatmapCmd.Flags().String("dataset", "", "The dataset to build a heatmap of")
+	heatmapCmd.Flags().String("name", "", "The name of the metric to build a heatmap of")
+	heatmapCmd.Flags().String("start", "", "Only consider records from this time onwards (RFC3339)")
+	heatmapCmd.Flags().String("end", "", "Only consider records up to this time (RFC3339); defaults to now when --start is set")
+	heatmapCmd.Flags().String("aggregation", "mean", "The aggregation to run per location; one of count, sum, min, max, mean, stddev, first, last")
+	heatmapCmd.Flags().Uint32("downsample", 1, "The number of cells, along each axis, to group into each value")
+}
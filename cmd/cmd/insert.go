@@ -22,7 +22,10 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
+	"github.com/xyt-db/xyt/server"
 )
 
 // insertCmd represents the insert command
@@ -50,7 +53,7 @@ var insertCmd = &cobra.Command{
 		}
 
 		ints := make(map[string]int32)
-		for _, f := range []string{"x", "y", "t"} {
+		for _, f := range []string{"x", "y", "z", "t"} {
 			ints[f], err = cmd.Flags().GetInt32(f)
 			if err != nil {
 				return
@@ -62,7 +65,47 @@ var insertCmd = &cobra.Command{
 			return
 		}
 
-		return c.insert(strings["dataset"], strings["name"], value, ints["x"], ints["y"], ints["t"])
+		var theta *float64
+		if cmd.Flags().Changed("theta") {
+			theta = new(float64)
+
+			*theta, err = cmd.Flags().GetFloat64("theta")
+			if err != nil {
+				return
+			}
+		}
+
+		world, err := cmd.Flags().GetFloat64Slice("world")
+		if err != nil {
+			return
+		}
+
+		var wp *server.WorldPoint
+
+		switch len(world) {
+		case 0:
+		case 2:
+			wp = &server.WorldPoint{X: world[0], Y: world[1]}
+		default:
+			return fmt.Errorf("--world expects an X,Y location, received %v", world)
+		}
+
+		geo, err := cmd.Flags().GetFloat64Slice("geo")
+		if err != nil {
+			return
+		}
+
+		var gp *server.GeoPoint
+
+		switch len(geo) {
+		case 0:
+		case 2:
+			gp = &server.GeoPoint{Latitude: geo[0], Longitude: geo[1]}
+		default:
+			return fmt.Errorf("--geo expects a LAT,LON location, received %v", geo)
+		}
+
+		return c.insert(strings["dataset"], strings["name"], value, ints["x"], ints["y"], ints["z"], ints["t"], theta, wp, gp)
 	},
 }
 
@@ -74,7 +117,11 @@ func init() {
 	insertCmd.Flags().Float64("value", 0, "The value of this metric")
 	insertCmd.Flags().Int32P("x", "x", 0, "The X position")
 	insertCmd.Flags().Int32P("y", "y", 0, "The Y position")
-	insertCmd.Flags().Int32P("t", "t", 0, "The Theta position (in degs)")
+	insertCmd.Flags().Int32P("z", "z", 0, "The Z position, for datasets with a Z axis")
+	insertCmd.Flags().Int32P("t", "t", 0, "The Theta position (in the dataset's theta buckets, which default to degs)")
+	insertCmd.Flags().Float64Slice("world", nil, "A world location, as X,Y, quantised into the dataset's cells; overrides -x and -y")
+	insertCmd.Flags().Float64Slice("geo", nil, "A latitude and longitude, as LAT,LON, projected into the dataset's cells; overrides --world, -x, and -y")
+	insertCmd.Flags().Float64("theta", 0, "A precise Theta position (in degs), binned into the dataset's theta buckets; overrides -t")
 
 	// Here you will define your flags and configuration settings.
 
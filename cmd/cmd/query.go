@@ -22,7 +22,13 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // queryCmd represents the query command
@@ -41,19 +47,19 @@ var queryCmd = &cobra.Command{
 			return
 		}
 
-		ds, err := cmd.Flags().GetString("dataset")
+		q, err := queryFromFlags(cmd)
 		if err != nil {
 			return
 		}
 
-		return c.query(ds)
+		return c.query(q)
 	},
 }
 
 func init() {
 	clientCmd.AddCommand(queryCmd)
 
-	queryCmd.Flags().String("dataset", "", "The dataset to query")
+	addQueryFlags(queryCmd)
 
 	// Here you will define your flags and configuration settings.
 
@@ -65,3 +71,262 @@ func init() {
 	// is called directly, e.g.:
 	// queryCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
+
+// addQueryFlags adds the flags used to build a server.Query to cmd,
+// for use with queryFromFlags
+func addQueryFlags(cmd *cobra.Command) {
+	cmd.Flags().String("dataset", "", "The dataset to query")
+	cmd.Flags().StringSlice("name", nil, "Only return records with this name; may be repeated, or comma separated")
+	cmd.Flags().String("index-key", "", "Only return records with this index key (requires --index-value)")
+	cmd.Flags().String("index-value", "", "Only return records where --index-key has this value")
+	cmd.Flags().String("start", "", "Only return records from this time onwards (RFC3339)")
+	cmd.Flags().String("end", "", "Only return records up to this time (RFC3339); defaults to now when --start is set")
+	cmd.Flags().Int32Slice("theta", nil, "Only return records with a theta from START up to END, as START,END (in the dataset's theta buckets, which default to degs); wraps around a full turn where START is greater than END")
+	cmd.Flags().Int32("heading", 0, "Only return records with a theta within --tolerance of this heading (in the dataset's theta buckets, which default to degs)")
+	cmd.Flags().Uint32("tolerance", 0, "How far either side of --heading to match (in the dataset's theta buckets, which default to degs)")
+	cmd.Flags().Int32Slice("z", nil, "Only return records at this Z, or from START up to END, as START,END")
+	cmd.Flags().Int32Slice("near", nil, "An X,Y location for --radius and --nearest to search around")
+	cmd.Flags().Float64("radius", 0, "Only return records within this many locations of --near, nearest first")
+	cmd.Flags().Uint32("nearest", 0, "Only return records from this many of the closest locations to --near which hold any, nearest first; --radius limits how far to look")
+	cmd.Flags().StringArray("polygon", nil, "Only return records within this polygon, as space separated X,Y vertices, with any holes following a '|'; may be repeated")
+	cmd.Flags().StringArray("world-polygon", nil, "As per --polygon, but in world coordinates, matching cells whose centres fall within it; may be repeated")
+	cmd.Flags().StringSlice("zone", nil, "Only return records within this named zone; may be repeated, or comma separated")
+}
+
+// queryFromFlags builds a server.Query from the flags added
+// by addQueryFlags
+func queryFromFlags(cmd *cobra.Command) (q *server.Query, err error) {
+	strings := make(map[string]string)
+	for _, f := range []string{"dataset", "index-key", "index-value"} {
+		strings[f], err = cmd.Flags().GetString(f)
+		if err != nil {
+			return
+		}
+	}
+
+	names, err := cmd.Flags().GetStringSlice("name")
+	if err != nil {
+		return
+	}
+
+	zones, err := cmd.Flags().GetStringSlice("zone")
+	if err != nil {
+		return
+	}
+
+	q = &server.Query{
+		Dataset:    strings["dataset"],
+		IndexKey:   strings["index-key"],
+		IndexValue: strings["index-value"],
+		Names:      names,
+		Zones:      zones,
+	}
+
+	err = thetaFromFlags(cmd, q)
+	if err != nil {
+		return
+	}
+
+	err = spatialFromFlags(cmd, q)
+	if err != nil {
+		return
+	}
+
+	z, err := cmd.Flags().GetInt32Slice("z")
+	if err != nil {
+		return
+	}
+
+	switch len(z) {
+	case 0:
+	case 1:
+		q.Z = &server.Query_ZValue{ZValue: z[0]}
+	case 2:
+		q.Z = &server.Query_ZRange{ZRange: &server.QueryRange{Start: z[0], End: z[1]}}
+	default:
+		return nil, fmt.Errorf("--z expects a single Z, or a START,END range, received %v", z)
+	}
+
+	q.Polygons, err = polygonsFromFlags(cmd, "polygon")
+	if err != nil {
+		return
+	}
+
+	q.WorldPolygons, err = polygonsFromFlags(cmd, "world-polygon")
+	if err != nil {
+		return
+	}
+
+	tr, err := timeRangeFromFlags(cmd)
+	if err != nil || tr == nil {
+		return
+	}
+
+	q.Time = &server.Query_TimeRange{TimeRange: tr}
+
+	return
+}
+
+// thetaFromFlags sets q's T from either the --theta flag,
+// or the --heading and --tolerance flags
+func thetaFromFlags(cmd *cobra.Command, q *server.Query) (err error) {
+	theta, err := cmd.Flags().GetInt32Slice("theta")
+	if err != nil {
+		return
+	}
+
+	heading := cmd.Flags().Changed("heading")
+
+	switch {
+	case len(theta) > 0 && heading:
+		return fmt.Errorf("--theta and --heading can't be used together")
+
+	case len(theta) > 0:
+		if len(theta) != 2 {
+			return fmt.Errorf("--theta expects a START,END range, received %v", theta)
+		}
+
+		q.T = &server.Query_TRange{TRange: &server.QueryRange{Start: theta[0], End: theta[1]}}
+
+	case heading:
+		h, err := cmd.Flags().GetInt32("heading")
+		if err != nil {
+			return err
+		}
+
+		tolerance, err := cmd.Flags().GetUint32("tolerance")
+		if err != nil {
+			return err
+		}
+
+		q.T = &server.Query_THeading{THeading: &server.Heading{Heading: h, Tolerance: tolerance}}
+	}
+
+	return
+}
+
+// spatialFromFlags sets q's Within or Nearest from the --near,
+// --radius, and --nearest flags, where --near is set
+func spatialFromFlags(cmd *cobra.Command, q *server.Query) (err error) {
+	near, err := cmd.Flags().GetInt32Slice("near")
+	if err != nil || len(near) == 0 {
+		return
+	}
+
+	if len(near) != 2 {
+		return fmt.Errorf("--near expects an X,Y location, received %v", near)
+	}
+
+	radius, err := cmd.Flags().GetFloat64("radius")
+	if err != nil {
+		return
+	}
+
+	k, err := cmd.Flags().GetUint32("nearest")
+	if err != nil {
+		return
+	}
+
+	if k > 0 {
+		q.Nearest = &server.Nearest{X: near[0], Y: near[1], K: k, MaxDistance: radius}
+
+		return
+	}
+
+	q.Within = &server.Circle{X: near[0], Y: near[1], Radius: radius}
+
+	return
+}
+
+// polygonsFromFlags parses each value passed to flag, such as:
+//
+//	0,0 10,0 10,10 0,10 | 4,4 6,4 6,6 4,6
+//
+// into a server.Polygon, where the first ring is the polygon's
+// outline and any subsequent rings are holes
+func polygonsFromFlags(cmd *cobra.Command, flag string) (polygons []*server.Polygon, err error) {
+	flags, err := cmd.Flags().GetStringArray(flag)
+	if err != nil {
+		return
+	}
+
+	for _, f := range flags {
+		var p *server.Polygon
+
+		p, err = parsePolygon(f)
+		if err != nil {
+			return
+		}
+
+		polygons = append(polygons, p)
+	}
+
+	return
+}
+
+// parsePolygon parses a single polygon, as per polygonsFromFlags
+func parsePolygon(s string) (p *server.Polygon, err error) {
+	p = new(server.Polygon)
+
+	for i, rs := range strings.Split(s, "|") {
+		r := new(server.Ring)
+
+		for _, vs := range strings.Fields(rs) {
+			v := new(server.Vertex)
+
+			_, err = fmt.Sscanf(vs, "%g,%g", &v.X, &v.Y)
+			if err != nil {
+				return nil, fmt.Errorf("polygons expect X,Y vertices, received %q", vs)
+			}
+
+			r.Vertices = append(r.Vertices, v)
+		}
+
+		if i == 0 {
+			p.Outline = r
+		} else {
+			p.Holes = append(p.Holes, r)
+		}
+	}
+
+	return
+}
+
+// timeRangeFromFlags returns a server.TimeRange built from the --start
+// and --end flags, or nil where neither is set
+func timeRangeFromFlags(cmd *cobra.Command) (tr *server.TimeRange, err error) {
+	s, err := cmd.Flags().GetString("start")
+	if err != nil {
+		return
+	}
+
+	e, err := cmd.Flags().GetString("end")
+	if err != nil {
+		return
+	}
+
+	if s == "" && e == "" {
+		return
+	}
+
+	var start time.Time
+	if s != "" {
+		start, err = time.Parse(time.RFC3339, s)
+		if err != nil {
+			return
+		}
+	}
+
+	end := time.Now()
+	if e != "" {
+		end, err = time.Parse(time.RFC3339, e)
+		if err != nil {
+			return
+		}
+	}
+
+	return &server.TimeRange{
+		Start: timestamppb.New(start),
+		End:   timestamppb.New(end),
+	}, nil
+}
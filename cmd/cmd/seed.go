@@ -50,6 +50,11 @@ var seedCmd = &cobra.Command{
 			return
 		}
 
+		columnar, err := cmd.Flags().GetBool("columnar")
+		if err != nil {
+			return
+		}
+
 		ds := "superduperdataset"
 		_, err = c.AddSchema(context.Background(), &server.Schema{
 			Dataset:             ds,
@@ -58,6 +63,7 @@ var seedCmd = &cobra.Command{
 			Frequency:           server.Frequency_F1000Hz,
 			SortOnInsert:        false,
 			LazyInitialAllocate: true,
+			Columnar:            columnar,
 		})
 		if err != nil {
 			return
@@ -92,6 +98,9 @@ var seedCmd = &cobra.Command{
 								Labels: map[string]string{
 									"robot": "robo-001",
 								},
+								Indices: map[string]string{
+									"robot": "robo-001",
+								},
 							},
 							X:       x,
 							Y:       y,
@@ -131,6 +140,8 @@ var seedCmd = &cobra.Command{
 func init() {
 	clientCmd.AddCommand(seedCmd)
 
+	seedCmd.Flags().Bool("columnar", false, "Store the seeded dataset as compressed columns, rather than records")
+
 	// Here you will define your flags and configuration settings.
 
 	// Cobra supports Persistent Flags which will work for this command
@@ -28,6 +28,7 @@ import (
 	"os"
 	"os/user"
 	"runtime"
+	"time"
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_zap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
@@ -67,11 +68,28 @@ var serverCmd = &cobra.Command{
 			return
 		}
 
-		s, err := newServer()
+		walPath, err := cmd.Flags().GetString("wal")
 		if err != nil {
 			return
 		}
 
+		walOpts, err := walOptions(cmd)
+		if err != nil {
+			return
+		}
+
+		s, err := newServer(walPath, walOpts)
+		if err != nil {
+			return
+		}
+
+		janitorInterval, err := cmd.Flags().GetDuration("janitor-interval")
+		if err != nil {
+			return
+		}
+
+		go s.database.RunJanitor(cmd.Context(), janitorInterval)
+
 		lis, err := net.Listen("tcp", l)
 		if err != nil {
 			return
@@ -99,15 +117,55 @@ func init() {
 	rootCmd.AddCommand(serverCmd)
 
 	serverCmd.PersistentFlags().StringP("listen", "l", "localhost:8888", "Address on which to create listener")
+	serverCmd.PersistentFlags().String("wal", "", "Path to a write-ahead log, replayed on startup; when empty, data is lost on exit")
+	serverCmd.PersistentFlags().String("wal-sync", "always", "How often to fsync the write-ahead log: always, batch, or interval")
+	serverCmd.PersistentFlags().Int("wal-batch-size", 1000, "Number of writes between fsyncs when --wal-sync=batch")
+	serverCmd.PersistentFlags().Duration("wal-sync-interval", time.Second, "Time between fsyncs when --wal-sync=interval")
+	serverCmd.PersistentFlags().Duration("janitor-interval", xyt.DefaultJanitorInterval, "Time between dropping records which have outlived their dataset's retention; 0 turns this off")
 }
 
-func newServer() (s *Server, err error) {
+func walOptions(cmd *cobra.Command) (opts xyt.WALOptions, err error) {
+	policy, err := cmd.Flags().GetString("wal-sync")
+	if err != nil {
+		return
+	}
+
+	opts.Policy, err = xyt.ParseSyncPolicy(policy)
+	if err != nil {
+		return
+	}
+
+	opts.BatchSize, err = cmd.Flags().GetInt("wal-batch-size")
+	if err != nil {
+		return
+	}
+
+	opts.Interval, err = cmd.Flags().GetDuration("wal-sync-interval")
+
+	return
+}
+
+func newServer(walPath string, walOpts xyt.WALOptions) (s *Server, err error) {
 	s = new(Server)
 	s.database, err = xyt.New()
 	if err != nil {
 		return
 	}
 
+	if walPath != "" {
+		var w *xyt.WAL
+
+		w, err = xyt.OpenWAL(walPath, walOpts)
+		if err != nil {
+			return
+		}
+
+		err = s.database.UseWAL(w)
+		if err != nil {
+			return
+		}
+	}
+
 	s.hostname, err = os.Hostname()
 	if err != nil {
 		return
@@ -169,6 +227,191 @@ func (s *Server) Select(q *server.Query, ss grpc.ServerStreamingServer[server.Re
 	return
 }
 
+func (s *Server) Subscribe(sr *server.SubscribeRequest, ss grpc.ServerStreamingServer[server.Record]) (err error) {
+	sub, err := s.database.Subscribe(sr)
+	if err != nil {
+		return
+	}
+
+	defer sub.Close()
+
+	var record *server.Record
+	for {
+		record, err = sub.Next(ss.Context())
+		if err != nil {
+			return
+		}
+
+		err = ss.Send(record)
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+
+			return
+		}
+	}
+}
+
+func (s *Server) Aggregate(_ context.Context, ar *server.AggregateRequest) (resp *server.AggregateResponse, err error) {
+	results, err := s.database.Aggregate(ar.Query, ar.Aggregations, ar.ByName)
+	if err != nil {
+		return
+	}
+
+	return &server.AggregateResponse{Results: results}, nil
+}
+
+func (s *Server) Heatmap(_ context.Context, hr *server.HeatmapRequest) (*server.HeatmapResponse, error) {
+	return s.database.Heatmap(hr)
+}
+
+func (s *Server) TimeSeries(_ context.Context, tr *server.TimeSeriesRequest) (*server.TimeSeriesResponse, error) {
+	series, err := s.database.TimeSeries(tr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &server.TimeSeriesResponse{Series: series}, nil
+}
+
+func (s *Server) Truncate(_ context.Context, tr *server.TruncateRequest) (*server.TruncateResponse, error) {
+	removed, err := s.database.Truncate(tr.Dataset, tr.Before.AsTime())
+	if err != nil {
+		return nil, err
+	}
+
+	return &server.TruncateResponse{Removed: removed}, nil
+}
+
+func (s *Server) AlterSchema(_ context.Context, a *server.AlterSchemaRequest) (_ *emptypb.Empty, err error) {
+	err = s.database.AlterSchema(a)
+
+	return
+}
+
+func (s *Server) DeleteDataset(_ context.Context, dr *server.DeleteDatasetRequest) (_ *emptypb.Empty, err error) {
+	err = s.database.DeleteDataset(dr.Dataset)
+
+	return
+}
+
+func (s *Server) Delete(_ context.Context, dr *server.DeleteRequest) (*server.DeleteResponse, error) {
+	removed, err := s.database.Delete(dr.Query, dr.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return &server.DeleteResponse{Removed: removed}, nil
+}
+
+func (s *Server) AddZone(_ context.Context, z *server.Zone) (_ *emptypb.Empty, err error) {
+	err = s.database.AddZone(z)
+
+	return
+}
+
+func (s *Server) ListZones(_ context.Context, lr *server.ListZonesRequest) (*server.ListZonesResponse, error) {
+	zones, err := s.database.Zones(lr.Dataset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &server.ListZonesResponse{Zones: zones}, nil
+}
+
+func (s *Server) DeleteZone(_ context.Context, dr *server.DeleteZoneRequest) (_ *emptypb.Empty, err error) {
+	err = s.database.DeleteZone(dr.Dataset, dr.Name)
+
+	return
+}
+
+// snapshotChunkSize is the largest amount of snapshot data sent
+// in a single message
+const snapshotChunkSize = 64 * 1024
+
+func (s *Server) Snapshot(_ *emptypb.Empty, ss grpc.ServerStreamingServer[server.SnapshotChunk]) (err error) {
+	return streamChunks(s.database.Snapshot, func(b []byte) error {
+		return ss.Send(&server.SnapshotChunk{Data: b})
+	})
+}
+
+func (s *Server) ExportGeoJSON(q *server.Query, ss grpc.ServerStreamingServer[server.ExportChunk]) (err error) {
+	return streamChunks(func(w io.Writer) error {
+		return s.database.GeoJSON(q, w)
+	}, func(b []byte) error {
+		return ss.Send(&server.ExportChunk{Data: b})
+	})
+}
+
+// streamChunks runs write in the background, passing everything it
+// writes to send in chunks of up to snapshotChunkSize bytes
+func streamChunks(write func(io.Writer) error, send func([]byte) error) (err error) {
+	pr, pw := io.Pipe()
+
+	go func() {
+		pw.CloseWithError(write(pw))
+	}()
+
+	// Ensure the writing goroutine is unblocked should
+	// the client go away mid-stream
+	defer pr.Close()
+
+	buf := make([]byte, snapshotChunkSize)
+	for {
+		var n int
+
+		n, err = pr.Read(buf)
+		if n > 0 {
+			serr := send(buf[:n])
+			if serr != nil {
+				return serr
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) Restore(cs grpc.ClientStreamingServer[server.SnapshotChunk, emptypb.Empty]) (err error) {
+	err = s.database.Restore(&snapshotReader{cs: cs})
+	if err != nil {
+		return
+	}
+
+	return cs.SendAndClose(new(emptypb.Empty))
+}
+
+// snapshotReader turns a stream of SnapshotChunks into an io.Reader
+type snapshotReader struct {
+	cs  grpc.ClientStreamingServer[server.SnapshotChunk, emptypb.Empty]
+	buf []byte
+}
+
+func (r *snapshotReader) Read(p []byte) (n int, err error) {
+	for len(r.buf) == 0 {
+		var chunk *server.SnapshotChunk
+
+		chunk, err = r.cs.Recv()
+		if err != nil {
+			return
+		}
+
+		r.buf = chunk.Data
+	}
+
+	n = copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return
+}
+
 func (s *Server) Version(context.Context, *emptypb.Empty) (*server.VersionMessage, error) {
 	return &server.VersionMessage{
 		Ref:       Ref,
@@ -183,21 +426,23 @@ func (s *Server) Stats(context.Context, *emptypb.Empty) (*server.StatsMessage, e
 		uptime = 0
 	}
 
-	stats := s.database.Stats()
-
 	ms := new(runtime.MemStats)
 	runtime.ReadMemStats(ms)
 
 	sm := make(map[string]*server.SchemaStats)
-	for ds, schema := range s.database.Datasets() {
-		ss := stats[ds]
+	for ds, summary := range s.database.Summaries() {
+		ss := summary.Stats
 
 		sm[ds] = &server.SchemaStats{
-			Schema:      schema,
-			Records:     ss.RecordCount,
-			TotalSize:   ss.TotalSize,
-			AverageSize: ss.TotalSize / uint64(ss.RecordCount),
-			Fields:      ss.Fields,
+			Schema:    summary.Schema,
+			Records:   ss.RecordCount,
+			TotalSize: ss.TotalSize,
+			Fields:    ss.Fields,
+		}
+
+		// Freshly created datasets have no records to average
+		if ss.RecordCount > 0 {
+			sm[ds].AverageSize = ss.TotalSize / uint64(ss.RecordCount)
 		}
 	}
 
@@ -0,0 +1,103 @@
+/*
+Copyright © 2025 jspc <james@zero-internet.org.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xyt-db/xyt/server"
+)
+
+// subscribeCmd represents the subscribe command
+var subscribeCmd = &cobra.Command{
+	Use:   "subscribe",
+	Short: "Stream new records as they're inserted",
+	Long:  "Print every newly inserted record matching a query, optionally after first printing every record which already matches",
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		addr, err := cmd.Flags().GetString("addr")
+		if err != nil {
+			return
+		}
+
+		c, err := newClient(addr)
+		if err != nil {
+			return
+		}
+
+		q, err := queryFromFlags(cmd)
+		if err != nil {
+			return
+		}
+
+		replay, err := cmd.Flags().GetBool("replay")
+		if err != nil {
+			return
+		}
+
+		bufferSize, err := cmd.Flags().GetUint32("buffer-size")
+		if err != nil {
+			return
+		}
+
+		p, err := cmd.Flags().GetString("slow-policy")
+		if err != nil {
+			return
+		}
+
+		policy, err := parseSlowSubscriberPolicy(p)
+		if err != nil {
+			return
+		}
+
+		return c.subscribe(&server.SubscribeRequest{
+			Query:      q,
+			Replay:     replay,
+			BufferSize: bufferSize,
+			SlowPolicy: policy,
+		})
+	},
+}
+
+func init() {
+	clientCmd.AddCommand(subscribeCmd)
+
+	addQueryFlags(subscribeCmd)
+	subscribeCmd.Flags().Bool("replay", false, "Print every record which already matches the query before any new ones")
+	subscribeCmd.Flags().Uint32("buffer-size", 0, "The number of records the server buffers while we catch up; 0 uses the server default")
+	subscribeCmd.Flags().String("slow-policy", "drop", "What the server does when its buffer is full; one of drop, disconnect")
+}
+
+// parseSlowSubscriberPolicy turns a slow subscriber policy, as
+// passed to --slow-policy, into a server.SlowSubscriberPolicy
+func parseSlowSubscriberPolicy(name string) (server.SlowSubscriberPolicy, error) {
+	switch strings.ToLower(name) {
+	case "drop":
+		return server.SlowSubscriberPolicy_DropRecords, nil
+
+	case "disconnect":
+		return server.SlowSubscriberPolicy_Disconnect, nil
+	}
+
+	return 0, fmt.Errorf("unknown slow subscriber policy %q", name)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright © 2025 jspc <james@zero-internet.org.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// timeseriesCmd represents the timeseries command
+var timeseriesCmd = &cobra.Command{
+	Use:   "timeseries",
+	Short: "Aggregate data into buckets of time",
+	Long:  "Aggregate the records matching a query into fixed-width buckets of time, returning a series per record name",
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		addr, err := cmd.Flags().GetString("addr")
+		if err != nil {
+			return
+		}
+
+		c, err := newClient(addr)
+		if err != nil {
+			return
+		}
+
+		q, err := queryFromFlags(cmd)
+		if err != nil {
+			return
+		}
+
+		strings := make(map[string]string)
+		for _, f := range []string{"aggregation", "fill", "align"} {
+			strings[f], err = cmd.Flags().GetString(f)
+			if err != nil {
+				return
+			}
+		}
+
+		aggregation, err := parseAggregation(strings["aggregation"])
+		if err != nil {
+			return
+		}
+
+		fill, err := parseFillPolicy(strings["fill"])
+		if err != nil {
+			return
+		}
+
+		width, err := cmd.Flags().GetDuration("bucket")
+		if err != nil {
+			return
+		}
+
+		tr := &server.TimeSeriesRequest{
+			Query:       q,
+			Aggregation: aggregation,
+			BucketWidth: durationpb.New(width),
+			Fill:        fill,
+		}
+
+		if strings["align"] != "" {
+			var align time.Time
+
+			align, err = time.Parse(time.RFC3339, strings["align"])
+			if err != nil {
+				return
+			}
+
+			tr.Alignment = timestamppb.New(align)
+		}
+
+		return c.timeSeries(tr)
+	},
+}
+
+func init() {
+	clientCmd.AddCommand(timeseriesCmd)
+
+	addQueryFlags(timeseriesCmd)
+	timeseriesCmd.Flags().String("aggregation", "mean", "The aggregation to run per bucket; one of count, sum, min, max, mean, stddev, first, last")
+	timeseriesCmd.Flags().Duration("bucket", time.Minute, "The width of each bucket")
+	timeseriesCmd.Flags().String("align", "", "A time (RFC3339) bucket boundaries line up with; defaults to the unix epoch")
+	timeseriesCmd.Flags().String("fill", "none", "How to fill empty buckets; one of none, null, previous, linear")
+}
+
+// parseFillPolicy turns a case-insensitive fill policy, such
+// as "linear", into a server.FillPolicy
+func parseFillPolicy(name string) (server.FillPolicy, error) {
+	for v, known := range server.FillPolicy_name {
+		if strings.EqualFold("Fill"+name, known) {
+			return server.FillPolicy(v), nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown fill policy %q", name)
+}
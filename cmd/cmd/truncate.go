@@ -0,0 +1,75 @@
+/*
+Copyright © 2025 jspc <james@zero-internet.org.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// truncateCmd represents the truncate command
+var truncateCmd = &cobra.Command{
+	Use:   "truncate",
+	Short: "Drop old records from a dataset",
+	Long:  "Drop every record in a dataset with a `When` value before a given time",
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		addr, err := cmd.Flags().GetString("addr")
+		if err != nil {
+			return
+		}
+
+		c, err := newClient(addr)
+		if err != nil {
+			return
+		}
+
+		dataset, err := cmd.Flags().GetString("dataset")
+		if err != nil {
+			return
+		}
+
+		b, err := cmd.Flags().GetString("before")
+		if err != nil {
+			return
+		}
+
+		before, err := time.Parse(time.RFC3339, b)
+		if err != nil {
+			return
+		}
+
+		return c.truncate(&server.TruncateRequest{
+			Dataset: dataset,
+			Before:  timestamppb.New(before),
+		})
+	},
+}
+
+func init() {
+	clientCmd.AddCommand(truncateCmd)
+
+	truncateCmd.Flags().String("dataset", "", "The dataset to truncate")
+	truncateCmd.Flags().String("before", "", "Drop every record from before this time (RFC3339)")
+}
@@ -0,0 +1,135 @@
+/*
+Copyright © 2025 jspc <james@zero-internet.org.uk>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/xyt-db/xyt/server"
+)
+
+// zoneCmd represents the zone command
+var zoneCmd = &cobra.Command{
+	Use:   "zone",
+	Short: "Manage the named zones on a dataset",
+	Long:  "Manage the named zones on a dataset, which queries can refer to with --zone",
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		return cmd.Usage()
+	},
+}
+
+// zoneAddCmd represents the zone add command
+var zoneAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a named zone to a dataset",
+	Long:  "Add a named zone to a dataset",
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		c, dataset, name, err := zoneFlags(cmd)
+		if err != nil {
+			return
+		}
+
+		polygon, err := cmd.Flags().GetString("polygon")
+		if err != nil {
+			return
+		}
+
+		p, err := parsePolygon(polygon)
+		if err != nil {
+			return
+		}
+
+		return c.addZone(&server.Zone{
+			Dataset: dataset,
+			Name:    name,
+			Polygon: p,
+		})
+	},
+}
+
+// zoneListCmd represents the zone list command
+var zoneListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the named zones on a dataset",
+	Long:  "List the named zones on a dataset",
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		c, dataset, _, err := zoneFlags(cmd)
+		if err != nil {
+			return
+		}
+
+		return c.listZones(dataset)
+	},
+}
+
+// zoneDeleteCmd represents the zone delete command
+var zoneDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a named zone from a dataset",
+	Long:  "Delete a named zone from a dataset",
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		c, dataset, name, err := zoneFlags(cmd)
+		if err != nil {
+			return
+		}
+
+		return c.deleteZone(dataset, name)
+	},
+}
+
+// zoneFlags returns a client, along with the --dataset and
+// --name flags, shared by each zone command
+func zoneFlags(cmd *cobra.Command) (c client, dataset, name string, err error) {
+	addr, err := cmd.Flags().GetString("addr")
+	if err != nil {
+		return
+	}
+
+	c, err = newClient(addr)
+	if err != nil {
+		return
+	}
+
+	dataset, err = cmd.Flags().GetString("dataset")
+	if err != nil {
+		return
+	}
+
+	if cmd.Flags().Lookup("name") == nil {
+		return
+	}
+
+	name, err = cmd.Flags().GetString("name")
+
+	return
+}
+
+func init() {
+	clientCmd.AddCommand(zoneCmd)
+	zoneCmd.AddCommand(zoneAddCmd, zoneListCmd, zoneDeleteCmd)
+
+	zoneCmd.PersistentFlags().String("dataset", "", "The dataset the zone belongs to")
+
+	zoneAddCmd.Flags().String("name", "", "The name of the zone, such as dock-3")
+	zoneAddCmd.Flags().String("polygon", "", "The zone's polygon, as space separated X,Y vertices, with any holes following a '|'")
+
+	zoneDeleteCmd.Flags().String("name", "", "The name of the zone to delete")
+}
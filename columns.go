@@ -0,0 +1,590 @@
+package xyt
+
+import (
+	"cmp"
+	"math"
+	"math/bits"
+	"slices"
+	"sort"
+	"time"
+)
+
+// blockSize is the number of readings a columnar series buffers,
+// uncompressed, before sealing them into a compressed block
+const blockSize = 1_024
+
+// columns holds the readings for a single series column by column, rather
+// than as records, for datasets with a Columnar schema.
+//
+// Readings are buffered into head columns, which are sealed into compressed
+// blocks once they hold blockSize readings. Timestamps are stored as
+// nanoseconds since the epoch
+type columns struct {
+	blocks []*block
+
+	whens  []int64
+	thetas []int32
+	values []float64
+}
+
+// A block holds a sealed, compressed, run of readings, along with the
+// range of timestamps within it so whole blocks can be skipped by queries.
+//
+// Readings are encoded Gorilla-style into a single bit stream: timestamps
+// as delta-of-deltas, values as the XOR of each value and the one before,
+// and thetas as a single bit where they're unchanged from the one before
+type block struct {
+	count            int
+	minWhen, maxWhen int64
+	data             []byte
+}
+
+func (c *columns) len() (n int) {
+	for _, b := range c.blocks {
+		n += b.count
+	}
+
+	return n + len(c.whens)
+}
+
+// latest returns the latest timestamp a sorted series holds, and
+// false where the series is empty
+func (c *columns) latest() (int64, bool) {
+	if len(c.whens) > 0 {
+		return c.whens[len(c.whens)-1], true
+	}
+
+	if len(c.blocks) > 0 {
+		return c.blocks[len(c.blocks)-1].maxWhen, true
+	}
+
+	return 0, false
+}
+
+// insert adds a reading, growing the head columns by grow readings at a
+// time, and keeping readings in timestamp order where sorted is true
+func (c *columns) insert(when int64, t int32, v float64, grow int, sorted bool) {
+	if len(c.whens) >= cap(c.whens) {
+		grow = min(grow, blockSize)
+
+		c.whens = slices.Grow(c.whens, grow)
+		c.thetas = slices.Grow(c.thetas, grow)
+		c.values = slices.Grow(c.values, grow)
+	}
+
+	latest, ok := c.latest()
+
+	switch {
+	case !sorted || !ok || when >= latest:
+		c.whens = append(c.whens, when)
+		c.thetas = append(c.thetas, t)
+		c.values = append(c.values, v)
+
+	case len(c.blocks) == 0 || when >= c.blocks[len(c.blocks)-1].maxWhen:
+		i := sort.Search(len(c.whens), func(i int) bool { return c.whens[i] > when })
+
+		c.whens = slices.Insert(c.whens, i, when)
+		c.thetas = slices.Insert(c.thetas, i, t)
+		c.values = slices.Insert(c.values, i, v)
+
+	default:
+		// The reading belongs within a sealed block, which is expensive
+		// but rare; the block is unpacked, added to, and sealed again
+		bi := sort.Search(len(c.blocks), func(i int) bool { return c.blocks[i].maxWhen > when })
+
+		whens, thetas, values := c.blocks[bi].decode()
+		i := sort.Search(len(whens), func(i int) bool { return whens[i] > when })
+
+		c.blocks[bi] = encodeBlock(
+			slices.Insert(whens, i, when),
+			slices.Insert(thetas, i, t),
+			slices.Insert(values, i, v),
+		)
+	}
+
+	if len(c.whens) >= blockSize {
+		c.seal()
+	}
+}
+
+// seal compresses the head columns into a new block
+func (c *columns) seal() {
+	c.blocks = append(c.blocks, encodeBlock(c.whens, c.thetas, c.values))
+
+	c.whens = c.whens[:0]
+	c.thetas = c.thetas[:0]
+	c.values = c.values[:0]
+}
+
+// sort puts every reading into timestamp order, keeping readings which share
+// a timestamp in the order they were stored, and seals them into fresh blocks.
+// It's for series which weren't sorted on insert, but now need to be
+func (c *columns) sort() {
+	type reading struct {
+		when int64
+		t    int32
+		v    float64
+	}
+
+	readings := make([]reading, 0, c.len())
+	c.each(math.MinInt64, math.MaxInt64, false, func(when int64, t int32, v float64) bool {
+		readings = append(readings, reading{when, t, v})
+
+		return true
+	})
+
+	slices.SortStableFunc(readings, func(a, b reading) int {
+		return cmp.Compare(a.when, b.when)
+	})
+
+	*c = columns{}
+	for _, r := range readings {
+		c.insert(r.when, r.t, r.v, blockSize, true)
+	}
+}
+
+// each passes every reading with a timestamp between from and to, inclusive,
+// to fn in storage order, stopping early where fn returns false. Where sorted
+// is true, each stops at the first reading after to
+func (c *columns) each(from, to int64, sorted bool, fn func(when int64, t int32, v float64) bool) {
+	// visit returns false where there's no need to look any further
+	visit := func(when int64, t int32, v float64) bool {
+		switch {
+		case when < from:
+			return true
+
+		case when > to:
+			return !sorted
+		}
+
+		return fn(when, t, v)
+	}
+
+	for _, b := range c.blocks {
+		if sorted && b.minWhen > to {
+			return
+		}
+
+		if b.maxWhen < from || b.minWhen > to {
+			continue
+		}
+
+		d := b.decoder()
+		for range b.count {
+			if !visit(d.next()) {
+				return
+			}
+		}
+	}
+
+	for i := range c.whens {
+		if !visit(c.whens[i], c.thetas[i], c.values[i]) {
+			return
+		}
+	}
+}
+
+// reverse passes every reading to fn, latest first for sorted
+// series, stopping early where fn returns false
+func (c *columns) reverse(fn func(when int64, t int32, v float64) bool) {
+	for i := len(c.whens) - 1; i >= 0; i-- {
+		if !fn(c.whens[i], c.thetas[i], c.values[i]) {
+			return
+		}
+	}
+
+	for bi := len(c.blocks) - 1; bi >= 0; bi-- {
+		whens, thetas, values := c.blocks[bi].decode()
+
+		for i := len(whens) - 1; i >= 0; i-- {
+			if !fn(whens[i], thetas[i], values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// truncate drops every reading from before before, returning the number
+// of readings dropped. Blocks which lose readings are sealed again, and the
+// head columns are reallocated, so dropped readings can actually be freed
+func (c *columns) truncate(before int64) (removed int) {
+	blocks := make([]*block, 0, len(c.blocks))
+
+	for _, b := range c.blocks {
+		switch {
+		case b.maxWhen < before:
+			removed += b.count
+
+		case b.minWhen >= before:
+			blocks = append(blocks, b)
+
+		default:
+			whens, thetas, values := b.decode()
+
+			var n int
+			whens, thetas, values, n = dropBefore(whens, thetas, values, before)
+			removed += n
+
+			if len(whens) > 0 {
+				blocks = append(blocks, encodeBlock(whens, thetas, values))
+			}
+		}
+	}
+
+	c.blocks = slices.Clip(blocks)
+
+	var n int
+	c.whens, c.thetas, c.values, n = dropBefore(c.whens, c.thetas, c.values, before)
+	removed += n
+
+	return
+}
+
+// deleteFunc drops every reading with a timestamp between from and to,
+// inclusive, for which drop returns true, returning the number of readings
+// dropped. As per truncate, blocks which lose readings are sealed again, and
+// the head columns reallocated
+func (c *columns) deleteFunc(from, to int64, drop func(when int64, t int32, v float64) bool) (removed int) {
+	inRange := func(when int64, t int32, v float64) bool {
+		return when >= from && when <= to && drop(when, t, v)
+	}
+
+	blocks := make([]*block, 0, len(c.blocks))
+
+	for _, b := range c.blocks {
+		if b.maxWhen < from || b.minWhen > to {
+			blocks = append(blocks, b)
+
+			continue
+		}
+
+		whens, thetas, values := b.decode()
+
+		var n int
+		whens, thetas, values, n = dropFunc(whens, thetas, values, inRange)
+		removed += n
+
+		switch {
+		case n == 0:
+			blocks = append(blocks, b)
+
+		case len(whens) > 0:
+			blocks = append(blocks, encodeBlock(whens, thetas, values))
+		}
+	}
+
+	c.blocks = slices.Clip(blocks)
+
+	var n int
+	c.whens, c.thetas, c.values, n = dropFunc(c.whens, c.thetas, c.values, inRange)
+	removed += n
+
+	return
+}
+
+// dropBefore returns freshly allocated columns without the readings
+// from before before, along with the number of readings dropped
+func dropBefore(whens []int64, thetas []int32, values []float64, before int64) ([]int64, []int32, []float64, int) {
+	return dropFunc(whens, thetas, values, func(when int64, _ int32, _ float64) bool {
+		return when < before
+	})
+}
+
+// dropFunc returns freshly allocated columns without the readings drop
+// returns true for, along with the number of readings dropped. Where
+// nothing is dropped, the columns are returned as they are
+func dropFunc(whens []int64, thetas []int32, values []float64, drop func(when int64, t int32, v float64) bool) ([]int64, []int32, []float64, int) {
+	var n int
+	for i, when := range whens {
+		if drop(when, thetas[i], values[i]) {
+			n++
+		}
+	}
+
+	if n == 0 {
+		return whens, thetas, values, 0
+	}
+
+	keptWhens := make([]int64, 0, len(whens)-n)
+	keptThetas := make([]int32, 0, len(whens)-n)
+	keptValues := make([]float64, 0, len(whens)-n)
+
+	for i, when := range whens {
+		if !drop(when, thetas[i], values[i]) {
+			keptWhens = append(keptWhens, when)
+			keptThetas = append(keptThetas, thetas[i])
+			keptValues = append(keptValues, values[i])
+		}
+	}
+
+	return keptWhens, keptThetas, keptValues, n
+}
+
+// unixNano is t.UnixNano, clamped to the range of an int64 rather than
+// undefined for times hundreds of years from the epoch
+func unixNano(t time.Time) int64 {
+	switch {
+	case t.Before(time.Unix(0, math.MinInt64)):
+		return math.MinInt64
+
+	case t.After(time.Unix(0, math.MaxInt64)):
+		return math.MaxInt64
+	}
+
+	return t.UnixNano()
+}
+
+// encodeBlock compresses the readings passed into a block
+func encodeBlock(whens []int64, thetas []int32, values []float64) *block {
+	b := &block{
+		count:   len(whens),
+		minWhen: math.MaxInt64,
+		maxWhen: math.MinInt64,
+	}
+
+	var (
+		w                 bitWriter
+		prevWhen, delta   int64
+		prevTheta         int32
+		prevValue         uint64
+		leading, trailing = -1, 0
+	)
+
+	for i, when := range whens {
+		b.minWhen = min(b.minWhen, when)
+		b.maxWhen = max(b.maxWhen, when)
+
+		value := math.Float64bits(values[i])
+
+		if i == 0 {
+			w.writeBits(uint64(when), 64)      // #nosec: G115
+			w.writeBits(uint64(thetas[i]), 32) // #nosec: G115
+			w.writeBits(value, 64)
+
+			prevWhen, prevTheta, prevValue = when, thetas[i], value
+
+			continue
+		}
+
+		// Timestamps
+		dod := (when - prevWhen) - delta
+		delta = when - prevWhen
+		prevWhen = when
+
+		switch {
+		case dod == 0:
+			w.writeBit(false)
+
+		case dod >= -63 && dod <= 64:
+			w.writeBits(0b10, 2)
+			w.writeBits(uint64(dod+63), 7) // #nosec: G115
+
+		case dod >= -255 && dod <= 256:
+			w.writeBits(0b110, 3)
+			w.writeBits(uint64(dod+255), 9) // #nosec: G115
+
+		case dod >= -2047 && dod <= 2048:
+			w.writeBits(0b1110, 4)
+			w.writeBits(uint64(dod+2047), 12) // #nosec: G115
+
+		default:
+			w.writeBits(0b1111, 4)
+			w.writeBits(uint64(dod), 64) // #nosec: G115
+		}
+
+		// Thetas
+		if thetas[i] == prevTheta {
+			w.writeBit(false)
+		} else {
+			w.writeBit(true)
+			w.writeBits(uint64(thetas[i]), 32) // #nosec: G115
+
+			prevTheta = thetas[i]
+		}
+
+		// Values
+		xor := value ^ prevValue
+		prevValue = value
+
+		if xor == 0 {
+			w.writeBit(false)
+
+			continue
+		}
+
+		w.writeBit(true)
+
+		lead := min(bits.LeadingZeros64(xor), 31)
+		trail := bits.TrailingZeros64(xor)
+
+		if leading >= 0 && lead >= leading && trail >= trailing {
+			// The meaningful bits fit within the previous window,
+			// so there's no need to describe the window again
+			w.writeBit(false)
+			w.writeBits(xor>>trailing, 64-leading-trailing)
+
+			continue
+		}
+
+		leading, trailing = lead, trail
+		significant := 64 - lead - trail
+
+		w.writeBit(true)
+		w.writeBits(uint64(lead), 5)             // #nosec: G115
+		w.writeBits(uint64(significant&0x3f), 6) // #nosec: G115
+		w.writeBits(xor>>trail, significant)
+	}
+
+	b.data = slices.Clip(w.b)
+
+	return b
+}
+
+// decode unpacks every reading in b
+func (b *block) decode() (whens []int64, thetas []int32, values []float64) {
+	whens = make([]int64, b.count)
+	thetas = make([]int32, b.count)
+	values = make([]float64, b.count)
+
+	d := b.decoder()
+	for i := range whens {
+		whens[i], thetas[i], values[i] = d.next()
+	}
+
+	return
+}
+
+func (b *block) decoder() *blockDecoder {
+	return &blockDecoder{
+		r:       bitReader{b: b.data},
+		leading: -1,
+	}
+}
+
+// A blockDecoder unpacks a block one reading at a time
+type blockDecoder struct {
+	r bitReader
+	n int
+
+	when, delta       int64
+	theta             int32
+	value             uint64
+	leading, trailing int
+}
+
+// next returns the next reading; callers must not call next
+// more times than the block has readings
+func (d *blockDecoder) next() (int64, int32, float64) {
+	d.n++
+
+	if d.n == 1 {
+		d.when = int64(d.r.readBits(64))  // #nosec: G115
+		d.theta = int32(d.r.readBits(32)) // #nosec: G115
+		d.value = d.r.readBits(64)
+
+		return d.when, d.theta, math.Float64frombits(d.value)
+	}
+
+	// Timestamps
+	var dod int64
+
+	switch {
+	case !d.r.readBit():
+
+	case !d.r.readBit():
+		dod = int64(d.r.readBits(7)) - 63 // #nosec: G115
+
+	case !d.r.readBit():
+		dod = int64(d.r.readBits(9)) - 255 // #nosec: G115
+
+	case !d.r.readBit():
+		dod = int64(d.r.readBits(12)) - 2047 // #nosec: G115
+
+	default:
+		dod = int64(d.r.readBits(64)) // #nosec: G115
+	}
+
+	d.delta += dod
+	d.when += d.delta
+
+	// Thetas
+	if d.r.readBit() {
+		d.theta = int32(d.r.readBits(32)) // #nosec: G115
+	}
+
+	// Values
+	if d.r.readBit() {
+		if d.r.readBit() {
+			d.leading = int(d.r.readBits(5))
+
+			significant := int(d.r.readBits(6))
+			if significant == 0 {
+				significant = 64
+			}
+
+			d.trailing = 64 - d.leading - significant
+		}
+
+		d.value ^= d.r.readBits(64-d.leading-d.trailing) << d.trailing
+	}
+
+	return d.when, d.theta, math.Float64frombits(d.value)
+}
+
+// bitWriter appends bits, most significant first, to a byte slice
+type bitWriter struct {
+	b    []byte
+	free int
+}
+
+func (w *bitWriter) writeBit(bit bool) {
+	var v uint64
+	if bit {
+		v = 1
+	}
+
+	w.writeBits(v, 1)
+}
+
+// writeBits writes the lowest n bits of v
+func (w *bitWriter) writeBits(v uint64, n int) {
+	for n > 0 {
+		if w.free == 0 {
+			w.b = append(w.b, 0)
+			w.free = 8
+		}
+
+		take := min(n, w.free)
+		chunk := (v >> (n - take)) & (1<<take - 1)
+
+		w.b[len(w.b)-1] |= byte(chunk << (w.free - take)) // #nosec: G115
+
+		w.free -= take
+		n -= take
+	}
+}
+
+// bitReader reads bits back out of a byte slice written by a bitWriter
+type bitReader struct {
+	b   []byte
+	pos int
+}
+
+func (r *bitReader) readBit() bool {
+	return r.readBits(1) == 1
+}
+
+func (r *bitReader) readBits(n int) (v uint64) {
+	for n > 0 {
+		avail := 8 - r.pos%8
+		take := min(n, avail)
+		chunk := (uint64(r.b[r.pos/8]) >> (avail - take)) & (1<<take - 1)
+
+		v = v<<take | chunk
+
+		r.pos += take
+		n -= take
+	}
+
+	return
+}
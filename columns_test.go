@@ -0,0 +1,232 @@
+package xyt
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestEncodeBlock(t *testing.T) {
+	// #nosec: G404
+	rnd := rand.New(rand.NewSource(1))
+
+	regular := func(i int) int64 { return int64(i) * int64(time.Second) }
+	jittered := func(i int) int64 { return int64(i)*int64(10*time.Millisecond) + rnd.Int63n(int64(time.Millisecond)) }
+	shuffled := func(i int) int64 { return rnd.Int63() - math.MaxInt64/2 }
+
+	for _, test := range []struct {
+		name  string
+		when  func(int) int64
+		theta func(int) int32
+		value func(int) float64
+	}{
+		{"Regular readings", regular, func(int) int32 { return 90 }, func(int) float64 { return 21.5 }},
+		{"Jittered readings", jittered, func(i int) int32 { return int32(i % 360) }, func(i int) float64 { return 20 + math.Sin(float64(i)) }},
+		{"Unsorted readings", shuffled, func(int) int32 { return rnd.Int31() - math.MaxInt32/2 }, func(int) float64 { return rnd.NormFloat64() * 1e6 }},
+		{"Awkward values", regular, func(int) int32 { return 0 }, func(i int) float64 {
+			return []float64{0, math.Copysign(0, -1), math.Inf(1), math.Inf(-1), math.MaxFloat64, math.SmallestNonzeroFloat64, -1}[i%7]
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			whens := make([]int64, blockSize)
+			thetas := make([]int32, blockSize)
+			values := make([]float64, blockSize)
+
+			for i := range whens {
+				whens[i], thetas[i], values[i] = test.when(i), test.theta(i), test.value(i)
+			}
+
+			rcvdWhens, rcvdThetas, rcvdValues := encodeBlock(whens, thetas, values).decode()
+
+			if !slices.Equal(whens, rcvdWhens) {
+				t.Errorf("timestamps did not survive encoding")
+			}
+
+			if !slices.Equal(thetas, rcvdThetas) {
+				t.Errorf("thetas did not survive encoding")
+			}
+
+			for i := range values {
+				if math.Float64bits(values[i]) != math.Float64bits(rcvdValues[i]) {
+					t.Fatalf("value %d: expected %v, received %v", i, values[i], rcvdValues[i])
+				}
+			}
+		})
+	}
+
+	t.Run("NaN", func(t *testing.T) {
+		_, _, values := encodeBlock([]int64{0, 1}, []int32{0, 0}, []float64{1, math.NaN()}).decode()
+		if !math.IsNaN(values[1]) {
+			t.Errorf("expected NaN, received %v", values[1])
+		}
+	})
+}
+
+func TestColumns_insert(t *testing.T) {
+	c := new(columns)
+
+	// Three blocks worth of readings, interleaved such that the odd readings
+	// arrive late and have to be inserted into already sealed blocks
+	n := int64(blockSize * 3)
+	for i := int64(0); i < n; i += 2 {
+		c.insert(i, 0, float64(i), 10, true)
+	}
+
+	for i := int64(1); i < n; i += 2 {
+		c.insert(i, 0, float64(i), 10, true)
+	}
+
+	if c.len() != int(n) {
+		t.Fatalf("expected %d readings, received %d", n, c.len())
+	}
+
+	var expect int64
+	c.each(math.MinInt64, math.MaxInt64, true, func(when int64, _ int32, v float64) bool {
+		if when != expect || v != float64(expect) {
+			t.Fatalf("expected reading %d, received %d (%v)", expect, when, v)
+		}
+
+		expect++
+
+		return true
+	})
+
+	removed := c.truncate(n / 2)
+	if removed != int(n/2) {
+		t.Errorf("expected %d readings removed, received %d", n/2, removed)
+	}
+
+	if c.len() != int(n/2) {
+		t.Errorf("expected %d readings, received %d", n/2, c.len())
+	}
+}
+
+func TestDatabase_Columnar(t *testing.T) {
+	databases := make(map[bool]*Database)
+
+	for _, columnar := range []bool{false, true} {
+		d, err := New()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = d.CreateDataset(&server.Schema{
+			Dataset:      "site-a",
+			XMax:         10,
+			YMax:         10,
+			SortOnInsert: true,
+			Columnar:     columnar,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Enough readings to seal blocks, arriving slightly out of order
+		for i := range blockSize * 3 {
+			when := time.Unix(int64(i+(i%3)), 0)
+
+			err = d.InsertRecord(&server.Record{
+				Meta:    &server.Metadata{When: timestamppb.New(when)},
+				Dataset: "site-a",
+				Name:    []string{"temperature", "humidity"}[i%2],
+				Value:   float64(i) / 4,
+				X:       int32(i % 3),
+				Y:       int32(i % 5),
+				T:       int32(i % 360),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		databases[columnar] = d
+	}
+
+	for _, test := range []struct {
+		name  string
+		query *server.Query
+	}{
+		{"Everything", &server.Query{Dataset: "site-a"}},
+		{"Single location", &server.Query{Dataset: "site-a", X: &server.Query_XValue{XValue: 1}, Y: &server.Query_YValue{YValue: 1}}},
+		{"Theta range", &server.Query{Dataset: "site-a", T: &server.Query_TRange{TRange: &server.QueryRange{Start: 90, End: 180}}}},
+		{"Names", &server.Query{Dataset: "site-a", Names: []string{"humidity"}}},
+		{"Latest", &server.Query{Dataset: "site-a", Time: &server.Query_TimeLatest{TimeLatest: true}, T: &server.Query_TValue{TValue: 7}}},
+		{"Time range", &server.Query{Dataset: "site-a", Time: &server.Query_TimeRange{TimeRange: &server.TimeRange{
+			Start: timestamppb.New(time.Unix(1000, 0)),
+			End:   timestamppb.New(time.Unix(2500, 0)),
+		}}}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			expect, err := databases[false].RetrieveRecords(test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			received, err := databases[true].RetrieveRecords(test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(expect) == 0 {
+				t.Fatal("expected records, received none")
+			}
+
+			if len(expect) != len(received) {
+				t.Fatalf("expected %d records, received %d", len(expect), len(received))
+			}
+
+			for i := range expect {
+				e, r := expect[i], received[i]
+
+				if e.Name != r.Name || e.Value != r.Value || e.X != r.X || e.Y != r.Y || e.T != r.T || !e.Meta.When.AsTime().Equal(r.Meta.When.AsTime()) {
+					t.Fatalf("record %d: expected %v, received %v", i, e, r)
+				}
+			}
+		})
+	}
+
+	t.Run("Snapshots", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+
+		err := databases[true].Snapshot(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		d, err := New()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = d.Restore(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		records, err := d.RetrieveRecords(&server.Query{Dataset: "site-a"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(records) != blockSize*3 {
+			t.Errorf("expected %d records, received %d", blockSize*3, len(records))
+		}
+
+		if d.data["site-a"].get(0, 0).series[0].columns == nil {
+			t.Error("expected restored dataset to be columnar")
+		}
+	})
+
+	t.Run("Index queries", func(t *testing.T) {
+		_, err := databases[true].RetrieveRecords(&server.Query{Dataset: "site-a", IndexKey: "robot", IndexValue: "robo-001"})
+		if err != ColumnarIndexQueryError {
+			t.Errorf("expected ColumnarIndexQueryError, received %#v", err)
+		}
+	})
+}
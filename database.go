@@ -1,11 +1,11 @@
 package xyt
 
 import (
-	"slices"
 	"sync"
 	"time"
 
 	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/proto"
 )
 
 // A Database is the top-level *thing* that xyt exposes.
@@ -14,20 +14,32 @@ import (
 // way to avoid frequent re-allocations, and these locations can be sorted by
 // time on insert to aid in querying.
 //
-// Database operations are thread-safe; all of the interesting stuff is either
-// gated with mutexes (inserts, etc.), or are eventually consistent (updating
-// internal metrics).
+// Database operations are thread-safe; all of the interesting stuff is
+// gated with mutexes.
+//
+// Each dataset has its own read/write lock, so inserts into one dataset never
+// wait on inserts into, or queries against, another. Queries hold their
+// dataset's lock for reading from start to finish, and so see a consistent
+// view of it; inserts, and other changes, hold it for writing. Creating,
+// altering, and deleting datasets holds every dataset's lock at once.
 type Database struct {
-	mutx sync.Mutex
+	// mutx guards the maps below, which is to say which datasets
+	// exist; it is held for writing to create, alter, or delete a
+	// dataset, and for reading by everything else. See rlock and lock
+	mutx sync.RWMutex
+
+	// locks holds a lock per dataset, guarding everything held
+	// for that dataset within the maps below
+	locks map[string]*sync.RWMutex
 
 	// data maps records as per:
-	//   [record.Dataset][record.X][record.Y][]*record
-	// Where the final slice contains references to records
-	// sorted on their `When` value
+	//   [record.Dataset] -> grid -> (record.X, record.Y)
+	// Where each cell holds references to records, split by
+	// record.Name and sorted on their `When` value
 	//
 	// We don't really do much here with sharding or timestamps; certainly
 	// not yet
-	data map[string][][][]*server.Record
+	data map[string]grid
 
 	// fields contains a union of the various fields a dataset contains
 	fields map[string]map[string]interface{}
@@ -37,6 +49,25 @@ type Database struct {
 
 	// stats holds varius dataset stats
 	stats map[string]*Stats
+
+	// indices holds the inverted indices for each dataset, as per:
+	//   [record.Dataset][index key]
+	indices map[string]map[string]*index
+
+	// tiles holds the spatial index for each dataset, which lets
+	// queries skip parts of a dataset which can't hold matches
+	tiles map[string]*quadtree
+
+	// subscriptions holds the live subscriptions to each dataset
+	subscriptions map[string]map[*Subscription]struct{}
+
+	// wal, when set, is where every accepted schema and record
+	// is logged before being applied
+	wal *WAL
+
+	// now returns the current time, and is used to decide which
+	// rollup tier, if any, should serve a query
+	now func() time.Time
 }
 
 // New creates a new Database and returns it for use and takes no tunables.
@@ -44,10 +75,15 @@ type Database struct {
 // Most of the fun stuff lives elsewhere, such as creating datasets.
 func New() (d *Database, err error) {
 	d = new(Database)
-	d.data = make(map[string][][][]*server.Record)
+	d.locks = make(map[string]*sync.RWMutex)
+	d.data = make(map[string]grid)
 	d.fields = make(map[string]map[string]interface{})
 	d.schemata = make(map[string]*server.Schema)
 	d.stats = make(map[string]*Stats)
+	d.indices = make(map[string]map[string]*index)
+	d.tiles = make(map[string]*quadtree)
+	d.subscriptions = make(map[string]map[*Subscription]struct{})
+	d.now = time.Now
 
 	return
 }
@@ -66,27 +102,66 @@ func (d *Database) Datasets() (ds map[string]*server.Schema) {
 	// run the risk of being able to change values that end up breaking things,
 	// like if someone decides to try and grow a dataset by changing the XMax
 	// and/or the YMax value which just ends up breaking querying
+	d.mutx.RLock()
+	defer d.mutx.RUnlock()
+
 	ds = make(map[string]*server.Schema)
-	for k, v := range d.schemata {
-		ds[k] = &server.Schema{
-			Dataset:   v.Dataset,
-			Frequency: v.Frequency,
-			XMin:      v.XMin,
-			XMax:      v.XMax,
-			YMin:      v.YMin,
-			YMax:      v.YMax,
-		}
+	for k := range d.schemata {
+		ds[k] = d.cloneSchema(k)
 	}
 
 	return
 }
 
+// cloneSchema returns a copy of dataset's schema, and must be
+// called with d.mutx held
+func (d *Database) cloneSchema(dataset string) *server.Schema {
+	// Zones are changed in place, under the dataset's lock
+	d.locks[dataset].RLock()
+	defer d.locks[dataset].RUnlock()
+
+	return proto.Clone(d.schemata[dataset]).(*server.Schema)
+}
+
 // Stats maps dataset names with things like record counts, and memory size
 // for later reporting.
 //
-// This is handy data for, say, capacity planning
-func (d *Database) Stats() map[string]*Stats {
-	return d.stats
+// This is handy data for, say, capacity planning. Stats are copies, taken
+// as of the call, and so are safe to read while records are still arriving
+func (d *Database) Stats() (stats map[string]*Stats) {
+	d.mutx.RLock()
+	defer d.mutx.RUnlock()
+
+	stats = make(map[string]*Stats, len(d.stats))
+	for k, v := range d.stats {
+		stats[k] = v.clone()
+	}
+
+	return
+}
+
+// A DatasetSummary holds a dataset's schema alongside its stats
+type DatasetSummary struct {
+	Schema *server.Schema
+	Stats  *Stats
+}
+
+// Summaries returns the schema and stats of every dataset, much as Datasets
+// and Stats do, but taken together so that datasets created or deleted in
+// between the two calls can't leave one without the other
+func (d *Database) Summaries() (summaries map[string]DatasetSummary) {
+	d.mutx.RLock()
+	defer d.mutx.RUnlock()
+
+	summaries = make(map[string]DatasetSummary, len(d.schemata))
+	for k := range d.schemata {
+		summaries[k] = DatasetSummary{
+			Schema: d.cloneSchema(k),
+			Stats:  d.stats[k].clone(),
+		}
+	}
+
+	return
 }
 
 // CreateDataset takes a schema and pre-allocates a load of memory for that dataset.
@@ -102,43 +177,120 @@ func (d *Database) Stats() map[string]*Stats {
 //	LazyInitialAllocate: for datasets where only a subset of locations are likely to be used,
 //			     setting this to true can limit the amount of zero pages allocated
 //			     to a xyt server, which is handy on systems with limited memory
+//	MaxIndexCardinality: the number of distinct values any one index key may hold, beyond which
+//			     inserts are rejected; defaults to DefaultMaxIndexCardinality
+//	Retention: how long records are kept, based on their `When` value, before the janitor
+//		   drops them; see RunJanitor. Records are kept forever where unset
+//	Rollups: tiers of per-location, per-name, aggregates at coarser resolutions, which can be kept
+//		 for much longer than the records they're built from. Queries reaching back beyond
+//		 Retention are served from the finest tier which covers them
+//	Sparse: only allocate the locations records are actually inserted into, for huge grids
+//		where only a sliver of locations are ever visited. Sparse datasets ignore
+//		LazyInitialAllocate
+//	Columnar: store readings as compressed columns of timestamps, thetas, and values rather than
+//		  as records, using far less memory. Labels and index values aren't kept, so columnar
+//		  datasets can't be queried by index
+//	Zones: named polygons which queries can refer to by name; see AddZone
+//	ThetaResolution: the size, in degrees, of each theta bucket records are binned into, for
+//			 headings more precise than a degree. Record.T, and queries on it, are in
+//			 buckets; where unset, buckets are a degree wide
+//	Transform: maps the grid onto real world coordinates, such as metres from a site origin, so
+//		   records can be inserted, and queried, by world location, and carry their world
+//		   location when queried
+//	Geo: maps world coordinates onto the globe, either as a local east/north/up plane or a UTM
+//	     zone, so records can be inserted by latitude and longitude, and exported as GeoJSON.
+//	     Geo needs a Transform; where Geo has Bounds and the schema has no X or Y bounds of
+//	     its own, they're worked out from the box
+//	ZMin/ZMax: a third, vertical, axis, such as floors of a building; records and queries carry a
+//		   Z alongside X and Y. Where unset, the dataset is flat, and Z is always 0
 //
 // A sensible norm would be to set the frequency to 1 - 10hz, setting SortOnInsert to true, and
 // LazyInitialAllocate to false; this will give you a nice, quick, trim dataset with good
 // insert and query performance.
 func (d *Database) CreateDataset(s *server.Schema) (err error) {
+	if s != nil && validGeo(s.Geo) && validTransform(s.Transform) {
+		deriveGeoBounds(s)
+	}
+
 	err = d.validateSchema(s)
 	if err != nil {
 		return
 	}
 
+	d.mutx.Lock()
+	defer d.mutx.Unlock()
+
 	if _, ok := d.data[s.Dataset]; ok {
 		return DuplicateDatasetError
 	}
 
-	d.mutx.Lock()
-	defer d.mutx.Unlock()
-
-	d.schemata[s.Dataset] = s
-	d.stats[s.Dataset] = newStats()
-
-	d.data[s.Dataset] = make([][][]*server.Record, s.XMax-s.XMin)
-	for xi := range d.data[s.Dataset] {
-		d.data[s.Dataset][xi] = make([][]*server.Record, s.YMax-s.YMin)
-
-		for yi := range d.data[s.Dataset][xi] {
-			switch s.LazyInitialAllocate {
-			case true:
-				d.data[s.Dataset][xi][yi] = make([]*server.Record, 0)
-			default:
-				d.data[s.Dataset][xi][yi] = make([]*server.Record, 0, frequencyToSize(s.Frequency))
-			}
+	if d.wal != nil {
+		err = d.wal.appendSchema(s)
+		if err != nil {
+			return
 		}
 	}
 
+	d.addDataset(s, newGrid(s), newQuadtree(s), newStats())
+
 	return
 }
 
+// addDataset adds a dataset, described by s, to each of d's maps, so
+// that nothing need be added to them again until the dataset is deleted.
+//
+// It must be called with d.mutx held for writing
+func (d *Database) addDataset(s *server.Schema, data grid, tiles *quadtree, stats *Stats) {
+	d.locks[s.Dataset] = new(sync.RWMutex)
+	d.schemata[s.Dataset] = s
+	d.stats[s.Dataset] = stats
+	d.data[s.Dataset] = data
+	d.tiles[s.Dataset] = tiles
+	d.fields[s.Dataset] = make(map[string]interface{})
+	d.indices[s.Dataset] = make(map[string]*index)
+	d.subscriptions[s.Dataset] = make(map[*Subscription]struct{})
+}
+
+// rlock holds d.mutx for reading, so that datasets can't be created,
+// altered, or deleted underneath the caller, along with dataset's own lock
+// for reading, returning a func which releases both.
+//
+// Where dataset doesn't exist, only d.mutx is held, leaving the caller to
+// decide what to do about that, such as returning UnknownDatasetError
+func (d *Database) rlock(dataset string) (unlock func()) {
+	d.mutx.RLock()
+
+	l, ok := d.locks[dataset]
+	if !ok {
+		return d.mutx.RUnlock
+	}
+
+	l.RLock()
+
+	return func() {
+		l.RUnlock()
+		d.mutx.RUnlock()
+	}
+}
+
+// lock is rlock for callers which change dataset, and so holds
+// dataset's own lock for writing
+func (d *Database) lock(dataset string) (unlock func()) {
+	d.mutx.RLock()
+
+	l, ok := d.locks[dataset]
+	if !ok {
+		return d.mutx.RUnlock
+	}
+
+	l.Lock()
+
+	return func() {
+		l.Unlock()
+		d.mutx.RUnlock()
+	}
+}
+
 // InsertRecord takes a record, validates it for things like (X,Y) boundaries,
 // required fields, and whether the underlying dataset exists, and then inserts
 // it into the Database.
@@ -148,116 +300,106 @@ func (d *Database) CreateDataset(s *server.Schema) (err error) {
 //
 // If the Dataset has been created with SortOnInsert=true, this function will also
 // ensure data is stored in the correct order.
+//
+// If the Database has a WAL (see UseWAL) then the record is logged before being
+// stored, and a failure to log the record fails the insert.
 func (d *Database) InsertRecord(r *server.Record) (err error) {
+	unlock := d.lock(r.GetDataset())
+	defer unlock()
+
 	err = d.validateRecord(r)
 	if err != nil {
 		return
 	}
 
-	d.mutx.Lock()
-	defer d.mutx.Unlock()
-
-	// Ensure we have enough space allocated to avoid re-allocating on every write
-	// and instead do allocations roughly once per second- which is at least more predictable
 	schema := d.schemata[r.Dataset]
 
-	if len(d.data[r.Dataset][r.X][r.Y]) >= cap(d.data[r.Dataset][r.X][r.Y]) {
-		d.data[r.Dataset][r.X][r.Y] = slices.Grow(d.data[r.Dataset][r.X][r.Y], frequencyToSize(schema.Frequency))
+	// A full turn is the same heading as no turn at all, and precise
+	// headings need binning; storing everything as a bucket means theta
+	// queries needn't care how a record's heading was sent
+	r.T = recordTheta(schema, r)
+
+	// Likewise, records are stored by cell, with their world location
+	// alongside where the dataset has one
+	r.World = recordWorld(schema, r)
+	r.X, r.Y = recordCell(schema, r)
+	if schema.Transform != nil && r.World == nil {
+		r.World = cellCentre(schema.Transform, r.X, r.Y)
 	}
 
-	d.data[r.Dataset][r.X][r.Y] = append(d.data[r.Dataset][r.X][r.Y], r)
-
-	if schema.SortOnInsert {
-		slices.SortFunc(d.data[r.Dataset][r.X][r.Y], func(a, b *server.Record) int {
-			return a.Meta.When.AsTime().Compare(b.Meta.When.AsTime())
-		})
+	err = d.checkCardinality(schema, r)
+	if err != nil {
+		return
 	}
 
-	if _, ok := d.fields[r.Dataset]; !ok {
-		d.fields[r.Dataset] = make(map[string]interface{})
+	if d.wal != nil {
+		err = d.wal.appendRecord(r)
+		if err != nil {
+			return
+		}
 	}
 
+	c := d.data[r.Dataset].allocate(r.X, r.Y)
+	d.tiles[r.Dataset].add(r.X, r.Y, unixNano(r.Meta.When.AsTime()))
+
+	c.insert(r, schema)
+	c.rollup(r, schema.Rollups)
+
+	d.indexRecord(schema, r)
+
 	d.fields[r.Dataset][r.Name] = nil
 
-	// Stats are eventually consistent
-	go d.stats[r.Dataset].addRecord(r)
+	d.publish(r)
+
+	d.stats[r.Dataset].addRecord(r)
 
 	return
 }
 
 // RetrieveRecords accepts a query and returns matching Records, erroing
 // if the query is invalid.
+//
+// Where a query sets both an IndexKey and an IndexValue, only records
+// indexed with that key/value pair are considered, which avoids having
+// to scan every location in the dataset.
+//
+// Where a query sets Names, only records with one of those names are
+// returned; records for other names are never looked at. Queries for the
+// latest record return the latest record for each name at each location.
+//
+// Theta ranges are in the dataset's theta buckets, and wrap around a full
+// turn where their start is greater than their end, so that, with one degree
+// buckets, a range of 350 to 10 covers due north, give or take ten degrees;
+// headings with a tolerance work the same way.
+//
+// Where a query is served from a rollup tier, a record is returned per rollup
+// rather than per reading, with the rollup's mean as its value and the start
+// of the rollup's period as its `When`.
+//
+// Where a query sets Within, only records from locations within that circle
+// are returned, and where it sets Nearest, only records from the closest
+// locations holding matches are; either way, records are ordered by distance,
+// nearest first.
+//
+// Where a query sets Polygons, only records from locations within at least
+// one of them, and outside of that polygon's holes, are returned. Locations
+// on an edge count as within the polygon, even where that edge is a hole's.
+// Zones work the same way, using polygons stored against the dataset, and so
+// do WorldPolygons, which match cells whose centres fall within them.
+//
+// Records from columnar datasets are rehydrated from their columns as they
+// match, and carry neither labels nor index values.
 func (d *Database) RetrieveRecords(q *server.Query) (r []*server.Record, err error) {
-	if q == nil || q.Dataset == "" {
-		return nil, MissingDatasetError
-	}
-
-	ds, ok := d.data[q.Dataset]
-	if !ok {
-		return nil, UnknownDatasetError
-	}
-
-	schema := d.schemata[q.Dataset]
-
-	xMin, xMax := xRange(schema, q)
-	yMin, yMax := yRange(schema, q)
-	tMin, tMax, tAll := tRange(schema, q)
-
-	timeStart, timeEnd, timeAll, timeLatest := timeRange(q)
-
-	// If we only want the latest matching record, there's no real
-	// need doing much beyond doing a backwards ranging of the data,
-	// finding the first (ie: most recent) record matching the theta
-	if timeLatest && !schema.SortOnInsert {
-		err = UnsortedDataset
-
-		return
-	}
+	unlock := d.rlock(q.GetDataset())
+	defer unlock()
 
 	r = make([]*server.Record, 0)
 
-	for x := xMin; x < xMax; x++ {
-		for y := yMin; y < yMax; y++ {
-			if timeLatest {
-				for ri := len(ds[x][y]) - 1; ri >= 0; ri-- {
-					record := ds[x][y][ri]
-					if !tAll {
-						if record.T >= tMin && record.T < tMax {
-							r = append(r, record)
-
-							goto next
-						}
-					}
-				}
-			}
-
-			for _, record := range ds[x][y] {
-				if !timeAll {
-					ts := record.Meta.When.AsTime()
-					if ts.Before(timeStart) {
-						continue
-					}
-
-					if ts.After(timeEnd) {
-						if schema.SortOnInsert {
-							goto next
-						}
-
-						continue
-					}
-				}
-
-				if !tAll {
-					if record.T < tMin || record.T >= tMax {
-						continue
-					}
-				}
-
-				r = append(r, record)
-			}
-		next:
-		}
-
+	err = d.walk(q, func(record *server.Record, _ *aggregator) {
+		r = append(r, record)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return
@@ -281,32 +423,63 @@ func (d *Database) validateRecord(r *server.Record) error {
 		return UnknownDatasetError
 	}
 
-	if r.X < schema.XMin || r.X > schema.XMax {
+	err := validateGeo(schema, r)
+	if err != nil {
+		return err
+	}
+
+	err = validateWorld(schema, r)
+	if err != nil {
+		return err
+	}
+
+	// Records sent in world coordinates need to land within the grid
+	// once quantised, rather than wherever X and Y happen to point
+	x, y := recordCell(schema, r)
+
+	// XMax and YMax are exclusive, hence reporting the
+	// largest allowed position, rather than the schema bound
+	if x < schema.XMin || x >= schema.XMax {
 		return PositionOutOfBoundsError{
 			dataset:  r.Dataset,
 			position: positionX,
 			min:      schema.XMin,
-			max:      schema.XMax,
-			received: r.X,
+			max:      schema.XMax - 1,
+			received: x,
 		}
 	}
 
-	if r.Y < schema.YMin || r.Y > schema.YMax {
+	if y < schema.YMin || y >= schema.YMax {
 		return PositionOutOfBoundsError{
 			dataset:  r.Dataset,
 			position: positionY,
 			min:      schema.YMin,
-			max:      schema.YMax,
-			received: r.Y,
+			max:      schema.YMax - 1,
+			received: y,
 		}
 	}
 
-	if r.T < 0 || r.T > 360 {
+	if zMin, zMax := zBounds(schema); r.Z < zMin || r.Z >= zMax {
+		return PositionOutOfBoundsError{
+			dataset:  r.Dataset,
+			position: positionZ,
+			min:      zMin,
+			max:      zMax - 1,
+			received: r.Z,
+		}
+	}
+
+	if r.Theta != nil {
+		// Checking this way around also catches NaN
+		if !(*r.Theta >= 0 && *r.Theta <= 360) {
+			return InvalidThetaError
+		}
+	} else if buckets := thetaBuckets(schema); r.T < 0 || r.T > buckets {
 		return PositionOutOfBoundsError{
 			dataset:  r.Dataset,
 			position: positionTheta,
 			min:      0,
-			max:      360,
+			max:      buckets,
 			received: r.T,
 		}
 	}
@@ -335,6 +508,53 @@ func (d *Database) validateSchema(s *server.Schema) error {
 		return InvalidCoordRangeError{s.Dataset, positionY, coordRangeErrorReasonMinMax}
 	}
 
+	if hasZ(s) {
+		if s.ZMin >= s.ZMax {
+			return InvalidCoordRangeError{s.Dataset, positionZ, coordRangeErrorReasonMinMax}
+		}
+
+		if s.Columnar {
+			return ColumnarZAxisError
+		}
+	}
+
+	if s.Retention.AsDuration() < 0 {
+		return InvalidRetentionError
+	}
+
+	if !validThetaResolution(s.ThetaResolution) {
+		return InvalidThetaResolutionError
+	}
+
+	if !validTransform(s.Transform) {
+		return InvalidTransformError
+	}
+
+	if !validGeo(s.Geo) {
+		return InvalidGeoError
+	}
+
+	if s.Geo != nil && s.Transform == nil {
+		return MissingTransformError
+	}
+
+	for i, tier := range s.Rollups {
+		if tier.Resolution.AsDuration() <= 0 || tier.Retention.AsDuration() < 0 {
+			return InvalidRollupTierError
+		}
+
+		if i > 0 && tier.Resolution.AsDuration() <= s.Rollups[i-1].Resolution.AsDuration() {
+			return InvalidRollupTierError
+		}
+	}
+
+	for name, p := range s.Zones {
+		err := validateZone(name, p)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -354,52 +574,125 @@ func frequencyToSize(f server.Frequency) int {
 	}
 }
 
-func xRange(s *server.Schema, q *server.Query) (min, max int32) {
-	switch v := q.X.(type) {
-	case *server.Query_XAll:
-		return s.XMin, s.XMax
+// offset returns the indices of the cell holding (x,y) within a dataset's
+// data; schemas needn't start at the origin, so cells are addressed relative
+// to XMin and YMin
+func offset(s *server.Schema, x, y int32) (xi, yi int32) {
+	return x - s.XMin, y - s.YMin
+}
 
+// xRange returns the (exclusive) range of X positions a query covers,
+// clamped to the schema's bounds
+func xRange(s *server.Schema, q *server.Query) (start, end int32) {
+	switch v := q.X.(type) {
 	case *server.Query_XValue:
-		return v.XValue, v.XValue + 1
+		start, end = v.XValue, v.XValue+1
 
 	case *server.Query_XRange:
-		return v.XRange.Start, v.XRange.End
+		start, end = v.XRange.Start, v.XRange.End
 
 	default:
 		return s.XMin, s.XMax
-
 	}
+
+	return clampRange(start, end, s.XMin, s.XMax)
 }
 
-func yRange(s *server.Schema, q *server.Query) (min, max int32) {
+// yRange returns the (exclusive) range of Y positions a query covers,
+// clamped to the schema's bounds
+func yRange(s *server.Schema, q *server.Query) (start, end int32) {
 	switch v := q.Y.(type) {
-	case *server.Query_YAll:
-		return s.YMin, s.YMax
-
 	case *server.Query_YValue:
-		return v.YValue, v.YValue + 1
+		start, end = v.YValue, v.YValue+1
 
 	case *server.Query_YRange:
-		return v.YRange.Start, v.YRange.End
+		start, end = v.YRange.Start, v.YRange.End
 
 	default:
 		return s.YMin, s.YMax
 	}
+
+	return clampRange(start, end, s.YMin, s.YMax)
 }
 
-func tRange(_ *server.Schema, q *server.Query) (min, max int32, all bool) {
+// hasZ returns whether s has a Z axis
+func hasZ(s *server.Schema) bool {
+	return s.ZMin != 0 || s.ZMax != 0
+}
+
+// zBounds returns the (exclusive) range of Z positions s allows; flat
+// datasets only allow a Z of 0
+func zBounds(s *server.Schema) (zMin, zMax int32) {
+	if !hasZ(s) {
+		return 0, 1
+	}
+
+	return s.ZMin, s.ZMax
+}
+
+// zRange returns the (exclusive) range of Z positions a query covers,
+// clamped to the schema's bounds
+func zRange(s *server.Schema, q *server.Query) (start, end int32) {
+	zMin, zMax := zBounds(s)
+
+	switch v := q.Z.(type) {
+	case *server.Query_ZValue:
+		start, end = v.ZValue, v.ZValue+1
+
+	case *server.Query_ZRange:
+		start, end = v.ZRange.Start, v.ZRange.End
+
+	default:
+		return zMin, zMax
+	}
+
+	return clampRange(start, end, zMin, zMax)
+}
+
+// clampRange clamps the range start to end to lower and upper, returning
+// an empty range where the two don't overlap at all
+func clampRange(start, end, lower, upper int32) (int32, int32) {
+	start = min(max(start, lower), upper)
+	end = min(max(end, start), upper)
+
+	return start, end
+}
+
+// tRange returns the (exclusive) range of theta buckets a query covers.
+// Ranges wrap around 0/360 where min is greater than max, and are empty
+// where the two are equal
+func tRange(s *server.Schema, q *server.Query) (min, max int32, all bool) {
+	n := thetaBuckets(s)
+
 	switch v := q.T.(type) {
 	case *server.Query_TAll:
-		return 0, 360, true
+		return 0, n, true
 
 	case *server.Query_TValue:
-		return v.TValue, v.TValue + 1, false
+		t := wrapTheta(int64(v.TValue), n)
+
+		return t, t + 1, false
 
 	case *server.Query_TRange:
-		return v.TRange.Start, v.TRange.End, false
+		start, end := int64(v.TRange.Start), int64(v.TRange.End)
+		if end-start >= int64(n) {
+			return 0, n, false
+		}
+
+		// Wrapping a non-empty range could leave start and end equal,
+		// which would make it empty; ranges this wide are caught above
+		return wrapTheta(start, n), wrapTheta(end, n), false
+
+	case *server.Query_THeading:
+		h, tolerance := int64(v.THeading.Heading), int64(v.THeading.Tolerance)
+		if 2*tolerance+1 >= int64(n) {
+			return 0, n, false
+		}
+
+		return wrapTheta(h-tolerance, n), wrapTheta(h+tolerance+1, n), false
 
 	default:
-		return 0, 360, all
+		return 0, n, all
 	}
 }
 
@@ -1,11 +1,18 @@
 package xyt
 
 import (
+	"bytes"
+	"context"
+	"io"
 	"math/rand"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -18,6 +25,40 @@ var (
 	chars = []byte{'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o', 'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z', '1', '2', '3', '4', '5', '6', '7', '8', '9', '0', '.', ','}
 )
 
+// testDatabase returns a database holding each of schemata, with each
+// of records inserted, in order, logging everything to w where w is non-nil
+func testDatabase(t *testing.T, w *WAL, schemata []*server.Schema, records []*server.Record) *Database {
+	t.Helper()
+
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w != nil {
+		err = d.UseWAL(w)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, s := range schemata {
+		err = d.CreateDataset(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, r := range records {
+		err = d.InsertRecord(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return d
+}
+
 func TestDatabase_Add(t *testing.T) {
 	for _, test := range []struct {
 		name        string
@@ -30,8 +71,10 @@ func TestDatabase_Add(t *testing.T) {
 		{"Empty When fails", &server.Record{Dataset: "site-a", X: 1, Y: 1, T: 90, Name: "temperature"}, true},
 		{"Too low X value fails", &server.Record{Dataset: "site-a", X: -11, Y: 1, T: 90, Name: "temperature", Meta: &server.Metadata{When: timestamppb.New(time.Now())}}, true},
 		{"Too high X value fails", &server.Record{Dataset: "site-a", X: 11, Y: 1, T: 90, Name: "temperature", Meta: &server.Metadata{When: timestamppb.New(time.Now())}}, true},
+		{"X value of XMax fails", &server.Record{Dataset: "site-a", X: 10, Y: 1, T: 90, Name: "temperature", Meta: &server.Metadata{When: timestamppb.New(time.Now())}}, true},
 		{"Too low Y value fails", &server.Record{Dataset: "site-a", X: 1, Y: -11, T: 90, Name: "temperature", Meta: &server.Metadata{When: timestamppb.New(time.Now())}}, true},
 		{"Too high Y value fails", &server.Record{Dataset: "site-a", X: 1, Y: 11, T: 90, Name: "temperature", Meta: &server.Metadata{When: timestamppb.New(time.Now())}}, true},
+		{"Y value of YMax fails", &server.Record{Dataset: "site-a", X: 1, Y: 10, T: 90, Name: "temperature", Meta: &server.Metadata{When: timestamppb.New(time.Now())}}, true},
 		{"Too low T value fails", &server.Record{Dataset: "site-a", X: 1, Y: 1, T: -1000, Name: "temperature", Meta: &server.Metadata{When: timestamppb.New(time.Now())}}, true},
 		{"Too high T value fails", &server.Record{Dataset: "site-a", X: 1, Y: 1, T: 1000, Name: "temperature", Meta: &server.Metadata{When: timestamppb.New(time.Now())}}, true},
 		{"Unknown dataset errors", &server.Record{Dataset: "site-b", X: 1, Y: 1, T: 90, Name: "temperature", Meta: &server.Metadata{When: timestamppb.New(time.Now())}}, true},
@@ -93,6 +136,12 @@ func TestDatabase_CreateDataset(t *testing.T) {
 		{"Empty dataset name fails", &server.Schema{}, true},
 		{"Unset XMax fails", &server.Schema{Dataset: "racecourse"}, true},
 		{"Unset YMax fails", &server.Schema{Dataset: "racecourse", XMax: 10}, true},
+		{"Negative retention fails", &server.Schema{Dataset: "racecourse", XMax: 10, YMax: 10, Retention: durationpb.New(-time.Hour)}, true},
+		{"Rollups without a resolution fail", &server.Schema{Dataset: "racecourse", XMax: 10, YMax: 10, Rollups: []*server.RollupTier{{}}}, true},
+		{"Rollups out of order fail", &server.Schema{Dataset: "racecourse", XMax: 10, YMax: 10, Rollups: []*server.RollupTier{
+			{Resolution: durationpb.New(time.Hour)},
+			{Resolution: durationpb.New(time.Minute)},
+		}}, true},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			err := d.CreateDataset(test.schema)
@@ -193,6 +242,372 @@ func TestDatabase_RetrieveRecords(t *testing.T) {
 	}
 }
 
+func TestDatabase_RetrieveRecords_Names(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.CreateDataset(&server.Schema{
+		Dataset:      "site-a",
+		XMin:         0,
+		XMax:         10,
+		YMin:         0,
+		YMax:         10,
+		SortOnInsert: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := time.Now()
+
+	for i, name := range []string{"temperature", "voltage", "network"} {
+		for x := int32(0); x < 10; x++ {
+			for y := int32(0); y < 10; y++ {
+				err = d.InsertRecord(&server.Record{
+					Meta: &server.Metadata{
+						When: timestamppb.New(ts.Add(time.Duration(i) * time.Second)),
+					},
+					Dataset: "site-a",
+					Name:    name,
+					Value:   float64(i),
+					X:       x,
+					Y:       y,
+					T:       90,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+
+	for _, test := range []struct {
+		name        string
+		query       *server.Query
+		expectCount int
+	}{
+		{"No names returns everything", &server.Query{Dataset: "site-a"}, 300},
+		{"A single name returns only that name", &server.Query{Dataset: "site-a", Names: []string{"temperature"}}, 100},
+		{"Multiple names return each name", &server.Query{Dataset: "site-a", Names: []string{"temperature", "voltage"}}, 200},
+		{"Unknown names return nothing", &server.Query{Dataset: "site-a", Names: []string{"flurbles"}}, 0},
+		{"Unknown names are ignored alongside known names", &server.Query{Dataset: "site-a", Names: []string{"flurbles", "network"}}, 100},
+		{"Names combine with locations", &server.Query{Dataset: "site-a", Names: []string{"voltage"}, X: &server.Query_XValue{XValue: 3}}, 10},
+		{"Latest returns the latest record per name", &server.Query{Dataset: "site-a", Time: new(server.Query_TimeLatest)}, 300},
+		{"Latest with names returns the latest of those names", &server.Query{Dataset: "site-a", Names: []string{"voltage"}, Time: new(server.Query_TimeLatest)}, 100},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			records, err := d.RetrieveRecords(test.query)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			rcvd := len(records)
+			if test.expectCount != rcvd {
+				t.Errorf("expected %d records, received %d", test.expectCount, rcvd)
+			}
+
+			for _, r := range records {
+				if len(test.query.Names) > 0 && !slices.Contains(test.query.Names, r.Name) {
+					t.Errorf("received record with unexpected name %q", r.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestDatabase_RetrieveRecords_Theta(t *testing.T) {
+	for _, columnar := range []bool{false, true} {
+		t.Run(map[bool]string{false: "records", true: "columnar"}[columnar], func(t *testing.T) {
+			d, err := New()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = d.CreateDataset(&server.Schema{
+				Dataset:      "site-a",
+				XMin:         0,
+				XMax:         10,
+				YMin:         0,
+				YMax:         10,
+				SortOnInsert: true,
+				Columnar:     columnar,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// A reading every 10 degrees, plus one at 360, which
+			// should be treated as though it were at 0
+			for theta := int32(0); theta <= 360; theta += 10 {
+				err = d.InsertRecord(&server.Record{
+					Meta:    &server.Metadata{When: timestamppb.New(time.Unix(int64(theta)+1, 0))},
+					Dataset: "site-a",
+					Name:    "heading",
+					T:       theta,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for _, test := range []struct {
+				name        string
+				query       *server.Query
+				expectCount int
+			}{
+				{"Everything", &server.Query{T: &server.Query_TAll{TAll: true}}, 37},
+				{"A T of 360 is stored as 0", &server.Query{T: &server.Query_TValue{TValue: 0}}, 2},
+				{"Querying 360 is querying 0", &server.Query{T: &server.Query_TValue{TValue: 360}}, 2},
+				{"Negative values wrap", &server.Query{T: &server.Query_TValue{TValue: -10}}, 1},
+				{"Linear ranges", &server.Query{T: &server.Query_TRange{TRange: &server.QueryRange{Start: 10, End: 40}}}, 3},
+				{"Ranges ending at 360", &server.Query{T: &server.Query_TRange{TRange: &server.QueryRange{Start: 350, End: 360}}}, 1},
+				{"Ranges wrap around 0/360", &server.Query{T: &server.Query_TRange{TRange: &server.QueryRange{Start: 350, End: 11}}}, 4},
+				{"Ranges covering everything", &server.Query{T: &server.Query_TRange{TRange: &server.QueryRange{Start: -180, End: 180}}}, 37},
+				{"Empty ranges", &server.Query{T: &server.Query_TRange{TRange: &server.QueryRange{Start: 10, End: 10}}}, 0},
+				{"Headings", &server.Query{T: &server.Query_THeading{THeading: &server.Heading{Heading: 90, Tolerance: 10}}}, 3},
+				{"Headings wrap around 0/360", &server.Query{T: &server.Query_THeading{THeading: &server.Heading{Heading: 0, Tolerance: 10}}}, 4},
+				{"Headings beyond 360 wrap", &server.Query{T: &server.Query_THeading{THeading: &server.Heading{Heading: 720, Tolerance: 0}}}, 2},
+				{"Huge tolerances cover everything", &server.Query{T: &server.Query_THeading{THeading: &server.Heading{Heading: 90, Tolerance: 180}}}, 37},
+			} {
+				t.Run(test.name, func(t *testing.T) {
+					test.query.Dataset = "site-a"
+
+					records, err := d.RetrieveRecords(test.query)
+					if err != nil {
+						t.Fatalf("unexpected error %#v", err)
+					}
+
+					if test.expectCount != len(records) {
+						t.Errorf("expected %d records, received %d", test.expectCount, len(records))
+					}
+
+					for _, r := range records {
+						if r.T < 0 || r.T >= 360 {
+							t.Errorf("unexpected T %d", r.T)
+						}
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestDatabase_RetrieveRecords_Z(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name        string
+		schema      *server.Schema
+		expectError error
+	}{
+		{"Z ranges must be increasing", &server.Schema{Dataset: "a", XMax: 10, YMax: 10, ZMin: 3, ZMax: 1}, InvalidCoordRangeError{"a", positionZ, coordRangeErrorReasonMinMax}},
+		{"Columnar datasets can't have Z", &server.Schema{Dataset: "b", XMax: 10, YMax: 10, ZMax: 3, Columnar: true}, ColumnarZAxisError},
+		{"Floors, including a basement", &server.Schema{Dataset: "warehouse", XMax: 10, YMax: 10, ZMin: -1, ZMax: 3, SortOnInsert: true}, nil},
+		{"Flat datasets", &server.Schema{Dataset: "yard", XMax: 10, YMax: 10}, nil},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := d.CreateDataset(test.schema)
+			if err != test.expectError {
+				t.Errorf("expected %#v, received %#v", test.expectError, err)
+			}
+		})
+	}
+
+	for _, test := range []struct {
+		name        string
+		record      *server.Record
+		expectError error
+	}{
+		{"Basement", &server.Record{Dataset: "warehouse", Z: -1}, nil},
+		{"Ground floor", &server.Record{Dataset: "warehouse", Z: 0}, nil},
+		{"First floor", &server.Record{Dataset: "warehouse", Z: 1}, nil},
+		{"Top floor", &server.Record{Dataset: "warehouse", Z: 2}, nil},
+		{"Top floor, elsewhere", &server.Record{Dataset: "warehouse", X: 5, Y: 5, Z: 2}, nil},
+		{"Above the roof", &server.Record{Dataset: "warehouse", Z: 3}, PositionOutOfBoundsError{dataset: "warehouse", position: positionZ, min: -1, max: 2, received: 3}},
+		{"Flat datasets are at Z 0", &server.Record{Dataset: "yard"}, nil},
+		{"Flat datasets don't have other Zs", &server.Record{Dataset: "yard", Z: 1}, PositionOutOfBoundsError{dataset: "yard", position: positionZ, min: 0, max: 0, received: 1}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			test.record.Name = "temperature"
+			test.record.Meta = &server.Metadata{When: timestamppb.New(time.Unix(1000, 0))}
+
+			err := d.InsertRecord(test.record)
+			if err != test.expectError {
+				t.Errorf("expected %#v, received %#v", test.expectError, err)
+			}
+		})
+	}
+
+	for _, test := range []struct {
+		name        string
+		query       *server.Query
+		expectCount int
+	}{
+		{"Everything", &server.Query{Dataset: "warehouse"}, 5},
+		{"Every Z, explicitly", &server.Query{Dataset: "warehouse", Z: &server.Query_ZAll{ZAll: true}}, 5},
+		{"A single floor", &server.Query{Dataset: "warehouse", Z: &server.Query_ZValue{ZValue: 2}}, 2},
+		{"A single floor and location", &server.Query{Dataset: "warehouse", X: &server.Query_XValue{XValue: 5}, Y: &server.Query_YValue{YValue: 5}, Z: &server.Query_ZValue{ZValue: 2}}, 1},
+		{"A range of floors", &server.Query{Dataset: "warehouse", Z: &server.Query_ZRange{ZRange: &server.QueryRange{Start: -1, End: 1}}}, 2},
+		{"Ranges are clamped", &server.Query{Dataset: "warehouse", Z: &server.Query_ZRange{ZRange: &server.QueryRange{Start: 1, End: 100}}}, 3},
+		{"Floors which don't exist", &server.Query{Dataset: "warehouse", Z: &server.Query_ZValue{ZValue: 10}}, 0},
+		{"The latest record from a floor", &server.Query{Dataset: "warehouse", Z: &server.Query_ZValue{ZValue: -1}, Time: &server.Query_TimeLatest{TimeLatest: true}}, 1},
+		{"Flat datasets", &server.Query{Dataset: "yard", Z: &server.Query_ZValue{ZValue: 0}}, 1},
+		{"Flat datasets have nothing above 0", &server.Query{Dataset: "yard", Z: &server.Query_ZValue{ZValue: 1}}, 0},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			records, err := d.RetrieveRecords(test.query)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if test.expectCount != len(records) {
+				t.Errorf("expected %d records, received %d", test.expectCount, len(records))
+			}
+		})
+	}
+}
+
+func TestDatabase_OffsetOrigins(t *testing.T) {
+	for _, schema := range []*server.Schema{
+		{Dataset: "straddling", XMin: -5, XMax: 5, YMin: -3, YMax: 3},
+		{Dataset: "negative", XMin: -20, XMax: -10, YMin: -9, YMax: -3},
+		{Dataset: "offset", XMin: 100, XMax: 110, YMin: 50, YMax: 56},
+	} {
+		t.Run(schema.Dataset, func(t *testing.T) {
+			d, err := New()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = d.CreateDataset(schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for x := schema.XMin; x < schema.XMax; x++ {
+				for y := schema.YMin; y < schema.YMax; y++ {
+					err = d.InsertRecord(&server.Record{
+						Meta:    &server.Metadata{When: timestamppb.Now()},
+						Dataset: schema.Dataset,
+						Name:    "temperature",
+						X:       x,
+						Y:       y,
+						T:       90,
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			for _, test := range []struct {
+				name        string
+				x, y        int32
+				expectError bool
+			}{
+				{"Inserting at the origin succeeds", schema.XMin, schema.YMin, false},
+				{"Inserting at the far corner succeeds", schema.XMax - 1, schema.YMax - 1, false},
+				{"Inserting below XMin fails", schema.XMin - 1, schema.YMin, true},
+				{"Inserting at XMax fails", schema.XMax, schema.YMin, true},
+				{"Inserting below YMin fails", schema.XMin, schema.YMin - 1, true},
+				{"Inserting at YMax fails", schema.XMin, schema.YMax, true},
+			} {
+				t.Run(test.name, func(t *testing.T) {
+					err := d.validateRecord(&server.Record{
+						Meta:    &server.Metadata{When: timestamppb.Now()},
+						Dataset: schema.Dataset,
+						Name:    "temperature",
+						X:       test.x,
+						Y:       test.y,
+						T:       90,
+					})
+					if err == nil && test.expectError {
+						t.Errorf("expected error, received none")
+					} else if err != nil && !test.expectError {
+						t.Errorf("unexpected error %#v", err)
+					}
+				})
+			}
+
+			width, height := int(schema.XMax-schema.XMin), int(schema.YMax-schema.YMin)
+
+			for _, test := range []struct {
+				name        string
+				query       *server.Query
+				expectCount int
+			}{
+				{"Everything", &server.Query{}, width * height},
+				{"A single column", &server.Query{X: &server.Query_XValue{XValue: schema.XMin + 1}}, height},
+				{"A single location", &server.Query{X: &server.Query_XValue{XValue: schema.XMax - 1}, Y: &server.Query_YValue{YValue: schema.YMin}}, 1},
+				{"A range straddling XMin is clamped", &server.Query{X: &server.Query_XRange{XRange: &server.QueryRange{Start: schema.XMin - 10, End: schema.XMin + 2}}}, 2 * height},
+				{"A range straddling YMax is clamped", &server.Query{Y: &server.Query_YRange{YRange: &server.QueryRange{Start: schema.YMax - 1, End: schema.YMax + 10}}}, width},
+				{"A column beyond XMax returns nothing", &server.Query{X: &server.Query_XValue{XValue: schema.XMax}}, 0},
+				{"A range entirely below YMin returns nothing", &server.Query{Y: &server.Query_YRange{YRange: &server.QueryRange{Start: schema.YMin - 10, End: schema.YMin - 5}}}, 0},
+				{"A backwards range returns nothing", &server.Query{X: &server.Query_XRange{XRange: &server.QueryRange{Start: schema.XMax, End: schema.XMin}}}, 0},
+			} {
+				t.Run(test.name, func(t *testing.T) {
+					test.query.Dataset = schema.Dataset
+
+					records, err := d.RetrieveRecords(test.query)
+					if err != nil {
+						t.Fatalf("unexpected error %#v", err)
+					}
+
+					if test.expectCount != len(records) {
+						t.Errorf("expected %d records, received %d", test.expectCount, len(records))
+					}
+
+					xMin, xMax := xRange(schema, test.query)
+					yMin, yMax := yRange(schema, test.query)
+
+					for _, r := range records {
+						if r.X < xMin || r.X >= xMax || r.Y < yMin || r.Y >= yMax {
+							t.Errorf("received record from unexpected location (%d,%d)", r.X, r.Y)
+						}
+					}
+				})
+			}
+
+			t.Run("Snapshots keep locations", func(t *testing.T) {
+				buf := new(bytes.Buffer)
+
+				err := d.Snapshot(buf)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				restored, err := New()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				err = restored.Restore(buf)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				records, err := restored.RetrieveRecords(&server.Query{
+					Dataset: schema.Dataset,
+					X:       &server.Query_XValue{XValue: schema.XMin},
+					Y:       &server.Query_YValue{YValue: schema.YMax - 1},
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(records) != 1 || records[0].X != schema.XMin || records[0].Y != schema.YMax-1 {
+					t.Errorf("expected a single record from (%d,%d), received %v", schema.XMin, schema.YMax-1, records)
+				}
+			})
+		})
+	}
+}
+
 func TestDatabase_Datasets(t *testing.T) {
 	d, err := New()
 	if err != nil {
@@ -200,11 +615,14 @@ func TestDatabase_Datasets(t *testing.T) {
 	}
 
 	ds := &server.Schema{
-		Dataset: "site-a",
-		XMin:    0,
-		XMax:    10,
-		YMin:    0,
-		YMax:    10,
+		Dataset:             "site-a",
+		XMin:                0,
+		XMax:                10,
+		YMin:                0,
+		YMax:                10,
+		Sparse:              true,
+		LazyInitialAllocate: true,
+		SortOnInsert:        true,
 	}
 
 	err = d.CreateDataset(ds)
@@ -221,6 +639,305 @@ func TestDatabase_Datasets(t *testing.T) {
 	if ds == rcvd {
 		t.Fatal("Both pointers point to the same memory location")
 	}
+
+	if !proto.Equal(ds, rcvd) {
+		t.Errorf("expected %#v, received %#v", ds, rcvd)
+	}
+}
+
+func TestDatabase_Summaries(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.CreateDataset(&server.Schema{Dataset: "site-a", XMax: 10, YMax: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary, ok := d.Summaries()["site-a"]
+	if !ok {
+		t.Fatal("expected site-a to be summarised")
+	}
+
+	if summary.Schema.Dataset != "site-a" {
+		t.Errorf("expected %q, received %q", "site-a", summary.Schema.Dataset)
+	}
+
+	if summary.Stats.RecordCount != 0 {
+		t.Errorf("expected %d records, received %d", 0, summary.Stats.RecordCount)
+	}
+}
+
+func TestDatabase_Concurrent(t *testing.T) {
+	const (
+		writers = 4
+		each    = 250
+		total   = writers * each
+	)
+
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []*server.Schema{
+		{Dataset: "site-a", XMax: 10, YMax: 10, SortOnInsert: true},
+		{Dataset: "site-b", XMax: 10, YMax: 10, SortOnInsert: true, Columnar: true, Sparse: true},
+	} {
+		err = d.CreateDataset(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sub, err := d.Subscribe(&server.SubscribeRequest{
+		Query:      &server.Query{Dataset: "site-a"},
+		BufferSize: total,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer sub.Close()
+
+	var (
+		wg   sync.WaitGroup
+		rwg  sync.WaitGroup
+		done = make(chan struct{})
+	)
+
+	// Writers insert into both datasets at once, each from its own
+	// goroutine, and with every writer's records interleaved in time
+	for w := range writers {
+		for _, ds := range []string{"site-a", "site-b"} {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				for i := range each {
+					err := d.InsertRecord(&server.Record{
+						Meta: &server.Metadata{
+							When:    timestamppb.New(time.Unix(int64(i*writers+w), 0)),
+							Indices: map[string]string{"writer": "writer"},
+						},
+						Dataset: ds,
+						Name:    "temperature",
+						Value:   float64(i),
+						X:       int32(i % 10), // #nosec: G115
+						Y:       int32(w),      // #nosec: G115
+					})
+					if err != nil {
+						t.Error(err)
+
+						return
+					}
+				}
+			}()
+		}
+	}
+
+	// Readers, meanwhile, should only ever see more records than the
+	// last time they looked, with each location's records in order
+	reader := func(fn func() (int, error)) {
+		rwg.Add(1)
+
+		go func() {
+			defer rwg.Done()
+
+			var last int
+
+			for {
+				select {
+				case <-done:
+					return
+
+				default:
+				}
+
+				n, err := fn()
+				if err != nil {
+					t.Error(err)
+
+					return
+				}
+
+				if n < last {
+					t.Errorf("expected at least %d records, received %d", last, n)
+				}
+
+				last = n
+
+				// Leave writers some room; readers hammering away with no
+				// let up at all only slow the test down
+				time.Sleep(time.Millisecond)
+			}
+		}()
+	}
+
+	for _, ds := range []string{"site-a", "site-b"} {
+		reader(func() (int, error) {
+			records, err := d.RetrieveRecords(&server.Query{Dataset: ds, X: &server.Query_XValue{XValue: 0}, Y: &server.Query_YValue{YValue: 0}})
+
+			return len(records), err
+		})
+
+		reader(func() (int, error) {
+			results, err := d.Aggregate(&server.Query{Dataset: ds}, []server.Aggregation{server.Aggregation_Count}, false)
+			if err != nil {
+				return 0, err
+			}
+
+			return int(results[0].Count), nil
+		})
+
+		reader(func() (int, error) {
+			return int(d.Stats()[ds].RecordCount), nil
+		})
+	}
+
+	reader(func() (int, error) {
+		records, err := d.RetrieveRecords(&server.Query{Dataset: "site-a", IndexKey: "writer", IndexValue: "writer"})
+
+		return len(records), err
+	})
+
+	reader(func() (int, error) {
+		return len(d.Datasets()), d.Snapshot(io.Discard)
+	})
+
+	// Changes to the datasets themselves can happen alongside
+	// all of the above, too
+	reader(func() (int, error) {
+		err := d.AddZone(&server.Zone{Dataset: "site-a", Name: "dock", Polygon: &server.Polygon{Outline: ring(0, 0, 3, 0, 3, 3, 0, 3)}})
+		if err != nil {
+			return 0, err
+		}
+
+		_, err = d.Zones("site-a")
+		if err != nil {
+			return 0, err
+		}
+
+		return 0, d.DeleteZone("site-a", "dock")
+	})
+
+	reader(func() (int, error) {
+		_, err := d.Truncate("site-b", time.Unix(0, 0))
+
+		return 0, err
+	})
+
+	// Datasets coming and going never leave a summary half filled in
+	reader(func() (int, error) {
+		err := d.CreateDataset(&server.Schema{Dataset: "site-c", XMax: 1, YMax: 1})
+		if err != nil {
+			return 0, err
+		}
+
+		for ds, summary := range d.Summaries() {
+			if summary.Schema == nil || summary.Stats == nil {
+				t.Errorf("%s: expected both schema and stats, received %#v", ds, summary)
+			}
+		}
+
+		return 0, d.DeleteDataset("site-c")
+	})
+
+	wg.Wait()
+	close(done)
+	rwg.Wait()
+
+	for _, ds := range []string{"site-a", "site-b"} {
+		records, err := d.RetrieveRecords(&server.Query{Dataset: ds})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(records) != total {
+			t.Errorf("%s: expected %d records, received %d", ds, total, len(records))
+		}
+
+		for i := 1; i < len(records); i++ {
+			if records[i].X == records[i-1].X && records[i].Y == records[i-1].Y && compareWhen(records[i-1], records[i]) > 0 {
+				t.Errorf("%s: expected records at (%d,%d) to be in order", ds, records[i].X, records[i].Y)
+
+				break
+			}
+		}
+	}
+
+	for i := range total {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+
+		_, err := sub.Next(ctx)
+		cancel()
+
+		if err != nil {
+			t.Fatalf("record %d: unexpected error %#v", i, err)
+		}
+	}
+}
+
+func TestDatabase_DatasetLocks(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ds := range []string{"site-a", "site-b"} {
+		err = d.CreateDataset(&server.Schema{Dataset: ds, XMax: 10, YMax: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	insert := func(ds string) chan error {
+		errs := make(chan error, 1)
+
+		go func() {
+			errs <- d.InsertRecord(&server.Record{
+				Meta:    &server.Metadata{When: timestamppb.New(time.Unix(1, 0))},
+				Dataset: ds,
+				Name:    "temperature",
+			})
+		}()
+
+		return errs
+	}
+
+	// Holding site-a's lock, as a long running insert or change would,
+	// shouldn't hold up inserts into site-b
+	unlock := d.lock("site-a")
+
+	select {
+	case err = <-insert("site-b"):
+		if err != nil {
+			t.Fatal(err)
+		}
+
+	case <-time.After(time.Second):
+		unlock()
+		t.Fatal("expected inserts into site-b not to wait on site-a")
+	}
+
+	blocked := insert("site-a")
+
+	select {
+	case <-blocked:
+		t.Error("expected inserts into site-a to wait on site-a")
+
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	unlock()
+
+	err = <-blocked
+	if err != nil {
+		t.Fatal(err)
+	}
 }
 
 func BenchmarkDatabase_CreateDataset1(b *testing.B)    { benchmarkCreateDataset(1, b) }
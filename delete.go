@@ -0,0 +1,262 @@
+package xyt
+
+import (
+	"math"
+	"slices"
+
+	"github.com/xyt-db/xyt/server"
+)
+
+// DeleteDataset drops dataset entirely, along with its records, rollups,
+// indices, zones, and stats, freeing everything it held. Any subscriptions
+// to dataset are ended with DatasetDeletedError.
+//
+// A dataset of the same name can be created again afterwards
+func (d *Database) DeleteDataset(dataset string) (err error) {
+	if dataset == "" {
+		return MissingDatasetError
+	}
+
+	d.mutx.Lock()
+	defer d.mutx.Unlock()
+
+	if _, ok := d.schemata[dataset]; !ok {
+		return UnknownDatasetError
+	}
+
+	if d.wal != nil {
+		err = d.wal.appendDeleteDataset(dataset)
+		if err != nil {
+			return
+		}
+	}
+
+	for s := range d.subscriptions[dataset] {
+		s.end(DatasetDeletedError)
+	}
+
+	delete(d.subscriptions, dataset)
+	delete(d.data, dataset)
+	delete(d.tiles, dataset)
+	delete(d.fields, dataset)
+	delete(d.indices, dataset)
+	delete(d.stats, dataset)
+	delete(d.schemata, dataset)
+	delete(d.locks, dataset)
+
+	return
+}
+
+// Delete drops every record q matches, returning the number of records
+// dropped or, where dryRun is set, the number of records which would have
+// been, without dropping anything.
+//
+// Delete works on records, rather than rollups; rollups already built from
+// dropped records are kept, and queries served from rollups are unaffected.
+// Queries which depend on which records are stored, such as nearest and
+// latest record queries, can't be used.
+//
+// As per Truncate, locations which lose records are given freshly allocated
+// slices, so that the memory behind dropped records can be freed
+func (d *Database) Delete(q *server.Query, dryRun bool) (removed uint64, err error) {
+	if q != nil && (q.Nearest != nil || q.GetTimeLatest()) {
+		return 0, InvalidDeleteQueryError
+	}
+
+	unlock := d.lock(q.GetDataset())
+	defer unlock()
+
+	err = d.validateQuery(q)
+	if err != nil {
+		return
+	}
+
+	if !dryRun && d.wal != nil {
+		err = d.wal.appendDelete(q)
+		if err != nil {
+			return
+		}
+	}
+
+	schema := d.schemata[q.Dataset]
+
+	m := newMatcher(schema, q)
+	m.names = q.Names
+
+	match := func(r *server.Record) bool {
+		if q.IndexKey != "" && r.Meta.Indices[q.IndexKey] != q.IndexValue {
+			return false
+		}
+
+		return m.matches(r)
+	}
+
+	var dropped []*server.Record
+
+	g := d.data[q.Dataset]
+	g.each(m.xMin, m.xMax, m.yMin, m.yMax, func(x, y int32, c *cell) {
+		if !m.matchesLocation(x, y) {
+			return
+		}
+
+		n, records := c.delete(m, match, dryRun)
+		removed += n
+
+		if dryRun || n == 0 {
+			return
+		}
+
+		dropped = append(dropped, records...)
+
+		d.tiles[q.Dataset].remove(x, y, int(n)) // #nosec: G115
+		c.dropEmpty()
+	})
+
+	if dryRun {
+		return
+	}
+
+	g.prune()
+	d.unindex(q.Dataset, dropped)
+	d.stats[q.Dataset].removeRecords(removed)
+
+	return
+}
+
+// delete drops every record in c which match returns true for, along with
+// every columnar reading m matches, returning the number dropped, and the
+// dropped records themselves. Nothing is dropped where dryRun is set
+func (c *cell) delete(m matcher, match func(*server.Record) bool, dryRun bool) (removed uint64, dropped []*server.Record) {
+	from, to := int64(math.MinInt64), int64(math.MaxInt64)
+	if !m.timeAll {
+		from, to = unixNano(m.timeStart), unixNano(m.timeEnd)
+	}
+
+	// Columnar readings are only ever matched on time and theta; the
+	// location and name have already been checked by this point
+	matchReading := func(_ int64, t int32, _ float64) bool {
+		return m.matchesT(t)
+	}
+
+	for _, s := range c.series {
+		if len(m.names) > 0 && !slices.Contains(m.names, s.name) {
+			continue
+		}
+
+		if s.columns != nil {
+			if dryRun {
+				s.columns.each(from, to, m.sorted, func(when int64, t int32, v float64) bool {
+					if matchReading(when, t, v) {
+						removed++
+					}
+
+					return true
+				})
+
+				continue
+			}
+
+			removed += uint64(s.columns.deleteFunc(from, to, matchReading)) // #nosec: G115
+
+			continue
+		}
+
+		if dryRun {
+			for _, r := range s.records {
+				if match(r) {
+					removed++
+				}
+			}
+
+			continue
+		}
+
+		kept, gone := dropRecords(s.records, match)
+		s.records = kept
+
+		removed += uint64(len(gone))
+		dropped = append(dropped, gone...)
+	}
+
+	return
+}
+
+// unindex drops records from dataset's indices, dropping
+// locations and values which are left empty.
+//
+// It must be called with dataset's lock held; see lock
+func (d *Database) unindex(dataset string, records []*server.Record) {
+	type posting struct {
+		key, value string
+		cell       cellKey
+	}
+
+	// Work out which postings need touching first, so that each is
+	// only rebuilt once, however many records are dropped from it
+	gone := make(map[*server.Record]struct{}, len(records))
+	touched := make(map[posting]struct{})
+
+	for _, r := range records {
+		gone[r] = struct{}{}
+
+		for k, v := range r.Meta.Indices {
+			touched[posting{k, v, cellKey{r.X, r.Y}}] = struct{}{}
+		}
+	}
+
+	for t := range touched {
+		idx, ok := d.indices[dataset][t.key]
+		if !ok {
+			continue
+		}
+
+		p, ok := idx.values[t.value]
+		if !ok {
+			continue
+		}
+
+		p.cells[t.cell], _ = dropRecords(p.cells[t.cell], func(r *server.Record) bool {
+			_, ok := gone[r]
+
+			return ok
+		})
+
+		if len(p.cells[t.cell]) == 0 {
+			delete(p.cells, t.cell)
+		}
+
+		if len(p.cells) == 0 {
+			delete(idx.values, t.value)
+		}
+	}
+}
+
+// dropRecords splits records into those drop returns false for, and those
+// it returns true for.
+//
+// Where anything is dropped, kept is a new allocation, rather than a
+// reslicing of records, so that records' backing array can be freed
+func dropRecords(records []*server.Record, drop func(*server.Record) bool) (kept, dropped []*server.Record) {
+	for _, r := range records {
+		if drop(r) {
+			dropped = append(dropped, r)
+		}
+	}
+
+	switch len(dropped) {
+	case 0:
+		return records, nil
+
+	case len(records):
+		return nil, dropped
+	}
+
+	kept = make([]*server.Record, 0, len(records)-len(dropped))
+	for _, r := range records {
+		if !drop(r) {
+			kept = append(kept, r)
+		}
+	}
+
+	return
+}
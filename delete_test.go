@@ -0,0 +1,318 @@
+package xyt
+
+import (
+	"cmp"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestDatabase_DeleteDataset(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := &server.Schema{Dataset: "site-a", XMax: 10, YMax: 10}
+
+	err = d.CreateDataset(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.InsertRecord(&server.Record{
+		Meta: &server.Metadata{
+			When:    timestamppb.New(time.Unix(1, 0)),
+			Indices: map[string]string{"robot": "robo-001"},
+		},
+		Dataset: "site-a",
+		Name:    "temperature",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRecordCount(t, d, "site-a", 1)
+
+	s, err := d.Subscribe(&server.SubscribeRequest{Query: &server.Query{Dataset: "site-a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer s.Close()
+
+	for _, test := range []struct {
+		name        string
+		dataset     string
+		expectError error
+	}{
+		{"Missing datasets fail", "", MissingDatasetError},
+		{"Unknown datasets fail", "site-b", UnknownDatasetError},
+		{"Known datasets are deleted", "site-a", nil},
+		{"Deleted datasets are unknown", "site-a", UnknownDatasetError},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := d.DeleteDataset(test.dataset)
+			if err != test.expectError {
+				t.Errorf("expected %#v, received %#v", test.expectError, err)
+			}
+		})
+	}
+
+	if _, ok := d.Datasets()["site-a"]; ok {
+		t.Errorf("expected site-a to be gone")
+	}
+
+	for name, ok := range map[string]bool{
+		"data":     d.data["site-a"] != nil,
+		"tiles":    d.tiles["site-a"] != nil,
+		"fields":   d.fields["site-a"] != nil,
+		"indices":  d.indices["site-a"] != nil,
+		"stats":    d.stats["site-a"] != nil,
+		"schemata": d.schemata["site-a"] != nil,
+	} {
+		if ok {
+			t.Errorf("expected site-a to be dropped from %s", name)
+		}
+	}
+
+	_, err = s.Next(context.Background())
+	if err != DatasetDeletedError {
+		t.Errorf("expected DatasetDeletedError, received %#v", err)
+	}
+
+	_, err = d.RetrieveRecords(&server.Query{Dataset: "site-a"})
+	if err != UnknownDatasetError {
+		t.Errorf("expected UnknownDatasetError, received %#v", err)
+	}
+
+	// Datasets can be created afresh, with nothing left over
+	err = d.CreateDataset(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := d.RetrieveRecords(&server.Query{Dataset: "site-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 0 {
+		t.Errorf("expected %d records, received %d", 0, len(records))
+	}
+}
+
+func TestDatabase_Delete(t *testing.T) {
+	for _, columnar := range []bool{false, true} {
+		t.Run(map[bool]string{false: "records", true: "columnar"}[columnar], func(t *testing.T) {
+			d, err := New()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = d.CreateDataset(&server.Schema{
+				Dataset:      "site-a",
+				XMax:         10,
+				YMax:         10,
+				SortOnInsert: true,
+				Columnar:     columnar,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Two robots, each taking temperature and humidity readings,
+			// along the diagonal from (0,0) to (9,9), one a second
+			for i := int32(0); i < 10; i++ {
+				robot := "robo-001"
+				if i >= 5 {
+					robot = "robo-002"
+				}
+
+				for _, name := range []string{"temperature", "humidity"} {
+					err = d.InsertRecord(&server.Record{
+						Meta: &server.Metadata{
+							When:    timestamppb.New(time.Unix(int64(i), 0)),
+							Indices: map[string]string{"robot": robot},
+						},
+						Dataset: "site-a",
+						Name:    name,
+						X:       i,
+						Y:       i,
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			expectRecordCount(t, d, "site-a", 20)
+
+			remaining := 20
+
+			for _, test := range []struct {
+				name         string
+				query        *server.Query
+				expectRemove uint64
+				expectError  error
+			}{
+				{"Nearest queries fail", &server.Query{Nearest: &server.Nearest{K: 1}}, 0, InvalidDeleteQueryError},
+				{"Latest record queries fail", &server.Query{Time: &server.Query_TimeLatest{TimeLatest: true}}, 0, InvalidDeleteQueryError},
+				{"Unknown datasets fail", &server.Query{Dataset: "site-b"}, 0, UnknownDatasetError},
+				{"Names", &server.Query{Names: []string{"humidity"}, X: &server.Query_XValue{XValue: 0}}, 1, nil},
+				{"Locations", &server.Query{X: &server.Query_XRange{XRange: &server.QueryRange{Start: 1, End: 3}}, Y: &server.Query_YRange{YRange: &server.QueryRange{Start: 1, End: 3}}}, 4, nil},
+				{"Circles", &server.Query{Within: &server.Circle{X: 9, Y: 9, Radius: 1}}, 2, nil},
+				{"Time ranges", &server.Query{Time: &server.Query_TimeRange{TimeRange: &server.TimeRange{Start: timestamppb.New(time.Unix(3, 0)), End: timestamppb.New(time.Unix(4, 0))}}}, 4, nil},
+				{"Nothing matching", &server.Query{Names: []string{"pressure"}}, 0, nil},
+			} {
+				t.Run(test.name, func(t *testing.T) {
+					test.query.Dataset = cmp.Or(test.query.Dataset, "site-a")
+
+					// A dry run should count exactly what's then dropped
+					counted, err := d.Delete(test.query, true)
+					if err != test.expectError {
+						t.Fatalf("expected %#v, received %#v", test.expectError, err)
+					}
+
+					removed, err := d.Delete(test.query, false)
+					if err != test.expectError {
+						t.Fatalf("expected %#v, received %#v", test.expectError, err)
+					}
+
+					if counted != test.expectRemove || removed != test.expectRemove {
+						t.Errorf("expected %d records, counted %d and removed %d", test.expectRemove, counted, removed)
+					}
+
+					remaining -= int(removed)
+
+					records, err := d.RetrieveRecords(&server.Query{Dataset: "site-a"})
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if remaining != len(records) {
+						t.Errorf("expected %d records, received %d", remaining, len(records))
+					}
+
+					if rc := d.Stats()["site-a"].RecordCount; rc != uint32(remaining) {
+						t.Errorf("expected RecordCount %d, received %d", remaining, rc)
+					}
+				})
+			}
+
+			if columnar {
+				_, err = d.Delete(&server.Query{Dataset: "site-a", IndexKey: "robot", IndexValue: "robo-001"}, false)
+				if err != ColumnarIndexQueryError {
+					t.Errorf("expected ColumnarIndexQueryError, received %#v", err)
+				}
+
+				return
+			}
+
+			// Only temperature at (0,0) remains from robo-001, alongside
+			// both readings at (5,5) through (8,8) from robo-002
+			removed, err := d.Delete(&server.Query{Dataset: "site-a", IndexKey: "robot", IndexValue: "robo-002"}, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if removed != 8 {
+				t.Errorf("expected %d records removed, received %d", 8, removed)
+			}
+
+			if _, ok := d.indices["site-a"]["robot"].values["robo-002"]; ok {
+				t.Errorf("expected empty index value to be dropped")
+			}
+
+			records, err := d.RetrieveRecords(&server.Query{Dataset: "site-a", IndexKey: "robot", IndexValue: "robo-001"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(records) != 1 {
+				t.Errorf("expected %d indexed records, received %d", 1, len(records))
+			}
+		})
+	}
+}
+
+func TestDatabase_Delete_WAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "xyt.wal")
+
+	writeTestWAL(t, path)
+
+	w, err := OpenWAL(path, WALOptions{Policy: SyncAlways})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.UseWAL(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = d.Delete(&server.Query{Dataset: "site-a", X: &server.Query_XRange{XRange: &server.QueryRange{Start: 0, End: 3}}}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Dry runs aren't logged, and so never replayed
+	_, err = d.Delete(&server.Query{Dataset: "site-a"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.CreateDataset(&server.Schema{Dataset: "site-b", XMax: 10, YMax: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.DeleteDataset("site-b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, err = OpenWAL(path, WALOptions{Policy: SyncAlways})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer w.Close()
+
+	d, err = New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.UseWAL(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := d.RetrieveRecords(&server.Query{Dataset: "site-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 7 {
+		t.Errorf("expected 7 records after replay, received %d", len(records))
+	}
+
+	if _, ok := d.Datasets()["site-b"]; ok {
+		t.Errorf("expected site-b to stay deleted after replay")
+	}
+}
@@ -15,6 +15,7 @@ const (
 	positionX
 	positionY
 	positionTheta
+	positionZ
 )
 
 const (
@@ -35,6 +36,9 @@ func (p position) String() string {
 	case positionTheta:
 		return "T"
 
+	case positionZ:
+		return "Z"
+
 	default:
 		return ""
 	}
@@ -85,6 +89,44 @@ func (e InvalidCoordRangeError) Error() string {
 	)
 }
 
+// CorruptWALError is returned when replaying a WAL which contains
+// a damaged entry somewhere other than at the very end of the log
+type CorruptWALError struct {
+	offset int64
+}
+
+// Error returns the error string
+func (e CorruptWALError) Error() string {
+	return fmt.Sprintf("WAL is corrupt: bad entry at offset %d", e.offset)
+}
+
+// UnsupportedSnapshotVersionError is returned when trying to restore
+// a snapshot written in a format this version of xyt doesn't understand
+type UnsupportedSnapshotVersionError struct {
+	version uint16
+}
+
+// Error returns the error string
+func (e UnsupportedSnapshotVersionError) Error() string {
+	return fmt.Sprintf("unsupported snapshot version %d: expected %d", e.version, snapshotVersion)
+}
+
+// IndexCardinalityError is returned when inserting a Record would
+// push the number of distinct values for an index key past the limit
+// set on a Dataset
+type IndexCardinalityError struct {
+	dataset string
+	key     string
+	limit   uint32
+}
+
+// Error returns the error string
+func (e IndexCardinalityError) Error() string {
+	return fmt.Sprintf("index %q for %s has reached its cardinality limit of %d values",
+		e.key, e.dataset, e.limit,
+	)
+}
+
 var (
 	DuplicateDatasetError = errors.New("Dataset already exists")
 	EmptyRecordError      = errors.New("Record is empty, or otherwise nil")
@@ -94,4 +136,54 @@ var (
 	UnknownDatasetError   = errors.New("Unknown Dataset")
 	EmptySchemaError      = errors.New("Schema is empty, or otherwise nil")
 	UnsortedDataset       = errors.New("Selecting the latest record on an un-sorted dataset makes no sense")
+	InvalidThetaError     = errors.New("Theta must be a number of degrees, from 0 to 360")
+
+	InvalidTransformError  = errors.New("Transforms must have a positive cell size, and finite origins and rotations")
+	MissingTransformError  = errors.New("World coordinates need a dataset with a Transform")
+	InvalidWorldPointError = errors.New("World coordinates must be finite")
+
+	InvalidGeoError      = errors.New("Geo projections must be ENU, with a valid origin, or UTM, with a zone from 1 to 60, and any bounds must be a valid box")
+	MissingGeoError      = errors.New("Latitudes and longitudes need a dataset with a Geo projection")
+	InvalidGeoPointError = errors.New("Latitude must be from -90 to 90, and longitude from -180 to 180")
+
+	UnknownSyncPolicyError  = errors.New("Unknown WAL sync policy; expected one of always, batch, or interval")
+	InvalidWALOptionsError  = errors.New("WAL batch size and interval must be greater than zero for their respective sync policies")
+	UnknownWALEntryError    = errors.New("Unknown WAL entry type")
+	WALAlreadyAttachedError = errors.New("Database already has a WAL")
+
+	InvalidSnapshotError = errors.New("Snapshot is invalid, truncated, or not a snapshot at all")
+
+	InvalidDeleteQueryError = errors.New("Deletes can't use nearest, or latest record, queries")
+
+	IncompleteIndexQueryError = errors.New("Index queries require both an index key and an index value")
+	ColumnarIndexQueryError   = errors.New("Columnar datasets don't keep index values, and so can't be queried by index")
+	ColumnarZAxisError        = errors.New("Columnar datasets don't keep Z values, and so can't have a Z axis")
+	UnknownAggregationError   = errors.New("Unknown aggregation")
+
+	ConflictingSpatialQueryError = errors.New("Queries can't set both within and nearest")
+	InvalidRadiusError           = errors.New("Circle radius must not be negative")
+	InvalidNearestError          = errors.New("Nearest queries must ask for at least one location, with a non-negative maximum distance")
+	NearestSubscriptionError     = errors.New("Subscriptions can't use nearest queries")
+	InvalidPolygonError          = errors.New("Polygons must be made of rings of at least three finite vertices")
+
+	MissingZoneNameError = errors.New("Missing Zone name")
+	DuplicateZoneError   = errors.New("Zone already exists")
+	UnknownZoneError     = errors.New("Unknown Zone")
+
+	InvalidBucketWidthError  = errors.New("Time series bucket width must be greater than zero")
+	UnknownFillPolicyError   = errors.New("Unknown time series fill policy")
+	InvalidTimeRangeError    = errors.New("Time range must not end before it starts")
+	TooManyBucketsError      = errors.New("Time series would contain too many buckets; try a wider bucket or a narrower time range")
+	TooManyHeatmapCellsError = errors.New("Heatmap would contain too many cells; try a larger downsample")
+
+	InvalidRetentionError       = errors.New("Retention must not be negative")
+	SchemaShrinkError           = errors.New("Schema changes can't shrink a dataset's bounds, as that could drop records")
+	InvalidThetaResolutionError = errors.New("Theta resolution must be positive, and divide 360 degrees into a whole number of buckets")
+	InvalidRollupTierError      = errors.New("Rollup tiers must have a positive resolution, a non-negative retention, and be ordered from finest to coarsest")
+	InvalidRollupBucketError    = errors.New("Rollup bucket doesn't fit its dataset's schema")
+
+	UnknownSlowSubscriberPolicyError = errors.New("Unknown slow subscriber policy")
+	SlowSubscriberError              = errors.New("Subscriber fell too far behind, and was disconnected")
+	SubscriptionClosedError          = errors.New("Subscription is closed")
+	DatasetDeletedError              = errors.New("Subscription ended, as its dataset was deleted")
 )
@@ -57,3 +57,17 @@ func TestInvalidCoordRangeError_Error(t *testing.T) {
 		})
 	}
 }
+
+func TestIndexCardinalityError_Error(t *testing.T) {
+	expect := `index "robot" for my-test-dataset has reached its cardinality limit of 1000 values`
+
+	rcvd := IndexCardinalityError{
+		dataset: "my-test-dataset",
+		key:     "robot",
+		limit:   1000,
+	}.Error()
+
+	if expect != rcvd {
+		t.Errorf("expected %q, received %q", expect, rcvd)
+	}
+}
@@ -0,0 +1,297 @@
+package xyt
+
+import (
+	"math"
+
+	"github.com/xyt-db/xyt/server"
+)
+
+// WGS84 ellipsoid parameters
+const (
+	wgs84A  = 6_378_137.0
+	wgs84F  = 1 / 298.257223563
+	wgs84E2 = wgs84F * (2 - wgs84F)
+
+	// utmK0 is the scale factor along a UTM zone's central meridian
+	utmK0 = 0.9996
+)
+
+// geoEdgeSamples is how many points along each edge of a GeoBox are
+// projected when working out a dataset's bounds; projected edges curve,
+// so the corners alone can miss a little
+const geoEdgeSamples = 16
+
+// validGeo returns whether g describes a usable projection, or is unset
+func validGeo(g *server.Geo) bool {
+	if g == nil {
+		return true
+	}
+
+	switch g.Projection {
+	case server.Projection_ENU:
+		if !validLatLon(g.OriginLatitude, g.OriginLongitude) {
+			return false
+		}
+
+	case server.Projection_UTM:
+		if g.UtmZone < 1 || g.UtmZone > 60 {
+			return false
+		}
+
+	default:
+		return false
+	}
+
+	if b := g.Bounds; b != nil {
+		return validLatLon(b.MinLatitude, b.MinLongitude) && validLatLon(b.MaxLatitude, b.MaxLongitude) &&
+			b.MinLatitude < b.MaxLatitude && b.MinLongitude < b.MaxLongitude
+	}
+
+	return true
+}
+
+// validLatLon returns whether lat and lon are a real location
+func validLatLon(lat, lon float64) bool {
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
+
+// project converts a latitude and longitude into world coordinates,
+// in metres, as per g's projection
+func project(g *server.Geo, lat, lon float64) (x, y float64) {
+	if g.Projection == server.Projection_UTM {
+		return utmForward(g.UtmZone, g.South, lat, lon)
+	}
+
+	return enuForward(g.OriginLatitude, g.OriginLongitude, lat, lon)
+}
+
+// unproject converts world coordinates, in metres, back into a
+// latitude and longitude, as per g's projection
+func unproject(g *server.Geo, x, y float64) (lat, lon float64) {
+	if g.Projection == server.Projection_UTM {
+		return utmInverse(g.UtmZone, g.South, x, y)
+	}
+
+	return enuInverse(g.OriginLatitude, g.OriginLongitude, x, y)
+}
+
+// deriveGeoBounds sets s's X and Y bounds to cover s.Geo.Bounds, where
+// s has a geographic bounding box and no bounds of its own. s must already
+// have a valid Geo and Transform
+func deriveGeoBounds(s *server.Schema) {
+	if s.Geo == nil || s.Geo.Bounds == nil || s.Transform == nil || s.XMin != 0 || s.XMax != 0 || s.YMin != 0 || s.YMax != 0 {
+		return
+	}
+
+	b := s.Geo.Bounds
+
+	xMin, yMin := math.Inf(1), math.Inf(1)
+	xMax, yMax := math.Inf(-1), math.Inf(-1)
+
+	include := func(lat, lon float64) {
+		wx, wy := project(s.Geo, lat, lon)
+		gx, gy := toGrid(s.Transform, wx, wy)
+
+		xMin, xMax = min(xMin, gx), max(xMax, gx)
+		yMin, yMax = min(yMin, gy), max(yMax, gy)
+	}
+
+	for i := range geoEdgeSamples + 1 {
+		f := float64(i) / geoEdgeSamples
+
+		lat := b.MinLatitude + f*(b.MaxLatitude-b.MinLatitude)
+		lon := b.MinLongitude + f*(b.MaxLongitude-b.MinLongitude)
+
+		include(lat, b.MinLongitude)
+		include(lat, b.MaxLongitude)
+		include(b.MinLatitude, lon)
+		include(b.MaxLatitude, lon)
+	}
+
+	s.XMin, s.XMax = clampInt32(math.Floor(xMin)), clampInt32(math.Floor(xMax)+1)
+	s.YMin, s.YMax = clampInt32(math.Floor(yMin)), clampInt32(math.Floor(yMax)+1)
+}
+
+// validateGeo ensures any latitude and longitude r carries can be
+// projected into the dataset described by s
+func validateGeo(s *server.Schema, r *server.Record) error {
+	if r.Geo == nil {
+		return nil
+	}
+
+	if s.Geo == nil {
+		return MissingGeoError
+	}
+
+	if !validLatLon(r.Geo.Latitude, r.Geo.Longitude) {
+		return InvalidGeoPointError
+	}
+
+	return nil
+}
+
+// recordWorld returns r's world location, projecting r.Geo where set
+func recordWorld(s *server.Schema, r *server.Record) *server.WorldPoint {
+	if r.Geo == nil || s.Geo == nil {
+		return r.World
+	}
+
+	x, y := project(s.Geo, r.Geo.Latitude, r.Geo.Longitude)
+
+	return &server.WorldPoint{X: x, Y: y}
+}
+
+// geodeticToECEF converts a latitude and longitude, on the surface
+// of the ellipsoid, into earth-centred, earth-fixed, coordinates
+func geodeticToECEF(lat, lon, h float64) (x, y, z float64) {
+	sinLat, cosLat := math.Sincos(lat * math.Pi / 180)
+	sinLon, cosLon := math.Sincos(lon * math.Pi / 180)
+
+	n := wgs84A / math.Sqrt(1-wgs84E2*sinLat*sinLat)
+
+	return (n + h) * cosLat * cosLon, (n + h) * cosLat * sinLon, (n*(1-wgs84E2) + h) * sinLat
+}
+
+// ecefToGeodetic is the inverse of geodeticToECEF
+func ecefToGeodetic(x, y, z float64) (lat, lon, h float64) {
+	p := math.Hypot(x, y)
+	phi := math.Atan2(z, p*(1-wgs84E2))
+
+	// A handful of iterations is plenty for anything near the surface
+	for range 5 {
+		sin, cos := math.Sincos(phi)
+		n := wgs84A / math.Sqrt(1-wgs84E2*sin*sin)
+
+		h = p/cos - n
+		phi = math.Atan2(z, p*(1-wgs84E2*n/(n+h)))
+	}
+
+	return phi * 180 / math.Pi, math.Atan2(y, x) * 180 / math.Pi, h
+}
+
+// enuForward projects a latitude and longitude onto the plane touching
+// the ellipsoid at the origin, returning east and north in metres
+func enuForward(lat0, lon0, lat, lon float64) (e, n float64) {
+	x0, y0, z0 := geodeticToECEF(lat0, lon0, 0)
+	x, y, z := geodeticToECEF(lat, lon, 0)
+
+	e, n, _ = ecefToENU(lat0, lon0, x-x0, y-y0, z-z0)
+
+	return
+}
+
+// enuInverse is the inverse of enuForward, finding the point on the
+// surface of the ellipsoid which sits at east and north
+func enuInverse(lat0, lon0, e, n float64) (lat, lon float64) {
+	x0, y0, z0 := geodeticToECEF(lat0, lon0, 0)
+
+	// Points on the plane sit above the ellipsoid, so walk down towards
+	// the surface until the height is negligible
+	var u, h float64
+	for range 5 {
+		dx, dy, dz := enuToECEF(lat0, lon0, e, n, u)
+
+		lat, lon, h = ecefToGeodetic(x0+dx, y0+dy, z0+dz)
+		u -= h
+	}
+
+	return
+}
+
+func ecefToENU(lat0, lon0, dx, dy, dz float64) (e, n, u float64) {
+	sinLat, cosLat := math.Sincos(lat0 * math.Pi / 180)
+	sinLon, cosLon := math.Sincos(lon0 * math.Pi / 180)
+
+	e = -sinLon*dx + cosLon*dy
+	n = -sinLat*cosLon*dx - sinLat*sinLon*dy + cosLat*dz
+	u = cosLat*cosLon*dx + cosLat*sinLon*dy + sinLat*dz
+
+	return
+}
+
+func enuToECEF(lat0, lon0, e, n, u float64) (dx, dy, dz float64) {
+	sinLat, cosLat := math.Sincos(lat0 * math.Pi / 180)
+	sinLon, cosLon := math.Sincos(lon0 * math.Pi / 180)
+
+	dx = -sinLon*e - sinLat*cosLon*n + cosLat*cosLon*u
+	dy = cosLon*e - sinLat*sinLon*n + cosLat*sinLon*u
+	dz = cosLat*n + sinLat*u
+
+	return
+}
+
+// utmCentralMeridian returns the central meridian of zone, in degrees
+func utmCentralMeridian(zone uint32) float64 {
+	return float64(zone)*6 - 183
+}
+
+// meridianArc returns the distance along the meridian from the
+// equator to phi, in radians, on the ellipsoid
+func meridianArc(phi float64) float64 {
+	e4, e6 := wgs84E2*wgs84E2, wgs84E2*wgs84E2*wgs84E2
+
+	return wgs84A * ((1-wgs84E2/4-3*e4/64-5*e6/256)*phi -
+		(3*wgs84E2/8+3*e4/32+45*e6/1024)*math.Sin(2*phi) +
+		(15*e4/256+45*e6/1024)*math.Sin(4*phi) -
+		(35*e6/3072)*math.Sin(6*phi))
+}
+
+// utmForward projects a latitude and longitude into a UTM zone, returning
+// eastings and northings in metres, as per Snyder's series
+func utmForward(zone uint32, south bool, lat, lon float64) (easting, northing float64) {
+	ep2 := wgs84E2 / (1 - wgs84E2)
+
+	phi := lat * math.Pi / 180
+	sin, cos := math.Sincos(phi)
+
+	n := wgs84A / math.Sqrt(1-wgs84E2*sin*sin)
+	t := math.Tan(phi) * math.Tan(phi)
+	c := ep2 * cos * cos
+	a := cos * (lon - utmCentralMeridian(zone)) * math.Pi / 180
+
+	easting = utmK0*n*(a+(1-t+c)*math.Pow(a, 3)/6+(5-18*t+t*t+72*c-58*ep2)*math.Pow(a, 5)/120) + 500_000
+	northing = utmK0 * (meridianArc(phi) + n*math.Tan(phi)*(a*a/2+(5-t+9*c+4*c*c)*math.Pow(a, 4)/24+(61-58*t+t*t+600*c-330*ep2)*math.Pow(a, 6)/720))
+
+	if south {
+		northing += 10_000_000
+	}
+
+	return
+}
+
+// utmInverse is the inverse of utmForward
+func utmInverse(zone uint32, south bool, easting, northing float64) (lat, lon float64) {
+	ep2 := wgs84E2 / (1 - wgs84E2)
+	e1 := (1 - math.Sqrt(1-wgs84E2)) / (1 + math.Sqrt(1-wgs84E2))
+
+	if south {
+		northing -= 10_000_000
+	}
+
+	e4, e6 := wgs84E2*wgs84E2, wgs84E2*wgs84E2*wgs84E2
+	mu := northing / utmK0 / (wgs84A * (1 - wgs84E2/4 - 3*e4/64 - 5*e6/256))
+
+	// phi1 is the footpoint latitude; the latitude on the central
+	// meridian with the same northing
+	phi1 := mu + (3*e1/2-27*math.Pow(e1, 3)/32)*math.Sin(2*mu) +
+		(21*e1*e1/16-55*math.Pow(e1, 4)/32)*math.Sin(4*mu) +
+		(151*math.Pow(e1, 3)/96)*math.Sin(6*mu) +
+		(1097*math.Pow(e1, 4)/512)*math.Sin(8*mu)
+
+	sin, cos := math.Sincos(phi1)
+
+	n1 := wgs84A / math.Sqrt(1-wgs84E2*sin*sin)
+	t1 := math.Tan(phi1) * math.Tan(phi1)
+	c1 := ep2 * cos * cos
+	r1 := wgs84A * (1 - wgs84E2) / math.Pow(1-wgs84E2*sin*sin, 1.5)
+	d := (easting - 500_000) / (n1 * utmK0)
+
+	phi := phi1 - (n1*math.Tan(phi1)/r1)*(d*d/2-
+		(5+3*t1+10*c1-4*c1*c1-9*ep2)*math.Pow(d, 4)/24+
+		(61+90*t1+298*c1+45*t1*t1-252*ep2-3*c1*c1)*math.Pow(d, 6)/720)
+
+	lambda := (d - (1+2*t1+c1)*math.Pow(d, 3)/6 +
+		(5-2*c1+28*t1-3*c1*c1+8*ep2+24*t1*t1)*math.Pow(d, 5)/120) / cos
+
+	return phi * 180 / math.Pi, utmCentralMeridian(zone) + lambda*180/math.Pi
+}
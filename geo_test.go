@@ -0,0 +1,199 @@
+package xyt
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestProject(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		geo      *server.Geo
+		lat, lon float64
+		x, y     float64
+	}{
+		{"UTM on the equator and central meridian", &server.Geo{Projection: server.Projection_UTM, UtmZone: 31}, 0, 3, 500_000, 0},
+		{"UTM on the central meridian", &server.Geo{Projection: server.Projection_UTM, UtmZone: 18}, 45, -75, 500_000, 4_982_950.4},
+		{"UTM in the southern hemisphere", &server.Geo{Projection: server.Projection_UTM, UtmZone: 31, South: true}, 0, 3, 500_000, 10_000_000},
+		{"ENU at the origin", &server.Geo{OriginLatitude: 51.5, OriginLongitude: -0.1}, 51.5, -0.1, 0, 0},
+		{"ENU along the equator", &server.Geo{}, 0, 0.001, 111.3195, 0},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			x, y := project(test.geo, test.lat, test.lon)
+			if math.Abs(x-test.x) > 0.5 || math.Abs(y-test.y) > 0.5 {
+				t.Errorf("expected (%v,%v), received (%v,%v)", test.x, test.y, x, y)
+			}
+		})
+	}
+}
+
+func TestProject_RoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		geo      *server.Geo
+		lat, lon float64
+	}{
+		{"ENU", &server.Geo{OriginLatitude: 51.5, OriginLongitude: -0.1}, 51.52, -0.13},
+		{"ENU south of the equator", &server.Geo{OriginLatitude: -33.9, OriginLongitude: 151.2}, -33.85, 151.25},
+		{"UTM", &server.Geo{Projection: server.Projection_UTM, UtmZone: 30}, 51.5, -3.2},
+		{"UTM at a zone's edge", &server.Geo{Projection: server.Projection_UTM, UtmZone: 30}, 60, -6},
+		{"UTM south of the equator", &server.Geo{Projection: server.Projection_UTM, UtmZone: 56, South: true}, -33.85, 151.25},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			x, y := project(test.geo, test.lat, test.lon)
+
+			lat, lon := unproject(test.geo, x, y)
+			if math.Abs(lat-test.lat) > 1e-7 || math.Abs(lon-test.lon) > 1e-7 {
+				t.Errorf("expected (%v,%v), received (%v,%v)", test.lat, test.lon, lat, lon)
+			}
+		})
+	}
+}
+
+func TestDatabase_Geo(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name        string
+		schema      *server.Schema
+		expectError error
+	}{
+		{"Geo needs a transform", &server.Schema{Dataset: "a", XMax: 10, YMax: 10, Geo: &server.Geo{}}, MissingTransformError},
+		{"UTM needs a zone", &server.Schema{Dataset: "b", XMax: 10, YMax: 10, Transform: &server.Transform{CellSize: 1}, Geo: &server.Geo{Projection: server.Projection_UTM}}, InvalidGeoError},
+		{"ENU needs a valid origin", &server.Schema{Dataset: "c", XMax: 10, YMax: 10, Transform: &server.Transform{CellSize: 1}, Geo: &server.Geo{OriginLatitude: 91}}, InvalidGeoError},
+		{"Bounds must be a box", &server.Schema{Dataset: "d", XMax: 10, YMax: 10, Transform: &server.Transform{CellSize: 1}, Geo: &server.Geo{Bounds: &server.GeoBox{MinLatitude: 1, MaxLatitude: 1, MaxLongitude: 1}}}, InvalidGeoError},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := d.CreateDataset(test.schema)
+			if err != test.expectError {
+				t.Errorf("expected %#v, received %#v", test.expectError, err)
+			}
+		})
+	}
+
+	// Ten metre cells, over roughly a couple of kilometres
+	// either way of the origin
+	err = d.CreateDataset(&server.Schema{
+		Dataset:   "site-a",
+		Transform: &server.Transform{CellSize: 10},
+		Geo: &server.Geo{
+			OriginLatitude:  51.5,
+			OriginLongitude: -0.1,
+			Bounds: &server.GeoBox{
+				MinLatitude:  51.48,
+				MinLongitude: -0.13,
+				MaxLatitude:  51.52,
+				MaxLongitude: -0.07,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.CreateDataset(&server.Schema{Dataset: "site-b", XMax: 10, YMax: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := d.Datasets()["site-a"]
+	if s.XMin != -209 || s.XMax != 209 || s.YMin != -223 || s.YMax != 223 {
+		t.Errorf("expected bounds (-209,-223) to (209,223), received (%d,%d) to (%d,%d)", s.XMin, s.YMin, s.XMax, s.YMax)
+	}
+
+	for _, test := range []struct {
+		name        string
+		record      *server.Record
+		expectX     int32
+		expectY     int32
+		expectError error
+	}{
+		{"Latitudes and longitudes are projected", &server.Record{Dataset: "site-a", Geo: &server.GeoPoint{Latitude: 51.5005, Longitude: -0.0995}}, 3, 5, nil},
+		{"Latitudes and longitudes replace world locations", &server.Record{Dataset: "site-a", World: &server.WorldPoint{X: 1000}, Geo: &server.GeoPoint{Latitude: 51.4995, Longitude: -0.1005}}, -4, -6, nil},
+		{"Grid locations are left alone", &server.Record{Dataset: "site-a", X: 100, Y: 100}, 100, 100, nil},
+		{"Latitudes and longitudes outside the grid fail", &server.Record{Dataset: "site-a", Geo: &server.GeoPoint{Latitude: 51.6, Longitude: -0.1}}, 0, 0, PositionOutOfBoundsError{dataset: "site-a", position: positionY, min: -223, max: 222, received: 1112}},
+		{"Latitudes and longitudes must be valid", &server.Record{Dataset: "site-a", Geo: &server.GeoPoint{Latitude: 91}}, 0, 0, InvalidGeoPointError},
+		{"Latitudes and longitudes need a projection", &server.Record{Dataset: "site-b", Geo: &server.GeoPoint{}}, 0, 0, MissingGeoError},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			test.record.Name = "temperature"
+			test.record.Value = 21.5
+			test.record.Meta = &server.Metadata{When: timestamppb.New(time.Unix(1000, 0))}
+
+			err := d.InsertRecord(test.record)
+			if err != test.expectError {
+				t.Fatalf("expected %#v, received %#v", test.expectError, err)
+			}
+
+			if err == nil && (test.record.X != test.expectX || test.record.Y != test.expectY) {
+				t.Errorf("expected (%d,%d), received (%d,%d)", test.expectX, test.expectY, test.record.X, test.record.Y)
+			}
+		})
+	}
+
+	t.Run("GeoJSON", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+
+		err := d.GeoJSON(&server.Query{Dataset: "site-a", X: &server.Query_XAll{XAll: true}, Y: &server.Query_YAll{YAll: true}, T: &server.Query_TAll{TAll: true}, Time: &server.Query_TimeAll{TimeAll: true}}, buf)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		var fc struct {
+			Type     string
+			Features []struct {
+				Geometry struct {
+					Type        string
+					Coordinates []float64
+				}
+				Properties map[string]any
+			}
+		}
+
+		err = json.Unmarshal(buf.Bytes(), &fc)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if fc.Type != "FeatureCollection" {
+			t.Errorf("expected a FeatureCollection, received %q", fc.Type)
+		}
+
+		if len(fc.Features) != 3 {
+			t.Fatalf("expected %d features, received %d", 3, len(fc.Features))
+		}
+
+		// Features come back in location order, and are placed at their
+		// latitude and longitude, or their cell's centre, longitude first
+		for i, expect := range [][2]float64{{-0.1005, 51.4995}, {-0.0995, 51.5005}, {-0.0855, 51.50905}} {
+			f := fc.Features[i]
+			if f.Geometry.Type != "Point" {
+				t.Errorf("%d: expected a Point, received %q", i, f.Geometry.Type)
+			}
+
+			if math.Abs(f.Geometry.Coordinates[0]-expect[0]) > 1e-4 || math.Abs(f.Geometry.Coordinates[1]-expect[1]) > 1e-4 {
+				t.Errorf("%d: expected %v, received %v", i, expect, f.Geometry.Coordinates)
+			}
+
+			if f.Properties["value"] != 21.5 {
+				t.Errorf("%d: expected value %v, received %#v", i, 21.5, f.Properties["value"])
+			}
+		}
+	})
+
+	t.Run("GeoJSON needs a projection", func(t *testing.T) {
+		err := d.GeoJSON(&server.Query{Dataset: "site-b"}, new(bytes.Buffer))
+		if err != MissingGeoError {
+			t.Errorf("expected MissingGeoError, received %#v", err)
+		}
+	})
+}
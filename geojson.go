@@ -0,0 +1,156 @@
+package xyt
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"math"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+)
+
+type geoJSONFeature struct {
+	Type       string            `json:"type"`
+	Geometry   geoJSONPoint      `json:"geometry"`
+	Properties geoJSONProperties `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+type geoJSONProperties struct {
+	Dataset string            `json:"dataset"`
+	Name    string            `json:"name"`
+	Value   *float64          `json:"value"`
+	X       int32             `json:"x"`
+	Y       int32             `json:"y"`
+	Z       int32             `json:"z"`
+	T       int32             `json:"t"`
+	When    string            `json:"when"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Indices map[string]string `json:"indices,omitempty"`
+}
+
+// GeoJSON writes every record q matches to w as a GeoJSON FeatureCollection,
+// in the same order RetrieveRecords returns them, with each record as a Point
+// feature and its value, location, and metadata as that feature's properties.
+//
+// Records are placed at the latitude and longitude they were inserted with
+// or, failing that, at their world location, or the centre of their cell,
+// projected back onto the globe. Values which JSON can't represent, such as
+// NaN, are written as null.
+//
+// The dataset must have a Geo projection, otherwise MissingGeoError is returned.
+//
+// Matching records are gathered before anything is written, so that a slow
+// w never holds up inserts into the dataset
+func (d *Database) GeoJSON(q *server.Query, w io.Writer) (err error) {
+	schema, records, err := d.geoJSONRecords(q)
+	if err != nil {
+		return
+	}
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	_, err = io.WriteString(bw, `{"type":"FeatureCollection","features":[`)
+	if err != nil {
+		return
+	}
+
+	for i, r := range records {
+		if i > 0 {
+			_, err = io.WriteString(bw, ",")
+			if err != nil {
+				return
+			}
+		}
+
+		err = enc.Encode(geoJSONRecord(schema, r))
+		if err != nil {
+			return
+		}
+	}
+
+	_, err = io.WriteString(bw, "]}\n")
+	if err != nil {
+		return
+	}
+
+	return bw.Flush()
+}
+
+// geoJSONRecords returns every record q matches, along with the
+// schema of the dataset they're from, for GeoJSON to write out
+func (d *Database) geoJSONRecords(q *server.Query) (schema *server.Schema, records []*server.Record, err error) {
+	unlock := d.rlock(q.GetDataset())
+	defer unlock()
+
+	err = d.validateQuery(q)
+	if err != nil {
+		return
+	}
+
+	schema = d.schemata[q.Dataset]
+	if schema.Geo == nil {
+		return nil, nil, MissingGeoError
+	}
+
+	err = d.walk(q, func(r *server.Record, _ *aggregator) {
+		records = append(records, r)
+	})
+
+	return
+}
+
+// geoJSONRecord turns r into a GeoJSON feature, using the projection
+// from s to place it
+func geoJSONRecord(s *server.Schema, r *server.Record) (f geoJSONFeature) {
+	var lat, lon float64
+
+	switch {
+	case r.Geo != nil:
+		lat, lon = r.Geo.Latitude, r.Geo.Longitude
+
+	default:
+		w := r.World
+		if w == nil {
+			w = cellCentre(s.Transform, r.X, r.Y)
+		}
+
+		lat, lon = unproject(s.Geo, w.X, w.Y)
+	}
+
+	f.Type = "Feature"
+	f.Geometry = geoJSONPoint{
+		Type: "Point",
+
+		// GeoJSON positions are longitude first
+		Coordinates: [2]float64{lon, lat},
+	}
+
+	f.Properties = geoJSONProperties{
+		Dataset: r.Dataset,
+		Name:    r.Name,
+		X:       r.X,
+		Y:       r.Y,
+		Z:       r.Z,
+		T:       r.T,
+	}
+
+	if !math.IsNaN(r.Value) && !math.IsInf(r.Value, 0) {
+		v := r.Value
+		f.Properties.Value = &v
+	}
+
+	if r.Meta != nil {
+		f.Properties.When = r.Meta.When.AsTime().Format(time.RFC3339Nano)
+		f.Properties.Labels = r.Meta.Labels
+		f.Properties.Indices = r.Meta.Indices
+	}
+
+	return
+}
@@ -0,0 +1,207 @@
+package xyt
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/xyt-db/xyt/server"
+)
+
+// A grid holds every cell within a single dataset.
+//
+// Cells are always addressed by their absolute (X,Y) location; grids
+// handle any translation to wherever they actually keep cells
+type grid interface {
+	// get returns the cell at (x,y), or nil where that
+	// location has never been allocated
+	get(x, y int32) *cell
+
+	// allocate returns the cell at (x,y), creating it where necessary
+	allocate(x, y int32) *cell
+
+	// each passes every allocated cell within the (exclusive) ranges
+	// passed to fn, in X then Y order
+	each(xMin, xMax, yMin, yMax int32, fn func(x, y int32, c *cell))
+
+	// prune drops cells which no longer hold anything, where
+	// the grid can do so without changing its behaviour
+	prune()
+
+	// resize returns a grid covering s's bounds, which must contain
+	// the grid's current bounds, holding the same cells. Cells are moved
+	// rather than copied, so the original grid mustn't be used afterwards
+	resize(s *server.Schema) grid
+}
+
+// newGrid returns the right sort of grid for schema
+func newGrid(s *server.Schema) grid {
+	if s.Sparse {
+		return &sparseGrid{cells: make(map[cellKey]*cell)}
+	}
+
+	g := &denseGrid{schema: s, cells: make([][]*cell, s.XMax-s.XMin)}
+	for xi := range g.cells {
+		g.cells[xi] = make([]*cell, s.YMax-s.YMin)
+
+		// Lazily allocated datasets leave cells nil until they're
+		// first inserted into
+		if s.LazyInitialAllocate {
+			continue
+		}
+
+		for yi := range g.cells[xi] {
+			g.cells[xi][yi] = newCell()
+		}
+	}
+
+	return g
+}
+
+// A denseGrid holds a slot for every location within a schema's bounds,
+// as per:
+//
+//	[X-XMin][Y-YMin]
+type denseGrid struct {
+	schema *server.Schema
+	cells  [][]*cell
+}
+
+func (g *denseGrid) get(x, y int32) *cell {
+	xi, yi := offset(g.schema, x, y)
+
+	return g.cells[xi][yi]
+}
+
+func (g *denseGrid) allocate(x, y int32) *cell {
+	xi, yi := offset(g.schema, x, y)
+
+	c := g.cells[xi][yi]
+	if c == nil {
+		c = newCell()
+		g.cells[xi][yi] = c
+	}
+
+	return c
+}
+
+func (g *denseGrid) each(xMin, xMax, yMin, yMax int32, fn func(x, y int32, c *cell)) {
+	for x := xMin; x < xMax; x++ {
+		for y := yMin; y < yMax; y++ {
+			if c := g.get(x, y); c != nil {
+				fn(x, y, c)
+			}
+		}
+	}
+}
+
+// prune does nothing for dense grids; datasets which aren't lazily
+// allocated expect every cell to exist, and lazily allocated datasets
+// are expected to revisit the same locations
+func (g *denseGrid) prune() {}
+
+func (g *denseGrid) resize(s *server.Schema) grid {
+	ng := &denseGrid{schema: s, cells: make([][]*cell, s.XMax-s.XMin)}
+	for xi := range ng.cells {
+		ng.cells[xi] = make([]*cell, s.YMax-s.YMin)
+	}
+
+	g.each(g.schema.XMin, g.schema.XMax, g.schema.YMin, g.schema.YMax, func(x, y int32, c *cell) {
+		xi, yi := offset(s, x, y)
+		ng.cells[xi][yi] = c
+	})
+
+	if s.LazyInitialAllocate {
+		return ng
+	}
+
+	for xi := range ng.cells {
+		for yi, c := range ng.cells[xi] {
+			if c == nil {
+				ng.cells[xi][yi] = newCell()
+			}
+		}
+	}
+
+	return ng
+}
+
+// A sparseGrid only holds the locations which have actually been
+// inserted into, which suits huge grids where only a sliver of
+// locations are ever visited
+type sparseGrid struct {
+	cells map[cellKey]*cell
+}
+
+func (g *sparseGrid) get(x, y int32) *cell {
+	return g.cells[cellKey{x, y}]
+}
+
+func (g *sparseGrid) allocate(x, y int32) *cell {
+	k := cellKey{x, y}
+
+	c, ok := g.cells[k]
+	if !ok {
+		c = newCell()
+		g.cells[k] = c
+	}
+
+	return c
+}
+
+func (g *sparseGrid) each(xMin, xMax, yMin, yMax int32, fn func(x, y int32, c *cell)) {
+	if xMin >= xMax || yMin >= yMax {
+		return
+	}
+
+	// Small ranges, such as single locations, are quicker to look up
+	// directly than by scanning every occupied cell
+	if area := (int64(xMax) - int64(xMin)) * (int64(yMax) - int64(yMin)); area <= int64(len(g.cells)) {
+		for x := xMin; x < xMax; x++ {
+			for y := yMin; y < yMax; y++ {
+				if c := g.get(x, y); c != nil {
+					fn(x, y, c)
+				}
+			}
+		}
+
+		return
+	}
+
+	keys := make([]cellKey, 0)
+	for k := range g.cells {
+		if k[0] >= xMin && k[0] < xMax && k[1] >= yMin && k[1] < yMax {
+			keys = append(keys, k)
+		}
+	}
+
+	sortCellKeys(keys)
+
+	for _, k := range keys {
+		fn(k[0], k[1], g.cells[k])
+	}
+}
+
+// resize returns g as it is; sparse grids address cells by their
+// absolute location, and so don't care about bounds at all
+func (g *sparseGrid) resize(*server.Schema) grid {
+	return g
+}
+
+func (g *sparseGrid) prune() {
+	for k, c := range g.cells {
+		if len(c.series) == 0 {
+			delete(g.cells, k)
+		}
+	}
+}
+
+// sortCellKeys sorts keys into X then Y order
+func sortCellKeys(keys []cellKey) {
+	slices.SortFunc(keys, func(a, b cellKey) int {
+		if c := cmp.Compare(a[0], b[0]); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a[1], b[1])
+	})
+}
@@ -0,0 +1,129 @@
+package xyt
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestDatabase_Sparse(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Far too big to allocate densely, even lazily
+	err = d.CreateDataset(&server.Schema{
+		Dataset:      "yard",
+		XMin:         -100_000,
+		XMax:         100_000,
+		YMin:         -100_000,
+		YMax:         100_000,
+		SortOnInsert: true,
+		Sparse:       true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A robot driving down a single lane, and back again
+	for i := int32(0); i < 200; i++ {
+		x := i - 100
+		if i >= 100 {
+			x = 99 - (i - 100)
+		}
+
+		err = d.InsertRecord(&server.Record{
+			Meta:    &server.Metadata{When: timestamppb.New(time.Unix(int64(i), 0))},
+			Dataset: "yard",
+			Name:    "temperature",
+			Value:   float64(i),
+			X:       x * 1000,
+			Y:       -50_000,
+			T:       90,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n := len(d.data["yard"].(*sparseGrid).cells); n != 200 {
+		t.Errorf("expected 200 occupied cells, received %d", n)
+	}
+
+	for _, test := range []struct {
+		name        string
+		query       *server.Query
+		expectCount int
+	}{
+		{"Everything", &server.Query{}, 200},
+		{"A single location", &server.Query{X: &server.Query_XValue{XValue: -100_000}, Y: &server.Query_YValue{YValue: -50_000}}, 1},
+		{"An empty location", &server.Query{X: &server.Query_XValue{XValue: 1}, Y: &server.Query_YValue{YValue: 1}}, 0},
+		{"Half the lane", &server.Query{X: &server.Query_XRange{XRange: &server.QueryRange{Start: 0, End: 100_000}}}, 100},
+		{"The wrong side of the yard", &server.Query{Y: &server.Query_YRange{YRange: &server.QueryRange{Start: 0, End: 100_000}}}, 0},
+		{"Latest", &server.Query{Time: &server.Query_TimeLatest{TimeLatest: true}}, 200},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			test.query.Dataset = "yard"
+
+			records, err := d.RetrieveRecords(test.query)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if test.expectCount != len(records) {
+				t.Fatalf("expected %d records, received %d", test.expectCount, len(records))
+			}
+
+			// Records must come back in the same X then Y order
+			// a dense dataset would return them in
+			for i := 1; i < len(records); i++ {
+				if records[i].X < records[i-1].X {
+					t.Errorf("record %d: X %d follows %d", i, records[i].X, records[i-1].X)
+				}
+			}
+		})
+	}
+
+	t.Run("Snapshots", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+
+		err := d.Snapshot(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		restored, err := New()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = restored.Restore(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		records, err := restored.RetrieveRecords(&server.Query{Dataset: "yard"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(records) != 200 {
+			t.Errorf("expected 200 records, received %d", len(records))
+		}
+	})
+
+	t.Run("Truncating drops empty cells", func(t *testing.T) {
+		_, err := d.Truncate("yard", time.Unix(150, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n := len(d.data["yard"].(*sparseGrid).cells); n != 50 {
+			t.Errorf("expected 50 occupied cells, received %d", n)
+		}
+	})
+}
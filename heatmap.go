@@ -0,0 +1,112 @@
+package xyt
+
+import (
+	"github.com/xyt-db/xyt/server"
+)
+
+// MaxHeatmapCells is the largest number of values a single heatmap may
+// hold, to stop a huge sparse dataset from allocating a value for every
+// one of its cells
+const MaxHeatmapCells = 4096 * 4096
+
+// Heatmap aggregates every record for a single Name, optionally within a
+// time window, into a dense grid with one value per location, such as for
+// rendering what a given reading looks like across a whole dataset.
+//
+// Setting Downsample groups that many cells along each axis into each
+// value, so that a 1000x1000 dataset downsampled by 10 becomes a 100x100
+// heatmap. Where a dataset doesn't divide evenly, the final row and column
+// of the heatmap cover fewer cells.
+//
+// Heatmaps are built a column of buckets at a time, so only a single column
+// of running aggregates is ever held in memory, however large the dataset.
+// The heatmap itself is held in full, and so may not hold more than
+// MaxHeatmapCells values; larger datasets need downsampling.
+func (d *Database) Heatmap(hr *server.HeatmapRequest) (h *server.HeatmapResponse, err error) {
+	if hr == nil || hr.Dataset == "" {
+		return nil, MissingDatasetError
+	}
+
+	if hr.Name == "" {
+		return nil, MissingFieldNameError
+	}
+
+	if !validAggregation(hr.Aggregation) {
+		return nil, UnknownAggregationError
+	}
+
+	// Holding the lock across every column means the whole
+	// heatmap is built from the same view of the dataset
+	unlock := d.rlock(hr.Dataset)
+	defer unlock()
+
+	schema, ok := d.schemata[hr.Dataset]
+	if !ok {
+		return nil, UnknownDatasetError
+	}
+
+	ds := max(int32(hr.Downsample), 1) // #nosec: G115
+
+	// Sparse datasets can be far too large to hold a value for every
+	// cell of, and so need downsampling; working this out in 64 bits
+	// means huge datasets can't overflow their way past the limit
+	width := ceilDiv64(int64(schema.XMax)-int64(schema.XMin), int64(ds))
+	height := ceilDiv64(int64(schema.YMax)-int64(schema.YMin), int64(ds))
+
+	if width*height > MaxHeatmapCells {
+		return nil, TooManyHeatmapCellsError
+	}
+
+	// #nosec: G115
+	h = &server.HeatmapResponse{
+		Width:      uint32(width),
+		Height:     uint32(height),
+		XMin:       schema.XMin,
+		YMin:       schema.YMin,
+		Downsample: uint32(ds),
+	}
+
+	h.Values = make([]float64, h.Width*h.Height)
+	h.Counts = make([]uint64, h.Width*h.Height)
+
+	q := &server.Query{
+		Dataset: hr.Dataset,
+		Names:   []string{hr.Name},
+	}
+
+	if hr.TimeRange != nil {
+		q.Time = &server.Query_TimeRange{TimeRange: hr.TimeRange}
+	}
+
+	column := make([]aggregator, h.Height)
+
+	for bx := int32(0); bx < int32(h.Width); bx++ {
+		clear(column)
+
+		// Schemas may span more than an int32 can hold, so
+		// offsets into them are worked out in 64 bits
+		xStart := int64(schema.XMin) + int64(bx)*int64(ds)
+
+		// #nosec: G115
+		q.X = &server.Query_XRange{XRange: &server.QueryRange{
+			Start: int32(xStart),
+			End:   int32(min(xStart+int64(ds), int64(schema.XMax))),
+		}}
+
+		err = d.walk(q, func(r *server.Record, rolled *aggregator) {
+			column[(int64(r.Y)-int64(schema.YMin))/int64(ds)].visit(r, rolled)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for by := range column {
+			i := by*int(h.Width) + int(bx)
+
+			h.Values[i] = column[by].value(hr.Aggregation)
+			h.Counts[i] = column[by].count
+		}
+	}
+
+	return
+}
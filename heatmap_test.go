@@ -0,0 +1,193 @@
+package xyt
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestDatabase_Heatmap(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.CreateDataset(&server.Schema{
+		Dataset: "site-a",
+		XMax:    5,
+		YMax:    4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := time.Now()
+
+	// Every location gets a temperature of x + y, the first column gets
+	// a second, older, temperature and everything gets a voltage reading
+	// which should never show up
+	for x := int32(0); x < 5; x++ {
+		for y := int32(0); y < 4; y++ {
+			for _, r := range []*server.Record{
+				{Name: "temperature", Value: float64(x + y), Meta: &server.Metadata{When: timestamppb.New(ts)}},
+				{Name: "voltage", Value: 1000, Meta: &server.Metadata{When: timestamppb.New(ts)}},
+			} {
+				r.Dataset = "site-a"
+				r.X = x
+				r.Y = y
+
+				err = d.InsertRecord(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+
+	for y := int32(0); y < 4; y++ {
+		err = d.InsertRecord(&server.Record{
+			Meta:    &server.Metadata{When: timestamppb.New(ts.Add(-time.Hour))},
+			Dataset: "site-a",
+			Name:    "temperature",
+			Value:   100,
+			X:       0,
+			Y:       y,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	nan := math.NaN()
+
+	for _, test := range []struct {
+		name         string
+		req          *server.HeatmapRequest
+		expectWidth  uint32
+		expectHeight uint32
+		expectValues []float64
+		expectError  bool
+	}{
+		{"Nil requests error", nil, 0, 0, nil, true},
+		{"Missing names error", &server.HeatmapRequest{Dataset: "site-a"}, 0, 0, nil, true},
+		{"Unknown datasets error", &server.HeatmapRequest{Dataset: "site-b", Name: "temperature"}, 0, 0, nil, true},
+		{"Unknown aggregations error", &server.HeatmapRequest{Dataset: "site-a", Name: "temperature", Aggregation: 100}, 0, 0, nil, true},
+		{"Full resolution heatmaps return every cell", &server.HeatmapRequest{Dataset: "site-a", Name: "temperature", Aggregation: server.Aggregation_Max}, 5, 4, []float64{
+			100, 1, 2, 3, 4,
+			100, 2, 3, 4, 5,
+			100, 3, 4, 5, 6,
+			100, 4, 5, 6, 7,
+		}, false},
+		{"Time ranges are respected", &server.HeatmapRequest{Dataset: "site-a", Name: "temperature", Aggregation: server.Aggregation_Max, TimeRange: &server.TimeRange{Start: timestamppb.New(ts.Add(-time.Minute)), End: timestamppb.New(ts.Add(time.Minute))}}, 5, 4, []float64{
+			0, 1, 2, 3, 4,
+			1, 2, 3, 4, 5,
+			2, 3, 4, 5, 6,
+			3, 4, 5, 6, 7,
+		}, false},
+		{"Downsampled heatmaps group cells, with partial buckets at the edges", &server.HeatmapRequest{Dataset: "site-a", Name: "temperature", Aggregation: server.Aggregation_Count, Downsample: 2}, 3, 2, []float64{
+			6, 4, 2,
+			6, 4, 2,
+		}, false},
+		{"Unknown names return empty buckets", &server.HeatmapRequest{Dataset: "site-a", Name: "flurbles", Aggregation: server.Aggregation_Mean, Downsample: 3}, 2, 2, []float64{
+			nan, nan,
+			nan, nan,
+		}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			h, err := d.Heatmap(test.req)
+			if err == nil && test.expectError {
+				t.Errorf("expected error, received none")
+			} else if err != nil && !test.expectError {
+				t.Errorf("unexpected error %#v", err)
+			}
+
+			if test.expectError {
+				return
+			}
+
+			if test.expectWidth != h.Width || test.expectHeight != h.Height {
+				t.Fatalf("expected %dx%d heatmap, received %dx%d", test.expectWidth, test.expectHeight, h.Width, h.Height)
+			}
+
+			if len(test.expectValues) != len(h.Values) {
+				t.Fatalf("expected %d values, received %d", len(test.expectValues), len(h.Values))
+			}
+
+			for i := range test.expectValues {
+				if !floatsMatch(test.expectValues[i], h.Values[i]) {
+					t.Errorf("value %d: expected %v, received %v", i, test.expectValues[i], h.Values[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDatabase_Heatmap_Huge(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.CreateDataset(&server.Schema{
+		Dataset: "site-a",
+		XMin:    math.MinInt32,
+		XMax:    math.MaxInt32,
+		YMax:    65536,
+		Sparse:  true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.InsertRecord(&server.Record{
+		Meta:    &server.Metadata{When: timestamppb.Now()},
+		Dataset: "site-a",
+		Name:    "temperature",
+		Value:   10,
+		X:       math.MaxInt32 - 1,
+		Y:       65535,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name         string
+		downsample   uint32
+		expectWidth  uint32
+		expectHeight uint32
+		expectError  error
+	}{
+		{"Full resolution heatmaps of huge datasets fail", 0, 0, 0, TooManyHeatmapCellsError},
+		{"Barely downsampled heatmaps of huge datasets fail", 2, 0, 0, TooManyHeatmapCellsError},
+		{"Heavily downsampled heatmaps of huge datasets succeed", 1 << 30, 4, 1, nil},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			h, err := d.Heatmap(&server.HeatmapRequest{
+				Dataset:     "site-a",
+				Name:        "temperature",
+				Aggregation: server.Aggregation_Max,
+				Downsample:  test.downsample,
+			})
+			if err != test.expectError {
+				t.Fatalf("expected %#v, received %#v", test.expectError, err)
+			}
+
+			if test.expectError != nil {
+				return
+			}
+
+			if test.expectWidth != h.Width || test.expectHeight != h.Height {
+				t.Fatalf("expected %dx%d heatmap, received %dx%d", test.expectWidth, test.expectHeight, h.Width, h.Height)
+			}
+
+			// The only record sits in the very last bucket
+			if v := h.Values[len(h.Values)-1]; v != 10 {
+				t.Errorf("expected %v, received %v", 10, v)
+			}
+		})
+	}
+}
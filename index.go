@@ -0,0 +1,130 @@
+package xyt
+
+import (
+	"github.com/xyt-db/xyt/server"
+)
+
+// DefaultMaxIndexCardinality is the number of distinct values a single
+// index key may hold when a Schema doesn't set MaxIndexCardinality
+const DefaultMaxIndexCardinality = 1_000
+
+// cellKey identifies a single (X,Y) location within a dataset
+type cellKey [2]int32
+
+// An index is an inverted index for a single index key, mapping
+// each value of that key to the records which carry it
+type index struct {
+	values map[string]*postings
+}
+
+// postings holds every record for a given index key/value pair, grouped
+// by location so that queries can still be filtered spatially without
+// touching records outside of the index
+type postings struct {
+	cells map[cellKey][]*server.Record
+}
+
+func newIndex() *index {
+	return &index{
+		values: make(map[string]*postings),
+	}
+}
+
+// checkCardinality ensures that indexing r wouldn't push any of the dataset's
+// indices past the cardinality limit set on the schema.
+//
+// It must be called with r.Dataset's lock held; see lock
+func (d *Database) checkCardinality(schema *server.Schema, r *server.Record) error {
+	if schema.Columnar {
+		return nil
+	}
+
+	limit := schema.MaxIndexCardinality
+	if limit == 0 {
+		limit = DefaultMaxIndexCardinality
+	}
+
+	for k, v := range r.Meta.Indices {
+		idx, ok := d.indices[r.Dataset][k]
+		if !ok {
+			continue
+		}
+
+		if _, ok := idx.values[v]; ok {
+			continue
+		}
+
+		// #nosec: G115
+		if uint32(len(idx.values)) >= limit {
+			return IndexCardinalityError{
+				dataset: r.Dataset,
+				key:     k,
+				limit:   limit,
+			}
+		}
+	}
+
+	return nil
+}
+
+// indexRecord adds r to each of the indices for the keys r carries,
+// keeping each location sorted by `When` where the schema asks for it.
+// Columnar datasets drop index values, and so are never indexed.
+//
+// It must be called with r.Dataset's lock held; see lock
+func (d *Database) indexRecord(schema *server.Schema, r *server.Record) {
+	if schema.Columnar || len(r.Meta.Indices) == 0 {
+		return
+	}
+
+	ck := cellKey{r.X, r.Y}
+
+	for k, v := range r.Meta.Indices {
+		idx, ok := d.indices[r.Dataset][k]
+		if !ok {
+			idx = newIndex()
+			d.indices[r.Dataset][k] = idx
+		}
+
+		p, ok := idx.values[v]
+		if !ok {
+			p = &postings{cells: make(map[cellKey][]*server.Record)}
+			idx.values[v] = p
+		}
+
+		if !schema.SortOnInsert {
+			p.cells[ck] = append(p.cells[ck], r)
+
+			continue
+		}
+
+		p.cells[ck] = insertSorted(p.cells[ck], r)
+	}
+}
+
+// lookup returns the postings for key=value in dataset ds, or nil
+// where nothing has been indexed for that pair
+func (d *Database) lookup(ds, key, value string) *postings {
+	idx, ok := d.indices[ds][key]
+	if !ok {
+		return nil
+	}
+
+	return idx.values[value]
+}
+
+// cellsWithin returns the locations within the postings which fall inside
+// of the (exclusive) ranges passed, in the same X then Y order a full scan
+// of a dataset would return them
+func (p *postings) cellsWithin(xMin, xMax, yMin, yMax int32) (keys []cellKey) {
+	keys = make([]cellKey, 0)
+	for k := range p.cells {
+		if k[0] >= xMin && k[0] < xMax && k[1] >= yMin && k[1] < yMax {
+			keys = append(keys, k)
+		}
+	}
+
+	sortCellKeys(keys)
+
+	return
+}
@@ -0,0 +1,184 @@
+package xyt
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestDatabase_RetrieveRecords_Indices(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.CreateDataset(&server.Schema{
+		Dataset:      "site-a",
+		XMax:         10,
+		YMax:         10,
+		SortOnInsert: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := time.Now()
+
+	// Two robots cover the whole grid, with robo-001 also covering
+	// the first column a second time
+	for i, robot := range []string{"robo-001", "robo-002"} {
+		for x := int32(0); x < 10; x++ {
+			for y := int32(0); y < 10; y++ {
+				err = d.InsertRecord(&server.Record{
+					Meta: &server.Metadata{
+						When:    timestamppb.New(ts.Add(time.Duration(i) * time.Minute)),
+						Indices: map[string]string{"robot": robot},
+					},
+					Dataset: "site-a",
+					Name:    "temperature",
+					X:       x,
+					Y:       y,
+					T:       90,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+
+	for y := int32(0); y < 10; y++ {
+		err = d.InsertRecord(&server.Record{
+			Meta: &server.Metadata{
+				When:    timestamppb.New(ts.Add(-time.Minute)),
+				Indices: map[string]string{"robot": "robo-001"},
+			},
+			Dataset: "site-a",
+			Name:    "temperature",
+			X:       0,
+			Y:       y,
+			T:       180,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, test := range []struct {
+		name        string
+		query       *server.Query
+		expectCount int
+		expectError bool
+	}{
+		{"Index key without value errors", &server.Query{Dataset: "site-a", IndexKey: "robot"}, 0, true},
+		{"Index value without key errors", &server.Query{Dataset: "site-a", IndexValue: "robo-001"}, 0, true},
+		{"Unknown index keys return nothing", &server.Query{Dataset: "site-a", IndexKey: "forklift", IndexValue: "robo-001"}, 0, false},
+		{"Unknown index values return nothing", &server.Query{Dataset: "site-a", IndexKey: "robot", IndexValue: "robo-003"}, 0, false},
+		{"Indexed values return only matching records", &server.Query{Dataset: "site-a", IndexKey: "robot", IndexValue: "robo-001"}, 110, false},
+		{"Indexed values return only matching records (2)", &server.Query{Dataset: "site-a", IndexKey: "robot", IndexValue: "robo-002"}, 100, false},
+		{"Index queries are filtered spatially", &server.Query{Dataset: "site-a", IndexKey: "robot", IndexValue: "robo-001", X: &server.Query_XValue{XValue: 0}}, 20, false},
+		{"Index queries are filtered by theta", &server.Query{Dataset: "site-a", IndexKey: "robot", IndexValue: "robo-001", T: &server.Query_TValue{TValue: 180}}, 10, false},
+		{"Index queries support latest", &server.Query{Dataset: "site-a", IndexKey: "robot", IndexValue: "robo-001", X: &server.Query_XValue{XValue: 0}, Time: new(server.Query_TimeLatest)}, 10, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			records, err := d.RetrieveRecords(test.query)
+			if err == nil && test.expectError {
+				t.Errorf("expected error, received none")
+			} else if err != nil && !test.expectError {
+				t.Errorf("unexpected error %#v", err)
+			}
+
+			rcvd := len(records)
+			if test.expectCount != rcvd {
+				t.Errorf("expected %d records, received %d", test.expectCount, rcvd)
+			}
+
+			for _, r := range records {
+				if test.query.IndexKey != "" && r.Meta.Indices[test.query.IndexKey] != test.query.IndexValue {
+					t.Errorf("received record with unexpected index value %q", r.Meta.Indices[test.query.IndexKey])
+				}
+			}
+		})
+	}
+
+	t.Run("Sorted indices return records in time order", func(t *testing.T) {
+		records, err := d.RetrieveRecords(&server.Query{Dataset: "site-a", IndexKey: "robot", IndexValue: "robo-001", X: &server.Query_XValue{XValue: 0}, Y: &server.Query_YValue{YValue: 0}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(records) != 2 {
+			t.Fatalf("expected 2 records, received %d", len(records))
+		}
+
+		if records[0].T != 180 {
+			t.Errorf("expected earliest record first")
+		}
+	})
+}
+
+func TestDatabase_InsertRecord_Cardinality(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.CreateDataset(&server.Schema{
+		Dataset:             "site-a",
+		XMax:                10,
+		YMax:                10,
+		MaxIndexCardinality: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	insert := func(robot string) error {
+		return d.InsertRecord(&server.Record{
+			Meta: &server.Metadata{
+				When:    timestamppb.Now(),
+				Indices: map[string]string{"robot": robot},
+			},
+			Dataset: "site-a",
+			Name:    "temperature",
+			X:       1,
+			Y:       1,
+		})
+	}
+
+	for i := 0; i < 3; i++ {
+		err = insert(fmt.Sprintf("robo-%03d", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("Existing values can still be inserted", func(t *testing.T) {
+		err = insert("robo-000")
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("New values beyond the limit are rejected", func(t *testing.T) {
+		err = insert("robo-003")
+
+		expect := IndexCardinalityError{dataset: "site-a", key: "robot", limit: 3}
+		if !errors.Is(err, expect) {
+			t.Errorf("expected %#v, received %#v", expect, err)
+		}
+
+		records, err := d.RetrieveRecords(&server.Query{Dataset: "site-a"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(records) != 4 {
+			t.Errorf("expected rejected record to not be stored")
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package xyt
+
+import (
+	"math"
+
+	"github.com/xyt-db/xyt/server"
+)
+
+// validatePolygon ensures p is a closed shape, with finite vertices
+func validatePolygon(p *server.Polygon) error {
+	rings := append([]*server.Ring{p.GetOutline()}, p.GetHoles()...)
+
+	for _, r := range rings {
+		if len(r.GetVertices()) < 3 {
+			return InvalidPolygonError
+		}
+
+		for _, v := range r.Vertices {
+			if math.IsNaN(v.GetX()) || math.IsInf(v.GetX(), 0) || math.IsNaN(v.GetY()) || math.IsInf(v.GetY(), 0) {
+				return InvalidPolygonError
+			}
+		}
+	}
+
+	return nil
+}
+
+// polygonBounds returns the (exclusive) ranges of locations which cover
+// every polygon passed
+func polygonBounds(polygons []*server.Polygon) (xMin, xMax, yMin, yMax float64) {
+	xMin, yMin = math.Inf(1), math.Inf(1)
+	xMax, yMax = math.Inf(-1), math.Inf(-1)
+
+	for _, p := range polygons {
+		for _, v := range p.Outline.Vertices {
+			xMin, xMax = min(xMin, v.X), max(xMax, v.X)
+			yMin, yMax = min(yMin, v.Y), max(yMax, v.Y)
+		}
+	}
+
+	return math.Ceil(xMin), math.Floor(xMax) + 1, math.Ceil(yMin), math.Floor(yMax) + 1
+}
+
+// inPolygons returns whether (x,y) falls within any of polygons
+func inPolygons(polygons []*server.Polygon, x, y int32) bool {
+	for _, p := range polygons {
+		if inPolygon(p, float64(x), float64(y)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inPolygon returns whether (x,y) falls within p's outline, and
+// not within any of its holes
+func inPolygon(p *server.Polygon, x, y float64) bool {
+	if !inRing(p.Outline, x, y, true) {
+		return false
+	}
+
+	for _, h := range p.Holes {
+		if inRing(h, x, y, false) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// inRing returns whether (x,y) falls within r, using the even-odd rule,
+// with points on r's edges considered within r where edges is true
+func inRing(r *server.Ring, x, y float64, edges bool) (in bool) {
+	vs := r.Vertices
+
+	for i, j := 0, len(vs)-1; i < len(vs); j, i = i, i+1 {
+		a, b := vs[j], vs[i]
+
+		if onSegment(a, b, x, y) {
+			return edges
+		}
+
+		// Count the edges a ray cast from (x,y) towards +X crosses
+		if (a.Y > y) != (b.Y > y) && x < (b.X-a.X)*(y-a.Y)/(b.Y-a.Y)+a.X {
+			in = !in
+		}
+	}
+
+	return
+}
+
+// onSegment returns whether (x,y) sits on the line segment from a to b
+func onSegment(a, b *server.Vertex, x, y float64) bool {
+	if x < min(a.X, b.X) || x > max(a.X, b.X) || y < min(a.Y, b.Y) || y > max(a.Y, b.Y) {
+		return false
+	}
+
+	// Vertices are typically whole locations, so this is
+	// exact far more often than it might look
+	cross := (b.X-a.X)*(y-a.Y) - (b.Y-a.Y)*(x-a.X)
+
+	return math.Abs(cross) < 1e-9
+}
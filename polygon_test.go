@@ -0,0 +1,128 @@
+package xyt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func ring(points ...float64) *server.Ring {
+	r := new(server.Ring)
+	for i := 0; i+1 < len(points); i += 2 {
+		r.Vertices = append(r.Vertices, &server.Vertex{X: points[i], Y: points[i+1]})
+	}
+
+	return r
+}
+
+func TestDatabase_RetrieveRecords_Polygons(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.CreateDataset(&server.Schema{
+		Dataset:             "site-a",
+		XMin:                0,
+		XMax:                20,
+		YMin:                0,
+		YMax:                20,
+		SortOnInsert:        true,
+		LazyInitialAllocate: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A single reading at every location, so the number of
+	// records returned is the number of locations matched
+	for x := int32(0); x < 20; x++ {
+		for y := int32(0); y < 20; y++ {
+			err = d.InsertRecord(&server.Record{
+				Meta:    &server.Metadata{When: timestamppb.New(time.Unix(1000, 0))},
+				Dataset: "site-a",
+				Name:    "temperature",
+				X:       x,
+				Y:       y,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	square := &server.Polygon{Outline: ring(2, 2, 6, 2, 6, 6, 2, 6)}
+
+	for _, test := range []struct {
+		name        string
+		polygons    []*server.Polygon
+		expect      int
+		expectError error
+	}{
+		{"Squares include their edges", []*server.Polygon{square}, 25, nil},
+		{"L shapes skip their notch", []*server.Polygon{{Outline: ring(0, 0, 4, 0, 4, 2, 2, 2, 2, 4, 0, 4)}}, 21, nil},
+		{"Holes are cut out, but keep their edges", []*server.Polygon{{Outline: square.Outline, Holes: []*server.Ring{ring(3, 3, 5, 3, 5, 5, 3, 5)}}}, 24, nil},
+		{"Triangles", []*server.Polygon{{Outline: ring(0, 0, 4, 0, 0, 4)}}, 15, nil},
+		{"Multiple polygons are a union", []*server.Polygon{square, {Outline: ring(4, 4, 8, 4, 8, 8, 4, 8)}}, 25 + 25 - 9, nil},
+		{"Polygons off the edge of the dataset", []*server.Polygon{{Outline: ring(-10, -10, 1, -10, 1, 1, -10, 1)}}, 4, nil},
+		{"Polygons entirely outside the dataset", []*server.Polygon{{Outline: ring(100, 100, 110, 100, 110, 110)}}, 0, nil},
+		{"Polygons need at least three vertices", []*server.Polygon{{Outline: ring(0, 0, 4, 4)}}, 0, InvalidPolygonError},
+		{"Holes need at least three vertices too", []*server.Polygon{{Outline: square.Outline, Holes: []*server.Ring{ring(3, 3)}}}, 0, InvalidPolygonError},
+		{"Polygons need an outline", []*server.Polygon{{}}, 0, InvalidPolygonError},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			records, err := d.RetrieveRecords(&server.Query{Dataset: "site-a", Polygons: test.polygons})
+			if err != test.expectError {
+				t.Fatalf("expected %#v, received %#v", test.expectError, err)
+			}
+
+			if len(records) != test.expect {
+				t.Errorf("expected %d records, received %d", test.expect, len(records))
+			}
+		})
+	}
+
+	t.Run("Polygons combine with circles", func(t *testing.T) {
+		records, err := d.RetrieveRecords(&server.Query{
+			Dataset:  "site-a",
+			Polygons: []*server.Polygon{square},
+			Within:   &server.Circle{X: 2, Y: 2, Radius: 1},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(records) != 3 {
+			t.Errorf("expected 3 records, received %d", len(records))
+		}
+	})
+
+	t.Run("Subscriptions respect polygons", func(t *testing.T) {
+		s, err := d.Subscribe(&server.SubscribeRequest{Query: &server.Query{
+			Dataset:  "site-a",
+			Polygons: []*server.Polygon{{Outline: square.Outline, Holes: []*server.Ring{ring(3, 3, 5, 3, 5, 5, 3, 5)}}},
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer s.Close()
+
+		for _, test := range []struct {
+			x, y   int32
+			expect bool
+		}{
+			{2, 2, true},
+			{3, 4, true},
+			{4, 4, false},
+			{7, 7, false},
+		} {
+			r := &server.Record{Dataset: "site-a", Name: "temperature", X: test.x, Y: test.y, Meta: &server.Metadata{When: timestamppb.Now()}}
+			if s.matches(r) != test.expect {
+				t.Errorf("(%d,%d): expected %v", test.x, test.y, test.expect)
+			}
+		}
+	})
+}
@@ -0,0 +1,242 @@
+package xyt
+
+import (
+	"cmp"
+	"math"
+	"math/bits"
+	"slices"
+
+	"github.com/xyt-db/xyt/server"
+)
+
+// tileSize is the width and height, in locations, of each tile
+// a dataset is split into for its spatial index
+const tileSize = 16
+
+// A quadtree is a spatial index over a dataset, splitting the dataset into
+// tiles of tileSize by tileSize locations, and recursively grouping those
+// tiles into quadrants.
+//
+// Each node knows how many records sit beneath it, and the range of their
+// `When` values, so that queries can skip over whole swathes of a dataset
+// which are empty, or hold nothing from the right period of time.
+//
+// Nodes are only allocated for tiles which have held records. Time bounds
+// only ever widen, even as records are truncated, so are conservative
+type quadtree struct {
+	xMin, yMin int32
+
+	// size is the width and height of the tree, in
+	// tiles, and is always a power of two
+	size int64
+	root *quadNode
+}
+
+type quadNode struct {
+	records          int
+	minWhen, maxWhen int64
+
+	// children holds each quadrant, in the order top-left,
+	// top-right, bottom-left, bottom-right; tiles have none
+	children [4]*quadNode
+}
+
+// A tileKey identifies a single tile within a quadtree
+type tileKey [2]int64
+
+func newQuadtree(s *server.Schema) *quadtree {
+	tiles := max(
+		ceilDiv64(int64(s.XMax)-int64(s.XMin), tileSize),
+		ceilDiv64(int64(s.YMax)-int64(s.YMin), tileSize),
+	)
+
+	return &quadtree{
+		xMin: s.XMin,
+		yMin: s.YMin,
+		size: 1 << bits.Len64(uint64(tiles-1)), // #nosec: G115
+	}
+}
+
+// tile returns the tile holding (x,y)
+func (q *quadtree) tile(x, y int32) tileKey {
+	return tileKey{
+		(int64(x) - int64(q.xMin)) / tileSize,
+		(int64(y) - int64(q.yMin)) / tileSize,
+	}
+}
+
+// add records that a record from when has been stored at (x,y)
+func (q *quadtree) add(x, y int32, when int64) {
+	if q.root == nil {
+		q.root = newQuadNode()
+	}
+
+	k := q.tile(x, y)
+	n := q.root
+
+	for size, tx, ty := q.size, int64(0), int64(0); ; {
+		n.records++
+		n.minWhen = min(n.minWhen, when)
+		n.maxWhen = max(n.maxWhen, when)
+
+		if size == 1 {
+			return
+		}
+
+		size /= 2
+
+		i := 0
+		if k[0] >= tx+size {
+			i, tx = i+1, tx+size
+		}
+
+		if k[1] >= ty+size {
+			i, ty = i+2, ty+size
+		}
+
+		if n.children[i] == nil {
+			n.children[i] = newQuadNode()
+		}
+
+		n = n.children[i]
+	}
+}
+
+// remove records that count records have been dropped from (x,y),
+// dropping any nodes left with no records at all
+func (q *quadtree) remove(x, y int32, count int) {
+	if count == 0 || q.root == nil {
+		return
+	}
+
+	k := q.tile(x, y)
+
+	var parent *quadNode
+
+	n, pi := q.root, 0
+	for size, tx, ty := q.size, int64(0), int64(0); n != nil; {
+		n.records -= count
+		if n.records <= 0 {
+			if parent == nil {
+				q.root = nil
+			} else {
+				parent.children[pi] = nil
+			}
+
+			return
+		}
+
+		if size == 1 {
+			return
+		}
+
+		size /= 2
+
+		i := 0
+		if k[0] >= tx+size {
+			i, tx = i+1, tx+size
+		}
+
+		if k[1] >= ty+size {
+			i, ty = i+2, ty+size
+		}
+
+		parent, n, pi = n, n.children[i], i
+	}
+}
+
+// tiles returns the tiles which overlap the (exclusive) ranges of locations
+// passed and which hold records from between from and to, inclusive, in X
+// then Y order
+func (q *quadtree) tiles(xMin, xMax, yMin, yMax int32, from, to int64) (keys []tileKey) {
+	if xMin >= xMax || yMin >= yMax {
+		return
+	}
+
+	lower, upper := q.tile(xMin, yMin), q.tile(xMax-1, yMax-1)
+
+	var visit func(n *quadNode, size, tx, ty int64)
+	visit = func(n *quadNode, size, tx, ty int64) {
+		if n == nil || n.maxWhen < from || n.minWhen > to {
+			return
+		}
+
+		if tx > upper[0] || tx+size <= lower[0] || ty > upper[1] || ty+size <= lower[1] {
+			return
+		}
+
+		if size == 1 {
+			keys = append(keys, tileKey{tx, ty})
+
+			return
+		}
+
+		size /= 2
+
+		visit(n.children[0], size, tx, ty)
+		visit(n.children[1], size, tx+size, ty)
+		visit(n.children[2], size, tx, ty+size)
+		visit(n.children[3], size, tx+size, ty+size)
+	}
+
+	visit(q.root, q.size, 0, 0)
+
+	slices.SortFunc(keys, func(a, b tileKey) int {
+		if c := cmp.Compare(a[0], b[0]); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a[1], b[1])
+	})
+
+	return
+}
+
+// each passes every cell within the (exclusive) ranges passed, from tiles
+// holding records from between from and to, to fn in X then Y order
+func (q *quadtree) each(g grid, xMin, xMax, yMin, yMax int32, from, to int64, fn func(x, y int32, c *cell)) {
+	keys := q.tiles(xMin, xMax, yMin, yMax, from, to)
+
+	for start := 0; start < len(keys); {
+		// Find the run of tiles sharing this column of tiles, so
+		// we can walk each X within the column across all of them
+		end := start + 1
+		for end < len(keys) && keys[end][0] == keys[start][0] {
+			end++
+		}
+
+		x0, x1 := q.span(keys[start][0], q.xMin, xMin, xMax)
+		for x := x0; x < x1; x++ {
+			for _, k := range keys[start:end] {
+				y0, y1 := q.span(k[1], q.yMin, yMin, yMax)
+				for y := y0; y < y1; y++ {
+					if c := g.get(x, y); c != nil {
+						fn(x, y, c)
+					}
+				}
+			}
+		}
+
+		start = end
+	}
+}
+
+// span returns the (exclusive) range of locations tile t covers along
+// an axis starting at origin, clamped to the range lower to upper
+func (q *quadtree) span(t int64, origin, lower, upper int32) (start, end int32) {
+	first := int64(origin) + t*tileSize
+
+	// #nosec: G115
+	return int32(max(first, int64(lower))), int32(min(first+tileSize, int64(upper)))
+}
+
+func newQuadNode() *quadNode {
+	return &quadNode{
+		minWhen: math.MaxInt64,
+		maxWhen: math.MinInt64,
+	}
+}
+
+func ceilDiv64(a, b int64) int64 {
+	return (a + b - 1) / b
+}
@@ -0,0 +1,140 @@
+package xyt
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestQuadtree(t *testing.T) {
+	schema := &server.Schema{Dataset: "site-a", XMin: -50, XMax: 150, YMin: 10, YMax: 70}
+
+	q := newQuadtree(schema)
+
+	q.add(-50, 10, 100)
+	q.add(-34, 10, 200)
+	q.add(149, 69, 300)
+	q.add(149, 69, 400)
+
+	for _, test := range []struct {
+		name     string
+		x0, x1   int32
+		y0, y1   int32
+		from, to int64
+		expect   []tileKey
+	}{
+		{"Everything", -50, 150, 10, 70, math.MinInt64, math.MaxInt64, []tileKey{{0, 0}, {1, 0}, {12, 3}}},
+		{"A single tile", -50, -34, 10, 70, math.MinInt64, math.MaxInt64, []tileKey{{0, 0}}},
+		{"Empty tiles are skipped", 0, 100, 10, 70, math.MinInt64, math.MaxInt64, nil},
+		{"Tiles from other times are skipped", -50, 150, 10, 70, 250, 500, []tileKey{{12, 3}}},
+		{"Empty ranges return nothing", 10, 10, 10, 70, math.MinInt64, math.MaxInt64, nil},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			rcvd := q.tiles(test.x0, test.x1, test.y0, test.y1, test.from, test.to)
+			if len(test.expect) != len(rcvd) {
+				t.Fatalf("expected %v, received %v", test.expect, rcvd)
+			}
+
+			for i := range rcvd {
+				if test.expect[i] != rcvd[i] {
+					t.Errorf("expected %v, received %v", test.expect, rcvd)
+				}
+			}
+		})
+	}
+
+	q.remove(149, 69, 2)
+	if rcvd := q.tiles(-50, 150, 10, 70, math.MinInt64, math.MaxInt64); len(rcvd) != 2 {
+		t.Errorf("expected emptied tile to be dropped, received %v", rcvd)
+	}
+
+	q.remove(-50, 10, 1)
+	q.remove(-34, 10, 1)
+	if q.root != nil {
+		t.Errorf("expected empty tree to be dropped")
+	}
+}
+
+// TestDatabase_RetrieveRecords_Tiles ensures queries which lean on the
+// spatial index return exactly what a scan of every location would
+func TestDatabase_RetrieveRecords_Tiles(t *testing.T) {
+	// #nosec: G404
+	rnd := rand.New(rand.NewSource(rseed))
+
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := &server.Schema{Dataset: "site-a", XMin: -100, XMax: 100, YMin: -100, YMax: 100, LazyInitialAllocate: true}
+
+	err = d.CreateDataset(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Records clustered in a handful of patches, like robots
+	// working a few aisles of a warehouse
+	for i := range 2_000 {
+		patch := int32(i % 4)
+
+		err = d.InsertRecord(&server.Record{
+			Meta:    &server.Metadata{When: timestamppb.New(time.Unix(int64(i), 0))},
+			Dataset: "site-a",
+			Name:    "temperature",
+			X:       -100 + patch*50 + rnd.Int31n(20),
+			Y:       -100 + patch*40 + rnd.Int31n(20),
+			T:       90,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := range 100 {
+		x0, y0 := -100+rnd.Int31n(200), -100+rnd.Int31n(200)
+		x1, y1 := x0+rnd.Int31n(100), y0+rnd.Int31n(100)
+		from := rnd.Int63n(2_000)
+
+		query := &server.Query{
+			Dataset: "site-a",
+			X:       &server.Query_XRange{XRange: &server.QueryRange{Start: x0, End: x1}},
+			Y:       &server.Query_YRange{YRange: &server.QueryRange{Start: y0, End: y1}},
+		}
+
+		if i%2 == 0 {
+			query.Time = &server.Query_TimeRange{TimeRange: &server.TimeRange{
+				Start: timestamppb.New(time.Unix(from, 0)),
+				End:   timestamppb.New(time.Unix(from+200, 0)),
+			}}
+		}
+
+		received, err := d.RetrieveRecords(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var expect []*server.Record
+
+		m := newMatcher(schema, query)
+		d.data["site-a"].each(m.xMin, m.xMax, m.yMin, m.yMax, func(x, y int32, c *cell) {
+			m.visitCell("site-a", x, y, c, func(r *server.Record) {
+				expect = append(expect, r)
+			})
+		})
+
+		if len(expect) != len(received) {
+			t.Fatalf("query %d: expected %d records, received %d", i, len(expect), len(received))
+		}
+
+		for j := range expect {
+			if expect[j] != received[j] {
+				t.Fatalf("query %d: record %d differs", i, j)
+			}
+		}
+	}
+}
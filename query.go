@@ -0,0 +1,442 @@
+package xyt
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+)
+
+// A matcher holds the resolved ranges for a query, and is used to
+// pick matching records out of a single location within a dataset
+type matcher struct {
+	sorted bool
+
+	xMin, xMax int32
+	yMin, yMax int32
+
+	tMin, tMax int32
+	tAll       bool
+
+	// zMin and zMax are only checked where zAll is false, which saves
+	// looking at Z at all for flat datasets, and queries ignoring it
+	zMin, zMax int32
+	zAll       bool
+
+	// tBuckets is the number of theta buckets in a full turn
+	tBuckets int32
+
+	timeStart, timeEnd  time.Time
+	timeAll, timeLatest bool
+
+	// names, when non-empty, limits matches to records with one of
+	// these names
+	names []string
+
+	// within, when set, limits matches to records from
+	// locations within a circle
+	within *server.Circle
+
+	// polygons, when set, limits matches to records from
+	// locations within any of these polygons
+	polygons []*server.Polygon
+
+	// transform is the dataset's world transform, if any
+	transform *server.Transform
+}
+
+func newMatcher(s *server.Schema, q *server.Query) (m matcher) {
+	m.sorted = s.SortOnInsert
+
+	m.tMin, m.tMax, m.tAll = tRange(s, q)
+	m.tBuckets = thetaBuckets(s)
+
+	m.timeStart, m.timeEnd, m.timeAll, m.timeLatest = timeRange(q)
+	m.within = q.Within
+	m.transform = s.Transform
+
+	// Zones are only named polygons, and have already been
+	// checked by validateQuery; world polygons are only
+	// polygons in different units
+	zones, _ := zonePolygons(s, q.Zones)
+	if s.Transform != nil {
+		zones = append(zones, gridPolygons(s.Transform, q.WorldPolygons)...)
+	}
+
+	if len(q.Polygons) > 0 || len(zones) > 0 {
+		m.polygons = append(slices.Clip(q.Polygons), zones...)
+	}
+
+	m.bound(s, q)
+
+	return
+}
+
+// bound works out the X, Y, and Z ranges m matches, from s and q, and
+// is called again for subscriptions whenever s's bounds change
+func (m *matcher) bound(s *server.Schema, q *server.Query) {
+	m.xMin, m.xMax = xRange(s, q)
+	m.yMin, m.yMax = yRange(s, q)
+
+	m.zMin, m.zMax = zRange(s, q)
+	zMin, zMax := zBounds(s)
+	m.zAll = m.zMin == zMin && m.zMax == zMax
+
+	// Narrowing X and Y down to the polygons' bounding box means nothing
+	// outside of it is ever looked at, whichever way the query is served
+	if len(m.polygons) > 0 {
+		xMin, xMax, yMin, yMax := polygonBounds(m.polygons)
+
+		m.xMin, m.xMax = clampRange(clampInt32(xMin), clampInt32(xMax), m.xMin, m.xMax)
+		m.yMin, m.yMax = clampRange(clampInt32(yMin), clampInt32(yMax), m.yMin, m.yMax)
+	}
+}
+
+// A visitFunc is passed each record a query matches. Where the query
+// is served from a rollup tier, each record stands in for a whole rollup
+// and rolled holds that rollup's aggregates; otherwise rolled is nil
+type visitFunc func(r *server.Record, rolled *aggregator)
+
+// walk validates q and then passes every matching record to fn, in the
+// same order RetrieveRecords returns them.
+//
+// Walking, rather than collecting, records allows things like aggregations
+// to run over huge numbers of records without allocating a slice to hold
+// them all.
+//
+// It must be called with q.Dataset's lock held; see rlock
+func (d *Database) walk(q *server.Query, fn visitFunc) (err error) {
+	err = d.validateQuery(q)
+	if err != nil {
+		return
+	}
+
+	ds := d.data[q.Dataset]
+	schema := d.schemata[q.Dataset]
+	m := newMatcher(schema, q)
+
+	// Drop any names this dataset has never seen, and skip the
+	// query entirely where that leaves nothing to look for
+	if len(q.Names) > 0 {
+		m.names = slices.DeleteFunc(slices.Clone(q.Names), func(n string) bool {
+			_, ok := d.fields[q.Dataset][n]
+
+			return !ok
+		})
+
+		if len(m.names) == 0 {
+			return
+		}
+	}
+
+	var each locations
+
+	switch tier := m.rollupTier(schema, q, d.now()); {
+	case tier >= 0:
+		resolution := schema.Rollups[tier].Resolution.AsDuration()
+
+		each = func(xMin, xMax, yMin, yMax int32, loc func(x, y int32, visit func(visitFunc))) {
+			ds.each(xMin, xMax, yMin, yMax, func(x, y int32, c *cell) {
+				loc(x, y, func(fn visitFunc) {
+					m.visitRollups(q.Dataset, x, y, c, tier, resolution, fn)
+				})
+			})
+		}
+
+	case q.IndexKey != "":
+		p := d.lookup(q.Dataset, q.IndexKey, q.IndexValue)
+		if p == nil {
+			return
+		}
+
+		each = func(xMin, xMax, yMin, yMax int32, loc func(x, y int32, visit func(visitFunc))) {
+			for _, k := range p.cellsWithin(xMin, xMax, yMin, yMax) {
+				loc(k[0], k[1], func(fn visitFunc) {
+					m.visitMatches(p.cells[k], true, raw(fn))
+				})
+			}
+		}
+
+	default:
+		// Queries served from records can lean on the spatial index to skip
+		// tiles which hold nothing, or nothing from the right time range
+		from, to := int64(math.MinInt64), int64(math.MaxInt64)
+		if !m.timeAll && !m.timeLatest {
+			from, to = unixNano(m.timeStart), unixNano(m.timeEnd)
+		}
+
+		each = func(xMin, xMax, yMin, yMax int32, loc func(x, y int32, visit func(visitFunc))) {
+			d.tiles[q.Dataset].each(ds, xMin, xMax, yMin, yMax, from, to, func(x, y int32, c *cell) {
+				loc(x, y, func(fn visitFunc) {
+					m.visitCell(q.Dataset, x, y, c, raw(fn))
+				})
+			})
+		}
+	}
+
+	if len(m.polygons) > 0 {
+		each = m.inPolygons(each)
+	}
+
+	switch {
+	case q.Within != nil:
+		m.walkWithin(q.Within, each, fn)
+
+	case q.Nearest != nil:
+		m.walkNearest(q.Nearest, each, fn)
+
+	default:
+		each(m.xMin, m.xMax, m.yMin, m.yMax, func(_, _ int32, visit func(visitFunc)) {
+			visit(fn)
+		})
+	}
+
+	return
+}
+
+// raw adapts fn to visit records, rather than rollups
+func raw(fn visitFunc) func(*server.Record) {
+	return func(r *server.Record) {
+		fn(r, nil)
+	}
+}
+
+// validateQuery ensures q can be run against this database
+func (d *Database) validateQuery(q *server.Query) error {
+	if q == nil || q.Dataset == "" {
+		return MissingDatasetError
+	}
+
+	schema, ok := d.schemata[q.Dataset]
+	if !ok {
+		return UnknownDatasetError
+	}
+
+	// If we only want the latest matching record, there's no real
+	// need doing much beyond doing a backwards ranging of the data,
+	// finding the first (ie: most recent) record matching the theta
+	if _, latest := q.Time.(*server.Query_TimeLatest); latest && !schema.SortOnInsert {
+		return UnsortedDataset
+	}
+
+	if (q.IndexKey == "") != (q.IndexValue == "") {
+		return IncompleteIndexQueryError
+	}
+
+	if q.IndexKey != "" && schema.Columnar {
+		return ColumnarIndexQueryError
+	}
+
+	_, err := zonePolygons(schema, q.Zones)
+	if err != nil {
+		return err
+	}
+
+	if len(q.WorldPolygons) > 0 && schema.Transform == nil {
+		return MissingTransformError
+	}
+
+	return validateSpatial(q)
+}
+
+// validateSpatial ensures any circle, or nearest neighbour
+// search, within q makes sense
+func validateSpatial(q *server.Query) error {
+	if q.Within != nil && q.Nearest != nil {
+		return ConflictingSpatialQueryError
+	}
+
+	if q.Within != nil && !(q.Within.Radius >= 0) {
+		return InvalidRadiusError
+	}
+
+	if q.Nearest != nil && (q.Nearest.K == 0 || !(q.Nearest.MaxDistance >= 0)) {
+		return InvalidNearestError
+	}
+
+	for _, p := range slices.Concat(q.Polygons, q.WorldPolygons) {
+		err := validatePolygon(p)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// visitCell passes every record from c, at (x,y) in dataset, which
+// matches the query to fn.
+//
+// Only the series for the names the query wants are looked at
+func (m matcher) visitCell(dataset string, x, y int32, c *cell, fn func(*server.Record)) {
+	visit := func(s *series) {
+		if s.columns != nil {
+			m.visitColumns(dataset, x, y, s, fn)
+
+			return
+		}
+
+		m.visitMatches(s.records, false, fn)
+	}
+
+	if len(m.names) == 0 {
+		for _, s := range c.series {
+			visit(s)
+		}
+
+		return
+	}
+
+	for _, name := range m.names {
+		if s := c.get(name); s != nil {
+			visit(s)
+		}
+	}
+}
+
+// visitColumns is visitMatches for columnar series; readings are only
+// rehydrated into records once they're known to match
+func (m matcher) visitColumns(dataset string, x, y int32, s *series, fn func(*server.Record)) {
+	if m.timeLatest {
+		s.columns.reverse(func(when int64, t int32, v float64) bool {
+			if !m.matchesT(t) {
+				return true
+			}
+
+			fn(m.worldRecord(s.rehydrate(dataset, x, y, when, t, v)))
+
+			return false
+		})
+
+		return
+	}
+
+	from, to := int64(math.MinInt64), int64(math.MaxInt64)
+	if !m.timeAll {
+		from, to = unixNano(m.timeStart), unixNano(m.timeEnd)
+	}
+
+	s.columns.each(from, to, m.sorted, func(when int64, t int32, v float64) bool {
+		if m.matchesT(t) {
+			fn(m.worldRecord(s.rehydrate(dataset, x, y, when, t, v)))
+		}
+
+		return true
+	})
+}
+
+// visitMatches passes every record from a single location which
+// matches the query to fn.
+//
+// When the query only wants the latest record, only the most recent
+// record matching the theta is passed for each name. Where records
+// are already split by name, mixed should be false so we can stop
+// looking at the first match
+func (m matcher) visitMatches(records []*server.Record, mixed bool, fn func(*server.Record)) {
+	if m.timeLatest {
+		var seen []string
+
+		for ri := len(records) - 1; ri >= 0; ri-- {
+			record := records[ri]
+			if !m.matchesName(record) || !m.matchesTheta(record) || !m.matchesZ(record) || slices.Contains(seen, record.Name) {
+				continue
+			}
+
+			fn(record)
+			if !mixed {
+				return
+			}
+
+			seen = append(seen, record.Name)
+		}
+
+		return
+	}
+
+	for _, record := range records {
+		if !m.timeAll {
+			ts := record.Meta.When.AsTime()
+			if ts.Before(m.timeStart) {
+				continue
+			}
+
+			if ts.After(m.timeEnd) {
+				// Sorted data means every subsequent record is
+				// later still, so there's no need to look further
+				if m.sorted {
+					break
+				}
+
+				continue
+			}
+		}
+
+		if !m.matchesName(record) || !m.matchesTheta(record) || !m.matchesZ(record) {
+			continue
+		}
+
+		fn(record)
+	}
+}
+
+// matches returns whether a single record, such as one which has
+// just been inserted, matches the query.
+//
+// Every record is considered the latest record as it arrives, so
+// queries for the latest record match on everything else
+func (m matcher) matches(r *server.Record) bool {
+	if !m.matchesLocation(r.X, r.Y) {
+		return false
+	}
+
+	if !m.timeAll && !m.timeLatest {
+		ts := r.Meta.When.AsTime()
+		if ts.Before(m.timeStart) || ts.After(m.timeEnd) {
+			return false
+		}
+	}
+
+	return m.matchesName(r) && m.matchesTheta(r) && m.matchesZ(r)
+}
+
+// matchesLocation returns whether (x,y) falls within the
+// query's ranges, and any circle or polygons it sets
+func (m matcher) matchesLocation(x, y int32) bool {
+	if x < m.xMin || x >= m.xMax || y < m.yMin || y >= m.yMax {
+		return false
+	}
+
+	if m.within != nil && distance(m.within.X, m.within.Y, x, y) > m.within.Radius {
+		return false
+	}
+
+	return len(m.polygons) == 0 || inPolygons(m.polygons, x, y)
+}
+
+func (m matcher) matchesName(r *server.Record) bool {
+	return len(m.names) == 0 || slices.Contains(m.names, r.Name)
+}
+
+func (m matcher) matchesTheta(r *server.Record) bool {
+	return m.matchesT(r.T)
+}
+
+func (m matcher) matchesZ(r *server.Record) bool {
+	return m.zAll || (r.Z >= m.zMin && r.Z < m.zMax)
+}
+
+// matchesT returns whether t falls within the query's range of
+// thetas, which wraps around 0/360 where tMin is greater than tMax
+func (m matcher) matchesT(t int32) bool {
+	switch {
+	case m.tAll:
+		return true
+
+	case m.tMin > m.tMax:
+		return t >= m.tMin || t < m.tMax
+
+	default:
+		return t >= m.tMin && t < m.tMax
+	}
+}
@@ -0,0 +1,266 @@
+package xyt
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+)
+
+// DefaultJanitorInterval is how often the janitor looks for records
+// and rollups which have outlived their retention
+const DefaultJanitorInterval = time.Minute
+
+// Truncate drops every record in dataset with a `When` value before
+// before, returning the number of records dropped. Rollups which end
+// at or before before are dropped too.
+//
+// Locations which lose records are given freshly allocated, right-sized,
+// slices so that the memory behind dropped records can actually be freed,
+// and indices are updated to match; index values left with no records
+// no longer count towards the dataset's MaxIndexCardinality
+func (d *Database) Truncate(dataset string, before time.Time) (removed uint64, err error) {
+	return d.truncate(dataset, before, true)
+}
+
+// truncate drops records, and optionally rollups, from before before;
+// the janitor expires records and rollups separately, since each rollup
+// tier has its own retention
+func (d *Database) truncate(dataset string, before time.Time, rollups bool) (removed uint64, err error) {
+	if dataset == "" {
+		return 0, MissingDatasetError
+	}
+
+	unlock := d.lock(dataset)
+	defer unlock()
+
+	schema, ok := d.schemata[dataset]
+	if !ok {
+		return 0, UnknownDatasetError
+	}
+
+	// Truncations are logged before they're applied, like any other
+	// write. Expiries are logged afterwards, and only where they drop
+	// something, so that an idle janitor doesn't grow the WAL every run;
+	// should logging one fail, replay merely brings back records which
+	// the janitor then expires again
+	if d.wal != nil && rollups {
+		err = d.wal.appendTruncate(walEntryTruncate, dataset, before)
+		if err != nil {
+			return
+		}
+	}
+
+	g := d.data[dataset]
+	g.each(schema.XMin, schema.XMax, schema.YMin, schema.YMax, func(x, y int32, c *cell) {
+		n := c.truncate(before, schema.SortOnInsert)
+		d.tiles[dataset].remove(x, y, int(n)) // #nosec: G115
+
+		removed += n
+
+		if rollups {
+			for tier, t := range schema.Rollups {
+				c.truncateRollups(tier, before, t.Resolution.AsDuration())
+			}
+		}
+
+		c.dropEmpty()
+	})
+	g.prune()
+
+	for _, idx := range d.indices[dataset] {
+		idx.truncate(before, schema.SortOnInsert)
+	}
+
+	d.stats[dataset].removeRecords(removed)
+
+	if d.wal != nil && !rollups && removed > 0 {
+		err = d.wal.appendTruncate(walEntryExpire, dataset, before)
+	}
+
+	return
+}
+
+// expireRollups drops rollups which have outlived their tier's retention,
+// where cutoffs holds the cutoff for each tier, or the zero time for tiers
+// which are kept forever.
+//
+// Expired rollups aren't logged to the WAL; replaying the WAL rebuilds them,
+// and the next run of the janitor expires them again
+func (d *Database) expireRollups(dataset string, cutoffs []time.Time) {
+	unlock := d.lock(dataset)
+	defer unlock()
+
+	schema, ok := d.schemata[dataset]
+	if !ok {
+		return
+	}
+
+	g := d.data[dataset]
+	g.each(schema.XMin, schema.XMax, schema.YMin, schema.YMax, func(_, _ int32, c *cell) {
+		for tier, cutoff := range cutoffs {
+			if !cutoff.IsZero() {
+				c.truncateRollups(tier, cutoff, schema.Rollups[tier].Resolution.AsDuration())
+			}
+		}
+
+		c.dropEmpty()
+	})
+	g.prune()
+}
+
+// RunJanitor drops records which have outlived their dataset's Retention,
+// and rollups which have outlived their tier's, checking every interval
+// until ctx is cancelled.
+//
+// An interval of zero, or less, turns the janitor off, and RunJanitor
+// returns straight away. Otherwise, RunJanitor blocks, and so should be
+// run in its own goroutine
+func (d *Database) RunJanitor(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case now := <-t.C:
+			// There's nobody to return this error to; failures
+			// are down to the WAL, which inserts will surface
+			_ = d.expire(now)
+		}
+	}
+}
+
+// expire drops the records and rollups in each dataset which have
+// outlived their retention as of now
+func (d *Database) expire(now time.Time) (err error) {
+	type cutoff struct {
+		records time.Time
+		rollups []time.Time
+	}
+
+	cutoffs := make(map[string]cutoff)
+
+	d.mutx.RLock()
+	for name, schema := range d.schemata {
+		var c cutoff
+
+		if schema.Retention.AsDuration() > 0 {
+			c.records = now.Add(-schema.Retention.AsDuration())
+		}
+
+		c.rollups = make([]time.Time, len(schema.Rollups))
+		for i, tier := range schema.Rollups {
+			if tier.Retention.AsDuration() > 0 {
+				c.rollups[i] = now.Add(-tier.Retention.AsDuration())
+			}
+		}
+
+		cutoffs[name] = c
+	}
+	d.mutx.RUnlock()
+
+	for name, c := range cutoffs {
+		if !c.records.IsZero() {
+			_, err = d.truncate(name, c.records, false)
+			if err != nil {
+				return
+			}
+		}
+
+		d.expireRollups(name, c.rollups)
+	}
+
+	return
+}
+
+// truncate drops every record before before from each series, and
+// returns the number of records dropped
+func (c *cell) truncate(before time.Time, sorted bool) (removed uint64) {
+	for _, s := range c.series {
+		var n int
+
+		if s.columns != nil {
+			n = s.columns.truncate(unixNano(before))
+		} else {
+			s.records, n = expireRecords(s.records, before, sorted)
+		}
+
+		removed += uint64(n) // #nosec: G115
+	}
+
+	return
+}
+
+// dropEmpty drops series which hold neither records nor rollups
+func (c *cell) dropEmpty() {
+	c.series = slices.DeleteFunc(c.series, func(s *series) bool {
+		return s.empty()
+	})
+}
+
+// truncate drops every record before before from the index, dropping
+// locations and values which are left empty
+func (idx *index) truncate(before time.Time, sorted bool) {
+	for v, p := range idx.values {
+		for k, records := range p.cells {
+			records, _ = expireRecords(records, before, sorted)
+			if len(records) == 0 {
+				delete(p.cells, k)
+
+				continue
+			}
+
+			p.cells[k] = records
+		}
+
+		if len(p.cells) == 0 {
+			delete(idx.values, v)
+		}
+	}
+}
+
+// expireRecords returns records without those before before, along with the
+// number of records dropped.
+//
+// Where anything is dropped, the returned slice is a new allocation, rather
+// than a reslicing of records, so that records' backing array can be freed
+func expireRecords(records []*server.Record, before time.Time, sorted bool) (kept []*server.Record, n int) {
+	if sorted {
+		// Sorted records means everything we want to
+		// drop sits at the start of the slice
+		n, _ = slices.BinarySearchFunc(records, before, func(r *server.Record, t time.Time) int {
+			return r.Meta.When.AsTime().Compare(t)
+		})
+	} else {
+		for _, r := range records {
+			if r.Meta.When.AsTime().Before(before) {
+				n++
+			}
+		}
+	}
+
+	switch n {
+	case 0:
+		return records, 0
+
+	case len(records):
+		return nil, n
+	}
+
+	kept = make([]*server.Record, 0, len(records)-n)
+	for _, r := range records {
+		if !r.Meta.When.AsTime().Before(before) {
+			kept = append(kept, r)
+		}
+	}
+
+	return
+}
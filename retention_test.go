@@ -0,0 +1,255 @@
+package xyt
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestDatabase_Truncate(t *testing.T) {
+	for _, sorted := range []bool{true, false} {
+		t.Run(map[bool]string{true: "sorted", false: "unsorted"}[sorted], func(t *testing.T) {
+			d, err := New()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = d.CreateDataset(&server.Schema{
+				Dataset:      "site-a",
+				XMax:         10,
+				YMax:         10,
+				SortOnInsert: sorted,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Insert records out of order, a handful per location, with
+			// the robot moving on part way through
+			for _, i := range []int64{5, 0, 9, 3, 1, 8, 2, 7, 4, 6} {
+				robot := "robo-001"
+				if i >= 4 {
+					robot = "robo-002"
+				}
+
+				err = d.InsertRecord(&server.Record{
+					Meta: &server.Metadata{
+						When:    timestamppb.New(time.Unix(i, 0)),
+						Indices: map[string]string{"robot": robot},
+					},
+					Dataset: "site-a",
+					Name:    "temperature",
+					X:       int32(i % 3),
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			expectRecordCount(t, d, "site-a", 10)
+
+			removed, err := d.Truncate("site-a", time.Unix(4, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if removed != 4 {
+				t.Errorf("expected 4 records removed, received %d", removed)
+			}
+
+			records, err := d.RetrieveRecords(&server.Query{Dataset: "site-a"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(records) != 6 {
+				t.Errorf("expected 6 records, received %d", len(records))
+			}
+
+			for _, r := range records {
+				if r.Meta.When.AsTime().Before(time.Unix(4, 0)) {
+					t.Errorf("unexpected record from %s", r.Meta.When.AsTime())
+				}
+			}
+
+			d.data["site-a"].each(0, 10, 0, 10, func(_, _ int32, c *cell) {
+				for _, s := range c.series {
+					if len(s.records) != cap(s.records) {
+						t.Errorf("expected truncated series to be right-sized, len %d cap %d", len(s.records), cap(s.records))
+					}
+				}
+			})
+
+			if _, ok := d.indices["site-a"]["robot"].values["robo-001"]; ok {
+				t.Errorf("expected empty index value to be dropped")
+			}
+
+			records, err = d.RetrieveRecords(&server.Query{Dataset: "site-a", IndexKey: "robot", IndexValue: "robo-002"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(records) != 6 {
+				t.Errorf("expected 6 indexed records, received %d", len(records))
+			}
+
+			stats := d.Stats()["site-a"]
+			if stats.RecordCount != 6 {
+				t.Errorf("expected RecordCount 6, received %d", stats.RecordCount)
+			}
+
+			if stats.TotalSize != 6*recordSize {
+				t.Errorf("expected TotalSize %d, received %d", 6*recordSize, stats.TotalSize)
+			}
+		})
+	}
+
+	t.Run("Invalid datasets error", func(t *testing.T) {
+		d, err := New()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, ds := range []string{"", "site-b"} {
+			_, err = d.Truncate(ds, time.Now())
+			if err == nil {
+				t.Errorf("%q: expected error, received none", ds)
+			}
+		}
+	})
+}
+
+func TestDatabase_expire(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []*server.Schema{
+		{Dataset: "short-lived", XMax: 1, YMax: 1, Retention: durationpb.New(time.Hour)},
+		{Dataset: "forever", XMax: 1, YMax: 1},
+	} {
+		err = d.CreateDataset(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+
+	for _, ds := range []string{"short-lived", "forever"} {
+		for _, ts := range []time.Time{now.Add(-2 * time.Hour), now} {
+			err = d.InsertRecord(&server.Record{
+				Meta:    &server.Metadata{When: timestamppb.New(ts)},
+				Dataset: ds,
+				Name:    "temperature",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		expectRecordCount(t, d, ds, 2)
+	}
+
+	err = d.expire(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for ds, expect := range map[string]int{"short-lived": 1, "forever": 2} {
+		records, err := d.RetrieveRecords(&server.Query{Dataset: ds})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(records) != expect {
+			t.Errorf("%s: expected %d records, received %d", ds, expect, len(records))
+		}
+	}
+}
+
+func TestDatabase_RunJanitor(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Janitors which are turned off return straight
+	// away, rather than blocking until cancelled
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		d.RunJanitor(context.Background(), interval)
+	}
+}
+
+func TestDatabase_Truncate_WAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "xyt.wal")
+
+	writeTestWAL(t, path)
+
+	w, err := OpenWAL(path, WALOptions{Policy: SyncAlways})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.UseWAL(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = d.Truncate("site-a", time.Unix(5, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, err = OpenWAL(path, WALOptions{Policy: SyncAlways})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer w.Close()
+
+	d, err = New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.UseWAL(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := d.RetrieveRecords(&server.Query{Dataset: "site-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 5 {
+		t.Errorf("expected 5 records after replay, received %d", len(records))
+	}
+}
+
+// expectRecordCount checks that a dataset's stats
+// account for exactly count records
+func expectRecordCount(t *testing.T, d *Database, ds string, count uint32) {
+	t.Helper()
+
+	if received := d.Stats()[ds].RecordCount; received != count {
+		t.Fatalf("expected stats for %s to hold %d records, received %d", ds, count, received)
+	}
+}
@@ -0,0 +1,283 @@
+package xyt
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// A rollup holds the aggregates for every record of a single Name, at a
+// single location, which fall within a single period of a rollup tier
+type rollup struct {
+	// start is the start of the period this rollup
+	// covers, in nanoseconds since the epoch
+	start int64
+
+	aggregator
+}
+
+// rollup adds r to each of the rollup tiers in tiers
+func (c *cell) rollup(r *server.Record, tiers []*server.RollupTier) {
+	if len(tiers) == 0 {
+		return
+	}
+
+	s := c.get(r.Name)
+
+	if len(s.rollups) < len(tiers) {
+		s.rollups = slices.Grow(s.rollups, len(tiers)-len(s.rollups))[:len(tiers)]
+	}
+
+	when := r.Meta.When.AsTime()
+	for i, tier := range tiers {
+		b := bucketing{width: int64(tier.Resolution.AsDuration())}
+
+		s.bucket(i, b.floor(when)).add(r)
+	}
+}
+
+// bucket returns the rollup for tier starting at start, creating it
+// where necessary. Rollups are kept sorted by start
+func (s *series) bucket(tier int, start int64) *rollup {
+	rollups := s.rollups[tier]
+
+	// Much like records, rollups overwhelmingly arrive in order, and
+	// in fact mostly land in the latest rollup
+	if n := len(rollups); n > 0 && rollups[n-1].start == start {
+		return rollups[n-1]
+	}
+
+	i, found := slices.BinarySearchFunc(rollups, start, func(r *rollup, start int64) int {
+		return cmp.Compare(r.start, start)
+	})
+	if found {
+		return rollups[i]
+	}
+
+	r := &rollup{start: start}
+	s.rollups[tier] = slices.Insert(rollups, i, r)
+
+	return r
+}
+
+// empty returns true where s holds neither records nor rollups
+func (s *series) empty() bool {
+	if s.len() > 0 {
+		return false
+	}
+
+	for _, rollups := range s.rollups {
+		if len(rollups) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// truncateRollups drops every rollup in tier which ends
+// at or before before
+func (c *cell) truncateRollups(tier int, before time.Time, resolution time.Duration) {
+	cutoff := before.Add(-resolution).UnixNano()
+
+	for _, s := range c.series {
+		if len(s.rollups) <= tier {
+			continue
+		}
+
+		// Rollups are always sorted, so everything
+		// we want to drop sits at the start
+		n, _ := slices.BinarySearchFunc(s.rollups[tier], cutoff, func(r *rollup, cutoff int64) int {
+			if r.start <= cutoff {
+				return -1
+			}
+
+			return 1
+		})
+
+		if n > 0 {
+			s.rollups[tier] = slices.Clone(s.rollups[tier][n:])
+		}
+	}
+}
+
+// rollupTier returns the index of the rollup tier which should serve q, or
+// -1 where q should be served from records.
+//
+// Only queries with a time range reaching back beyond the schema's Retention
+// are served from rollups. Rollups don't hold theta, Z, or index values, and so
+// queries filtering on any of them are always served from records
+func (m matcher) rollupTier(s *server.Schema, q *server.Query, now time.Time) int {
+	if len(s.Rollups) == 0 || q.GetTimeRange() == nil || q.IndexKey != "" || !m.allThetas() || !m.zAll {
+		return -1
+	}
+
+	covers := func(retention time.Duration) bool {
+		return retention <= 0 || !m.timeStart.Before(now.Add(-retention))
+	}
+
+	if covers(s.Retention.AsDuration()) {
+		return -1
+	}
+
+	for i, tier := range s.Rollups {
+		if covers(tier.Retention.AsDuration()) {
+			return i
+		}
+	}
+
+	// Nothing covers the whole range, so go with
+	// whatever reaches back furthest
+	best, furthest := -1, s.Retention.AsDuration()
+	for i, tier := range s.Rollups {
+		if tier.Retention.AsDuration() > furthest {
+			best, furthest = i, tier.Retention.AsDuration()
+		}
+	}
+
+	return best
+}
+
+func (m matcher) allThetas() bool {
+	return m.tAll || (m.tMin <= 0 && m.tMax >= m.tBuckets)
+}
+
+// visitRollups passes each of the rollups in tier from c which overlap
+// the query's time range to fn, along with a record standing in for it
+func (m matcher) visitRollups(dataset string, x, y int32, c *cell, tier int, resolution time.Duration, fn visitFunc) {
+	for _, s := range c.series {
+		if len(s.rollups) <= tier || (len(m.names) > 0 && !slices.Contains(m.names, s.name)) {
+			continue
+		}
+
+		for _, r := range s.rollups[tier] {
+			start := time.Unix(0, r.start)
+
+			if !m.timeAll {
+				if !start.Add(resolution).After(m.timeStart) {
+					continue
+				}
+
+				if start.After(m.timeEnd) {
+					break
+				}
+			}
+
+			fn(m.worldRecord(&server.Record{
+				Meta:    &server.Metadata{When: timestamppb.New(start)},
+				Dataset: dataset,
+				Name:    s.name,
+				Value:   r.mean,
+				X:       x,
+				Y:       y,
+			}), &r.aggregator)
+		}
+	}
+}
+
+// rollupBuckets returns every rollup within c, at (x,y) in dataset,
+// as RollupBuckets, such as for writing to a snapshot
+func (c *cell) rollupBuckets(dataset string, x, y int32) (buckets []*server.RollupBucket) {
+	for _, s := range c.series {
+		for tier, rollups := range s.rollups {
+			for _, r := range rollups {
+				buckets = append(buckets, &server.RollupBucket{
+					Dataset:   dataset,
+					X:         x,
+					Y:         y,
+					Name:      s.name,
+					Tier:      uint32(tier), // #nosec: G115
+					Start:     timestamppb.New(time.Unix(0, r.start)),
+					Count:     r.count,
+					Sum:       r.sum,
+					Min:       r.min,
+					Max:       r.max,
+					Mean:      r.mean,
+					M2:        r.m2,
+					First:     r.first,
+					Last:      r.last,
+					FirstWhen: timestamppb.New(r.firstWhen),
+					LastWhen:  timestamppb.New(r.lastWhen),
+				})
+			}
+		}
+	}
+
+	return
+}
+
+// restoreRollup sets a single rollup from b, replacing whatever
+// was there before. It is used when replaying WALs
+func (d *Database) restoreRollup(b *server.RollupBucket) (err error) {
+	unlock := d.lock(b.Dataset)
+	defer unlock()
+
+	schema, ok := d.schemata[b.Dataset]
+	if !ok {
+		return UnknownDatasetError
+	}
+
+	err = validateRollupBucket(schema, b)
+	if err != nil {
+		return
+	}
+
+	return d.setRollup(b)
+}
+
+// setRollup sets a single rollup from b, replacing whatever was there
+// before, and logs it to the WAL where the Database has one.
+//
+// b must be valid for its dataset, and setRollup must be called with
+// b.Dataset's lock held; see lock
+func (d *Database) setRollup(b *server.RollupBucket) (err error) {
+	if d.wal != nil {
+		err = d.wal.appendRollup(b)
+		if err != nil {
+			return
+		}
+	}
+
+	schema := d.schemata[b.Dataset]
+
+	c := d.data[b.Dataset].allocate(b.X, b.Y)
+
+	s := c.get(b.Name)
+	if s == nil {
+		s = &series{name: b.Name}
+		c.series = append(c.series, s)
+	}
+
+	if len(s.rollups) < len(schema.Rollups) {
+		s.rollups = slices.Grow(s.rollups, len(schema.Rollups)-len(s.rollups))[:len(schema.Rollups)]
+	}
+
+	r := s.bucket(int(b.Tier), b.Start.AsTime().UnixNano())
+	r.aggregator = aggregator{
+		count:     b.Count,
+		sum:       b.Sum,
+		min:       b.Min,
+		max:       b.Max,
+		mean:      b.Mean,
+		m2:        b.M2,
+		first:     b.First,
+		last:      b.Last,
+		firstWhen: b.FirstWhen.AsTime(),
+		lastWhen:  b.LastWhen.AsTime(),
+	}
+
+	return
+}
+
+// validateRollupBucket ensures b fits within schema
+func validateRollupBucket(schema *server.Schema, b *server.RollupBucket) error {
+	if int(b.Tier) >= len(schema.Rollups) || b.Name == "" || b.Start == nil ||
+		b.X < schema.XMin || b.X >= schema.XMax || b.Y < schema.YMin || b.Y >= schema.YMax {
+		return InvalidRollupBucketError
+	}
+
+	return nil
+}
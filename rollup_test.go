@@ -0,0 +1,283 @@
+package xyt
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// rollupNow is the time rollup tests pretend it is
+var rollupNow = time.Date(2025, 1, 1, 12, 0, 30, 0, time.UTC)
+
+// rollupDatabase returns a database with a single dataset, warehouse,
+// whose records have been expired into rollups, logging everything to
+// w where w is non-nil
+func rollupDatabase(t *testing.T, w *WAL) *Database {
+	t.Helper()
+
+	var records []*server.Record
+	for _, r := range []struct {
+		ago   time.Duration
+		value float64
+	}{
+		{48 * time.Hour, 10},
+		{3 * time.Hour, 2},
+		{3*time.Hour - 10*time.Second, 4},
+		{3*time.Hour - time.Minute, 6},
+		{30 * time.Minute, 8},
+	} {
+		records = append(records, &server.Record{
+			Meta:    &server.Metadata{When: timestamppb.New(rollupNow.Add(-r.ago))},
+			Dataset: "warehouse",
+			Name:    "temperature",
+			Value:   r.value,
+			X:       1,
+			Y:       2,
+			T:       90,
+		})
+	}
+
+	d := testDatabase(t, w, []*server.Schema{{
+		Dataset:      "warehouse",
+		XMax:         10,
+		YMax:         10,
+		SortOnInsert: true,
+		Retention:    durationpb.New(time.Hour),
+		Rollups: []*server.RollupTier{
+			{Resolution: durationpb.New(time.Minute), Retention: durationpb.New(24 * time.Hour)},
+			{Resolution: durationpb.New(time.Hour)},
+		},
+	}}, records)
+
+	d.now = func() time.Time { return rollupNow }
+
+	expectRecordCount(t, d, "warehouse", 5)
+
+	err := d.expire(rollupNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return d
+}
+
+func rollupQuery(since time.Duration) *server.Query {
+	return &server.Query{
+		Dataset: "warehouse",
+		Time: &server.Query_TimeRange{TimeRange: &server.TimeRange{
+			Start: timestamppb.New(rollupNow.Add(-since)),
+			End:   timestamppb.New(rollupNow),
+		}},
+	}
+}
+
+func testRollupQueries(t *testing.T, d *Database) {
+	t.Helper()
+
+	thetaQuery := rollupQuery(4 * time.Hour)
+	thetaQuery.T = &server.Query_TValue{TValue: 90}
+
+	for _, test := range []struct {
+		name         string
+		query        *server.Query
+		expectValues []float64
+		expectCount  float64
+		expectMean   float64
+	}{
+		{"Recent queries are served from records", rollupQuery(40 * time.Minute), []float64{8}, 1, 8},
+		{"Older queries are served from the finest covering tier", rollupQuery(4 * time.Hour), []float64{3, 6, 8}, 4, 5},
+		{"Queries older than every tier but the last are served from the last", rollupQuery(72 * time.Hour), []float64{10, 4, 8}, 5, 6},
+		{"Theta queries are always served from records", thetaQuery, []float64{8}, 1, 8},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			records, err := d.RetrieveRecords(test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(test.expectValues) != len(records) {
+				t.Fatalf("expected %d records, received %d", len(test.expectValues), len(records))
+			}
+
+			for i, r := range records {
+				if test.expectValues[i] != r.Value {
+					t.Errorf("record %d: expected %v, received %v", i, test.expectValues[i], r.Value)
+				}
+			}
+
+			results, err := d.Aggregate(test.query, []server.Aggregation{server.Aggregation_Count, server.Aggregation_Mean}, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.expectCount != results[0].Values[0].Value {
+				t.Errorf("expected count %v, received %v", test.expectCount, results[0].Values[0].Value)
+			}
+
+			if !floatsMatch(test.expectMean, results[0].Values[1].Value) {
+				t.Errorf("expected mean %v, received %v", test.expectMean, results[0].Values[1].Value)
+			}
+		})
+	}
+}
+
+func TestDatabase_Rollups(t *testing.T) {
+	testRollupQueries(t, rollupDatabase(t, nil))
+}
+
+func TestDatabase_Rollups_Snapshot(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	err := rollupDatabase(t, nil).Snapshot(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "xyt.wal")
+
+	w, err := OpenWAL(path, WALOptions{Policy: SyncAlways})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.now = func() time.Time { return rollupNow }
+
+	err = d.UseWAL(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.Restore(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("restored", func(t *testing.T) {
+		testRollupQueries(t, d)
+	})
+
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, err = OpenWAL(path, WALOptions{Policy: SyncAlways})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer w.Close()
+
+	d, err = New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.now = func() time.Time { return rollupNow }
+
+	err = d.UseWAL(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("replayed", func(t *testing.T) {
+		testRollupQueries(t, d)
+	})
+}
+
+func TestDatabase_Rollups_WAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "xyt.wal")
+
+	w, err := OpenWAL(path, WALOptions{Policy: SyncAlways})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := rollupDatabase(t, w)
+	expect := rollupState(d)
+
+	before, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Everything due to expire already has, so the janitor
+	// has nothing to log
+	err = d.expire(rollupNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if before.Size() != after.Size() {
+		t.Errorf("expected WAL to stay at %d bytes, received %d", before.Size(), after.Size())
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, err = OpenWAL(path, WALOptions{Policy: SyncAlways})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer w.Close()
+
+	d, err = New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.now = func() time.Time { return rollupNow }
+
+	err = d.UseWAL(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Expired rollups aren't logged, and so come back on replay,
+	// until the janitor next runs
+	if len(rollupState(d)) <= len(expect) {
+		t.Errorf("expected expired rollups to be rebuilt on replay")
+	}
+
+	err = d.expire(rollupNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received := rollupState(d)
+	if !slices.EqualFunc(expect, received, func(a, b *server.RollupBucket) bool { return proto.Equal(a, b) }) {
+		t.Errorf("expected %v, received %v", expect, received)
+	}
+
+	testRollupQueries(t, d)
+}
+
+// rollupState returns every rollup in the warehouse dataset
+func rollupState(d *Database) (buckets []*server.RollupBucket) {
+	d.data["warehouse"].each(0, 10, 0, 10, func(x, y int32, c *cell) {
+		buckets = append(buckets, c.rollupBuckets("warehouse", x, y)...)
+	})
+
+	return
+}
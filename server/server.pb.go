@@ -9,6 +9,7 @@ package server
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
@@ -74,6 +75,230 @@ func (Frequency) EnumDescriptor() ([]byte, []int) {
 	return file_server_proto_rawDescGZIP(), []int{0}
 }
 
+type Projection int32
+
+const (
+	// ENU projects onto a plane touching the Earth at the Geo's origin,
+	// with X pointing east and Y pointing north. It suits small sites
+	Projection_ENU Projection = 0
+	// UTM projects onto a Universal Transverse Mercator zone, with X
+	// and Y as eastings and northings
+	Projection_UTM Projection = 1
+)
+
+// Enum value maps for Projection.
+var (
+	Projection_name = map[int32]string{
+		0: "ENU",
+		1: "UTM",
+	}
+	Projection_value = map[string]int32{
+		"ENU": 0,
+		"UTM": 1,
+	}
+)
+
+func (x Projection) Enum() *Projection {
+	p := new(Projection)
+	*p = x
+	return p
+}
+
+func (x Projection) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Projection) Descriptor() protoreflect.EnumDescriptor {
+	return file_server_proto_enumTypes[1].Descriptor()
+}
+
+func (Projection) Type() protoreflect.EnumType {
+	return &file_server_proto_enumTypes[1]
+}
+
+func (x Projection) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Projection.Descriptor instead.
+func (Projection) EnumDescriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{1}
+}
+
+// SlowSubscriberPolicy determines what happens to a subscription when
+// its subscriber falls behind, and its buffer fills up
+type SlowSubscriberPolicy int32
+
+const (
+	// DropRecords drops new records until the subscriber catches up
+	SlowSubscriberPolicy_DropRecords SlowSubscriberPolicy = 0
+	// Disconnect ends the subscription
+	SlowSubscriberPolicy_Disconnect SlowSubscriberPolicy = 1
+)
+
+// Enum value maps for SlowSubscriberPolicy.
+var (
+	SlowSubscriberPolicy_name = map[int32]string{
+		0: "DropRecords",
+		1: "Disconnect",
+	}
+	SlowSubscriberPolicy_value = map[string]int32{
+		"DropRecords": 0,
+		"Disconnect":  1,
+	}
+)
+
+func (x SlowSubscriberPolicy) Enum() *SlowSubscriberPolicy {
+	p := new(SlowSubscriberPolicy)
+	*p = x
+	return p
+}
+
+func (x SlowSubscriberPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SlowSubscriberPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_server_proto_enumTypes[2].Descriptor()
+}
+
+func (SlowSubscriberPolicy) Type() protoreflect.EnumType {
+	return &file_server_proto_enumTypes[2]
+}
+
+func (x SlowSubscriberPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SlowSubscriberPolicy.Descriptor instead.
+func (SlowSubscriberPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{2}
+}
+
+type Aggregation int32
+
+const (
+	Aggregation_Count  Aggregation = 0
+	Aggregation_Sum    Aggregation = 1
+	Aggregation_Min    Aggregation = 2
+	Aggregation_Max    Aggregation = 3
+	Aggregation_Mean   Aggregation = 4
+	Aggregation_Stddev Aggregation = 5
+	Aggregation_First  Aggregation = 6
+	Aggregation_Last   Aggregation = 7
+)
+
+// Enum value maps for Aggregation.
+var (
+	Aggregation_name = map[int32]string{
+		0: "Count",
+		1: "Sum",
+		2: "Min",
+		3: "Max",
+		4: "Mean",
+		5: "Stddev",
+		6: "First",
+		7: "Last",
+	}
+	Aggregation_value = map[string]int32{
+		"Count":  0,
+		"Sum":    1,
+		"Min":    2,
+		"Max":    3,
+		"Mean":   4,
+		"Stddev": 5,
+		"First":  6,
+		"Last":   7,
+	}
+)
+
+func (x Aggregation) Enum() *Aggregation {
+	p := new(Aggregation)
+	*p = x
+	return p
+}
+
+func (x Aggregation) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Aggregation) Descriptor() protoreflect.EnumDescriptor {
+	return file_server_proto_enumTypes[3].Descriptor()
+}
+
+func (Aggregation) Type() protoreflect.EnumType {
+	return &file_server_proto_enumTypes[3]
+}
+
+func (x Aggregation) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Aggregation.Descriptor instead.
+func (Aggregation) EnumDescriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{3}
+}
+
+// FillPolicy determines what a TimeSeries does with buckets
+// which contain no records
+type FillPolicy int32
+
+const (
+	// FillNone leaves empty buckets out of the series entirely
+	FillPolicy_FillNone FillPolicy = 0
+	// FillNull includes empty buckets, with null set
+	FillPolicy_FillNull FillPolicy = 1
+	// FillPrevious gives empty buckets the value of the bucket before
+	// them; empty buckets before the first populated bucket are null
+	FillPolicy_FillPrevious FillPolicy = 2
+	// FillLinear interpolates empty buckets between the populated buckets
+	// either side of them; empty buckets at either end are null
+	FillPolicy_FillLinear FillPolicy = 3
+)
+
+// Enum value maps for FillPolicy.
+var (
+	FillPolicy_name = map[int32]string{
+		0: "FillNone",
+		1: "FillNull",
+		2: "FillPrevious",
+		3: "FillLinear",
+	}
+	FillPolicy_value = map[string]int32{
+		"FillNone":     0,
+		"FillNull":     1,
+		"FillPrevious": 2,
+		"FillLinear":   3,
+	}
+)
+
+func (x FillPolicy) Enum() *FillPolicy {
+	p := new(FillPolicy)
+	*p = x
+	return p
+}
+
+func (x FillPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FillPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_server_proto_enumTypes[4].Descriptor()
+}
+
+func (FillPolicy) Type() protoreflect.EnumType {
+	return &file_server_proto_enumTypes[4]
+}
+
+func (x FillPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FillPolicy.Descriptor instead.
+func (FillPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{4}
+}
+
 type StatsMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -297,6 +522,70 @@ type Schema struct {
 	// map, with a high range of different theta values, then you want to set
 	// this to false so performance is predictable
 	LazyInitialAllocate bool `protobuf:"varint,8,opt,name=lazy_initial_allocate,json=lazyInitialAllocate,proto3" json:"lazy_initial_allocate,omitempty"`
+	// MaxIndexCardinality is the number of distinct values any single
+	// index key may hold within this dataset; inserts which would exceed
+	// this are rejected.
+	//
+	// Where unset, a sensible default is used
+	MaxIndexCardinality uint32 `protobuf:"varint,9,opt,name=max_index_cardinality,json=maxIndexCardinality,proto3" json:"max_index_cardinality,omitempty"`
+	// Retention is how long records are kept for, based on their `When`
+	// value; older records are periodically dropped.
+	//
+	// Where unset, records are kept forever
+	Retention *durationpb.Duration `protobuf:"bytes,10,opt,name=retention,proto3" json:"retention,omitempty"`
+	// Rollups configure coarser copies of this dataset's records, aggregated
+	// per location, per name, over each rollup tier's resolution, and kept
+	// for (typically) much longer than Retention.
+	//
+	// Tiers must be ordered from finest to coarsest resolution. Queries with
+	// a time range reaching back beyond Retention are transparently served
+	// from the finest tier which covers the whole range
+	Rollups []*RollupTier `protobuf:"bytes,11,rep,name=rollups,proto3" json:"rollups,omitempty"`
+	// Columnar stores readings column by column, per location and name,
+	// compressing older readings into blocks, rather than as full records.
+	// This uses a fraction of the memory, at the cost of some query speed.
+	//
+	// Columnar datasets only keep each record's timestamp, theta and value;
+	// labels and index values are dropped, and so columnar datasets can't be
+	// queried by index
+	Columnar bool `protobuf:"varint,12,opt,name=columnar,proto3" json:"columnar,omitempty"`
+	// Sparse only allocates the locations records are actually inserted
+	// into, rather than a slot for every location within the bounds. This
+	// suits huge grids where only a sliver of locations are ever visited,
+	// at the cost of slightly slower inserts and lookups.
+	//
+	// Sparse datasets are always lazily allocated, and so ignore
+	// LazyInitialAllocate
+	Sparse bool `protobuf:"varint,13,opt,name=sparse,proto3" json:"sparse,omitempty"`
+	// Zones maps names, such as "dock-3", to regions of this dataset,
+	// so that queries can refer to the same regions by name. Zones
+	// are usually managed with AddZone and DeleteZone
+	Zones map[string]*Polygon `protobuf:"bytes,14,rep,name=zones,proto3" json:"zones,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// ThetaResolution is the size, in degrees, of each theta bucket. Records
+	// are binned into a bucket as they're inserted, and their T holds that
+	// bucket, so with a resolution of 0.1 a record at 90.25 degrees has a T
+	// of 902. Queries on T use buckets too.
+	//
+	// Resolutions must divide 360 evenly. Where unset, buckets are one degree
+	// wide, and T is simply in degrees
+	ThetaResolution float64 `protobuf:"fixed64,15,opt,name=theta_resolution,json=thetaResolution,proto3" json:"theta_resolution,omitempty"`
+	// Transform, when set, maps this dataset's grid onto real world
+	// coordinates, so that records and queries can use those instead
+	Transform *Transform `protobuf:"bytes,16,opt,name=transform,proto3" json:"transform,omitempty"`
+	// Geo, when set, places this dataset on the Earth, so that records can
+	// be inserted by latitude and longitude, and exported as GeoJSON. Geo
+	// projects latitudes and longitudes into world coordinates, in metres,
+	// and so requires a Transform to map those onto the grid
+	Geo *Geo `protobuf:"bytes,17,opt,name=geo,proto3" json:"geo,omitempty"`
+	// ZMin and ZMax give this dataset a third, vertical, axis, such as the
+	// floors of a warehouse or a drone's altitude band. Like X and Y, ZMax
+	// is exclusive.
+	//
+	// Where both are unset the dataset is flat, and every record has a Z of
+	// 0. Z is stored on each record, rather than in the grid, so flat
+	// datasets cost no more than they did. Columnar datasets can't have Z
+	ZMin int32 `protobuf:"zigzag32,18,opt,name=z_min,json=zMin,proto3" json:"z_min,omitempty"`
+	ZMax int32 `protobuf:"zigzag32,19,opt,name=z_max,json=zMax,proto3" json:"z_max,omitempty"`
 }
 
 func (x *Schema) Reset() {
@@ -387,20 +676,104 @@ func (x *Schema) GetLazyInitialAllocate() bool {
 	return false
 }
 
-type SchemaStats struct {
+func (x *Schema) GetMaxIndexCardinality() uint32 {
+	if x != nil {
+		return x.MaxIndexCardinality
+	}
+	return 0
+}
+
+func (x *Schema) GetRetention() *durationpb.Duration {
+	if x != nil {
+		return x.Retention
+	}
+	return nil
+}
+
+func (x *Schema) GetRollups() []*RollupTier {
+	if x != nil {
+		return x.Rollups
+	}
+	return nil
+}
+
+func (x *Schema) GetColumnar() bool {
+	if x != nil {
+		return x.Columnar
+	}
+	return false
+}
+
+func (x *Schema) GetSparse() bool {
+	if x != nil {
+		return x.Sparse
+	}
+	return false
+}
+
+func (x *Schema) GetZones() map[string]*Polygon {
+	if x != nil {
+		return x.Zones
+	}
+	return nil
+}
+
+func (x *Schema) GetThetaResolution() float64 {
+	if x != nil {
+		return x.ThetaResolution
+	}
+	return 0
+}
+
+func (x *Schema) GetTransform() *Transform {
+	if x != nil {
+		return x.Transform
+	}
+	return nil
+}
+
+func (x *Schema) GetGeo() *Geo {
+	if x != nil {
+		return x.Geo
+	}
+	return nil
+}
+
+func (x *Schema) GetZMin() int32 {
+	if x != nil {
+		return x.ZMin
+	}
+	return 0
+}
+
+func (x *Schema) GetZMax() int32 {
+	if x != nil {
+		return x.ZMax
+	}
+	return 0
+}
+
+type Geo struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Schema      *Schema  `protobuf:"bytes,1,opt,name=schema,proto3" json:"schema,omitempty"`
-	Records     uint32   `protobuf:"varint,2,opt,name=records,proto3" json:"records,omitempty"`
-	TotalSize   uint64   `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
-	AverageSize uint64   `protobuf:"varint,4,opt,name=average_size,json=averageSize,proto3" json:"average_size,omitempty"`
-	Fields      []string `protobuf:"bytes,5,rep,name=fields,proto3" json:"fields,omitempty"`
-}
-
-func (x *SchemaStats) Reset() {
-	*x = SchemaStats{}
+	Projection Projection `protobuf:"varint,1,opt,name=projection,proto3,enum=server.Projection" json:"projection,omitempty"`
+	// origin_latitude and origin_longitude are where an ENU
+	// projection touches the Earth
+	OriginLatitude  float64 `protobuf:"fixed64,2,opt,name=origin_latitude,json=originLatitude,proto3" json:"origin_latitude,omitempty"`
+	OriginLongitude float64 `protobuf:"fixed64,3,opt,name=origin_longitude,json=originLongitude,proto3" json:"origin_longitude,omitempty"`
+	// utm_zone, from 1 to 60, and south pick the zone a
+	// UTM projection uses
+	UtmZone uint32 `protobuf:"varint,4,opt,name=utm_zone,json=utmZone,proto3" json:"utm_zone,omitempty"`
+	South   bool   `protobuf:"varint,5,opt,name=south,proto3" json:"south,omitempty"`
+	// bounds, when set on a schema with no X and Y bounds of its own,
+	// is used to work out those bounds when the dataset is created
+	Bounds *GeoBox `protobuf:"bytes,6,opt,name=bounds,proto3" json:"bounds,omitempty"`
+}
+
+func (x *Geo) Reset() {
+	*x = Geo{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_server_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -408,13 +781,13 @@ func (x *SchemaStats) Reset() {
 	}
 }
 
-func (x *SchemaStats) String() string {
+func (x *Geo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SchemaStats) ProtoMessage() {}
+func (*Geo) ProtoMessage() {}
 
-func (x *SchemaStats) ProtoReflect() protoreflect.Message {
+func (x *Geo) ProtoReflect() protoreflect.Message {
 	mi := &file_server_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -426,82 +799,66 @@ func (x *SchemaStats) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SchemaStats.ProtoReflect.Descriptor instead.
-func (*SchemaStats) Descriptor() ([]byte, []int) {
+// Deprecated: Use Geo.ProtoReflect.Descriptor instead.
+func (*Geo) Descriptor() ([]byte, []int) {
 	return file_server_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *SchemaStats) GetSchema() *Schema {
+func (x *Geo) GetProjection() Projection {
 	if x != nil {
-		return x.Schema
+		return x.Projection
 	}
-	return nil
+	return Projection_ENU
 }
 
-func (x *SchemaStats) GetRecords() uint32 {
+func (x *Geo) GetOriginLatitude() float64 {
 	if x != nil {
-		return x.Records
+		return x.OriginLatitude
 	}
 	return 0
 }
 
-func (x *SchemaStats) GetTotalSize() uint64 {
+func (x *Geo) GetOriginLongitude() float64 {
 	if x != nil {
-		return x.TotalSize
+		return x.OriginLongitude
 	}
 	return 0
 }
 
-func (x *SchemaStats) GetAverageSize() uint64 {
+func (x *Geo) GetUtmZone() uint32 {
 	if x != nil {
-		return x.AverageSize
+		return x.UtmZone
 	}
 	return 0
 }
 
-func (x *SchemaStats) GetFields() []string {
+func (x *Geo) GetSouth() bool {
 	if x != nil {
-		return x.Fields
+		return x.South
+	}
+	return false
+}
+
+func (x *Geo) GetBounds() *GeoBox {
+	if x != nil {
+		return x.Bounds
 	}
 	return nil
 }
 
-type Query struct {
+type GeoBox struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Dataset string `protobuf:"bytes,1,opt,name=dataset,proto3" json:"dataset,omitempty"`
-	// Types that are assignable to X:
-	//
-	//	*Query_XAll
-	//	*Query_XValue
-	//	*Query_XRange
-	X isQuery_X `protobuf_oneof:"x"`
-	// Types that are assignable to Y:
-	//
-	//	*Query_YAll
-	//	*Query_YValue
-	//	*Query_YRange
-	Y isQuery_Y `protobuf_oneof:"y"`
-	// Types that are assignable to T:
-	//
-	//	*Query_TAll
-	//	*Query_TValue
-	//	*Query_TRange
-	T isQuery_T `protobuf_oneof:"t"`
-	// Types that are assignable to Time:
-	//
-	//	*Query_TimeAll
-	//	*Query_TimeLatest
-	//	*Query_TimeRange
-	Time       isQuery_Time `protobuf_oneof:"time"`
-	IndexKey   string       `protobuf:"bytes,11,opt,name=index_key,json=indexKey,proto3" json:"index_key,omitempty"`
-	IndexValue string       `protobuf:"bytes,12,opt,name=index_value,json=indexValue,proto3" json:"index_value,omitempty"`
+	MinLatitude  float64 `protobuf:"fixed64,1,opt,name=min_latitude,json=minLatitude,proto3" json:"min_latitude,omitempty"`
+	MinLongitude float64 `protobuf:"fixed64,2,opt,name=min_longitude,json=minLongitude,proto3" json:"min_longitude,omitempty"`
+	MaxLatitude  float64 `protobuf:"fixed64,3,opt,name=max_latitude,json=maxLatitude,proto3" json:"max_latitude,omitempty"`
+	MaxLongitude float64 `protobuf:"fixed64,4,opt,name=max_longitude,json=maxLongitude,proto3" json:"max_longitude,omitempty"`
 }
 
-func (x *Query) Reset() {
-	*x = Query{}
+func (x *GeoBox) Reset() {
+	*x = GeoBox{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_server_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -509,13 +866,13 @@ func (x *Query) Reset() {
 	}
 }
 
-func (x *Query) String() string {
+func (x *GeoBox) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Query) ProtoMessage() {}
+func (*GeoBox) ProtoMessage() {}
 
-func (x *Query) ProtoReflect() protoreflect.Message {
+func (x *GeoBox) ProtoReflect() protoreflect.Message {
 	mi := &file_server_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -527,258 +884,258 @@ func (x *Query) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Query.ProtoReflect.Descriptor instead.
-func (*Query) Descriptor() ([]byte, []int) {
+// Deprecated: Use GeoBox.ProtoReflect.Descriptor instead.
+func (*GeoBox) Descriptor() ([]byte, []int) {
 	return file_server_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *Query) GetDataset() string {
+func (x *GeoBox) GetMinLatitude() float64 {
 	if x != nil {
-		return x.Dataset
-	}
-	return ""
-}
-
-func (m *Query) GetX() isQuery_X {
-	if m != nil {
-		return m.X
+		return x.MinLatitude
 	}
-	return nil
+	return 0
 }
 
-func (x *Query) GetXAll() bool {
-	if x, ok := x.GetX().(*Query_XAll); ok {
-		return x.XAll
+func (x *GeoBox) GetMinLongitude() float64 {
+	if x != nil {
+		return x.MinLongitude
 	}
-	return false
+	return 0
 }
 
-func (x *Query) GetXValue() int32 {
-	if x, ok := x.GetX().(*Query_XValue); ok {
-		return x.XValue
+func (x *GeoBox) GetMaxLatitude() float64 {
+	if x != nil {
+		return x.MaxLatitude
 	}
 	return 0
 }
 
-func (x *Query) GetXRange() *QueryRange {
-	if x, ok := x.GetX().(*Query_XRange); ok {
-		return x.XRange
+func (x *GeoBox) GetMaxLongitude() float64 {
+	if x != nil {
+		return x.MaxLongitude
 	}
-	return nil
+	return 0
 }
 
-func (m *Query) GetY() isQuery_Y {
-	if m != nil {
-		return m.Y
-	}
-	return nil
+// A GeoPoint is a location on the Earth, in degrees (WGS84)
+type GeoPoint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Latitude  float64 `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude float64 `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
 }
 
-func (x *Query) GetYAll() bool {
-	if x, ok := x.GetY().(*Query_YAll); ok {
-		return x.YAll
+func (x *GeoPoint) Reset() {
+	*x = GeoPoint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return false
 }
 
-func (x *Query) GetYValue() int32 {
-	if x, ok := x.GetY().(*Query_YValue); ok {
-		return x.YValue
-	}
-	return 0
+func (x *GeoPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *Query) GetYRange() *QueryRange {
-	if x, ok := x.GetY().(*Query_YRange); ok {
-		return x.YRange
+func (*GeoPoint) ProtoMessage() {}
+
+func (x *GeoPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (m *Query) GetT() isQuery_T {
-	if m != nil {
-		return m.T
-	}
-	return nil
+// Deprecated: Use GeoPoint.ProtoReflect.Descriptor instead.
+func (*GeoPoint) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *Query) GetTAll() bool {
-	if x, ok := x.GetT().(*Query_TAll); ok {
-		return x.TAll
+func (x *GeoPoint) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
 	}
-	return false
+	return 0
 }
 
-func (x *Query) GetTValue() int32 {
-	if x, ok := x.GetT().(*Query_TValue); ok {
-		return x.TValue
+func (x *GeoPoint) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
 	}
 	return 0
 }
 
-func (x *Query) GetTRange() *QueryRange {
-	if x, ok := x.GetT().(*Query_TRange); ok {
-		return x.TRange
-	}
-	return nil
+// A Transform maps a dataset's grid onto real world coordinates, such
+// as metres from a site's origin. World coordinates are found by scaling
+// grid coordinates by cell_size, rotating them by rotation, and then
+// offsetting them by the origin; the grid's (0,0) sits at the origin
+type Transform struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// cell_size is the width and height of a single cell, in world units
+	CellSize float64 `protobuf:"fixed64,1,opt,name=cell_size,json=cellSize,proto3" json:"cell_size,omitempty"`
+	OriginX  float64 `protobuf:"fixed64,2,opt,name=origin_x,json=originX,proto3" json:"origin_x,omitempty"`
+	OriginY  float64 `protobuf:"fixed64,3,opt,name=origin_y,json=originY,proto3" json:"origin_y,omitempty"`
+	// rotation is the angle, in degrees anticlockwise, from the
+	// world's X axis to the grid's
+	Rotation float64 `protobuf:"fixed64,4,opt,name=rotation,proto3" json:"rotation,omitempty"`
 }
 
-func (m *Query) GetTime() isQuery_Time {
-	if m != nil {
-		return m.Time
+func (x *Transform) Reset() {
+	*x = Transform{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-func (x *Query) GetTimeAll() bool {
-	if x, ok := x.GetTime().(*Query_TimeAll); ok {
-		return x.TimeAll
-	}
-	return false
+func (x *Transform) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *Query) GetTimeLatest() bool {
-	if x, ok := x.GetTime().(*Query_TimeLatest); ok {
-		return x.TimeLatest
+func (*Transform) ProtoMessage() {}
+
+func (x *Transform) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return false
+	return mi.MessageOf(x)
 }
 
-func (x *Query) GetTimeRange() *TimeRange {
-	if x, ok := x.GetTime().(*Query_TimeRange); ok {
-		return x.TimeRange
-	}
-	return nil
+// Deprecated: Use Transform.ProtoReflect.Descriptor instead.
+func (*Transform) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *Query) GetIndexKey() string {
+func (x *Transform) GetCellSize() float64 {
 	if x != nil {
-		return x.IndexKey
+		return x.CellSize
 	}
-	return ""
+	return 0
 }
 
-func (x *Query) GetIndexValue() string {
+func (x *Transform) GetOriginX() float64 {
 	if x != nil {
-		return x.IndexValue
+		return x.OriginX
 	}
-	return ""
-}
-
-type isQuery_X interface {
-	isQuery_X()
-}
-
-type Query_XAll struct {
-	XAll bool `protobuf:"varint,13,opt,name=x_all,json=xAll,proto3,oneof"`
-}
-
-type Query_XValue struct {
-	XValue int32 `protobuf:"zigzag32,2,opt,name=x_value,json=xValue,proto3,oneof"`
-}
-
-type Query_XRange struct {
-	XRange *QueryRange `protobuf:"bytes,3,opt,name=x_range,json=xRange,proto3,oneof"`
-}
-
-func (*Query_XAll) isQuery_X() {}
-
-func (*Query_XValue) isQuery_X() {}
-
-func (*Query_XRange) isQuery_X() {}
-
-type isQuery_Y interface {
-	isQuery_Y()
-}
-
-type Query_YAll struct {
-	YAll bool `protobuf:"varint,14,opt,name=y_all,json=yAll,proto3,oneof"`
+	return 0
 }
 
-type Query_YValue struct {
-	YValue int32 `protobuf:"zigzag32,4,opt,name=y_value,json=yValue,proto3,oneof"`
+func (x *Transform) GetOriginY() float64 {
+	if x != nil {
+		return x.OriginY
+	}
+	return 0
 }
 
-type Query_YRange struct {
-	YRange *QueryRange `protobuf:"bytes,5,opt,name=y_range,json=yRange,proto3,oneof"`
+func (x *Transform) GetRotation() float64 {
+	if x != nil {
+		return x.Rotation
+	}
+	return 0
 }
 
-func (*Query_YAll) isQuery_Y() {}
-
-func (*Query_YValue) isQuery_Y() {}
-
-func (*Query_YRange) isQuery_Y() {}
-
-type isQuery_T interface {
-	isQuery_T()
-}
+// A WorldPoint is a location in world coordinates
+type WorldPoint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-type Query_TAll struct {
-	TAll bool `protobuf:"varint,15,opt,name=t_all,json=tAll,proto3,oneof"`
+	X float64 `protobuf:"fixed64,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y float64 `protobuf:"fixed64,2,opt,name=y,proto3" json:"y,omitempty"`
 }
 
-type Query_TValue struct {
-	TValue int32 `protobuf:"zigzag32,6,opt,name=t_value,json=tValue,proto3,oneof"`
+func (x *WorldPoint) Reset() {
+	*x = WorldPoint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-type Query_TRange struct {
-	TRange *QueryRange `protobuf:"bytes,7,opt,name=t_range,json=tRange,proto3,oneof"`
+func (x *WorldPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Query_TAll) isQuery_T() {}
-
-func (*Query_TValue) isQuery_T() {}
-
-func (*Query_TRange) isQuery_T() {}
+func (*WorldPoint) ProtoMessage() {}
 
-type isQuery_Time interface {
-	isQuery_Time()
+func (x *WorldPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-type Query_TimeAll struct {
-	TimeAll bool `protobuf:"varint,8,opt,name=time_all,json=timeAll,proto3,oneof"`
+// Deprecated: Use WorldPoint.ProtoReflect.Descriptor instead.
+func (*WorldPoint) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{8}
 }
 
-type Query_TimeLatest struct {
-	TimeLatest bool `protobuf:"varint,9,opt,name=time_latest,json=timeLatest,proto3,oneof"`
+func (x *WorldPoint) GetX() float64 {
+	if x != nil {
+		return x.X
+	}
+	return 0
 }
 
-type Query_TimeRange struct {
-	TimeRange *TimeRange `protobuf:"bytes,10,opt,name=time_range,json=timeRange,proto3,oneof"`
+func (x *WorldPoint) GetY() float64 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
 }
 
-func (*Query_TimeAll) isQuery_Time() {}
-
-func (*Query_TimeLatest) isQuery_Time() {}
-
-func (*Query_TimeRange) isQuery_Time() {}
-
-type QueryRange struct {
+type RollupTier struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Start int32 `protobuf:"zigzag32,1,opt,name=start,proto3" json:"start,omitempty"`
-	End   int32 `protobuf:"zigzag32,2,opt,name=end,proto3" json:"end,omitempty"`
+	// resolution is the period of time each rollup covers, such as a minute
+	Resolution *durationpb.Duration `protobuf:"bytes,1,opt,name=resolution,proto3" json:"resolution,omitempty"`
+	// retention is how long rollups are kept for; where unset, they
+	// are kept forever
+	Retention *durationpb.Duration `protobuf:"bytes,2,opt,name=retention,proto3" json:"retention,omitempty"`
 }
 
-func (x *QueryRange) Reset() {
-	*x = QueryRange{}
+func (x *RollupTier) Reset() {
+	*x = RollupTier{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_server_proto_msgTypes[6]
+		mi := &file_server_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *QueryRange) String() string {
+func (x *RollupTier) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryRange) ProtoMessage() {}
+func (*RollupTier) ProtoMessage() {}
 
-func (x *QueryRange) ProtoReflect() protoreflect.Message {
-	mi := &file_server_proto_msgTypes[6]
+func (x *RollupTier) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -789,51 +1146,68 @@ func (x *QueryRange) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryRange.ProtoReflect.Descriptor instead.
-func (*QueryRange) Descriptor() ([]byte, []int) {
-	return file_server_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use RollupTier.ProtoReflect.Descriptor instead.
+func (*RollupTier) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *QueryRange) GetStart() int32 {
+func (x *RollupTier) GetResolution() *durationpb.Duration {
 	if x != nil {
-		return x.Start
+		return x.Resolution
 	}
-	return 0
+	return nil
 }
 
-func (x *QueryRange) GetEnd() int32 {
+func (x *RollupTier) GetRetention() *durationpb.Duration {
 	if x != nil {
-		return x.End
+		return x.Retention
 	}
-	return 0
+	return nil
 }
 
-type TimeRange struct {
+// A RollupBucket holds the aggregates for a single name, at a single
+// location, over a single rollup period. These are used by snapshots
+// and write-ahead logs to persist rollups
+type RollupBucket struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Start *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
-	End   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
-}
-
-func (x *TimeRange) Reset() {
-	*x = TimeRange{}
+	Dataset   string                 `protobuf:"bytes,1,opt,name=dataset,proto3" json:"dataset,omitempty"`
+	X         int32                  `protobuf:"zigzag32,2,opt,name=x,proto3" json:"x,omitempty"`
+	Y         int32                  `protobuf:"zigzag32,3,opt,name=y,proto3" json:"y,omitempty"`
+	Name      string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Tier      uint32                 `protobuf:"varint,5,opt,name=tier,proto3" json:"tier,omitempty"`
+	Start     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=start,proto3" json:"start,omitempty"`
+	Count     uint64                 `protobuf:"varint,7,opt,name=count,proto3" json:"count,omitempty"`
+	Sum       float64                `protobuf:"fixed64,8,opt,name=sum,proto3" json:"sum,omitempty"`
+	Min       float64                `protobuf:"fixed64,9,opt,name=min,proto3" json:"min,omitempty"`
+	Max       float64                `protobuf:"fixed64,10,opt,name=max,proto3" json:"max,omitempty"`
+	Mean      float64                `protobuf:"fixed64,11,opt,name=mean,proto3" json:"mean,omitempty"`
+	M2        float64                `protobuf:"fixed64,12,opt,name=m2,proto3" json:"m2,omitempty"`
+	First     float64                `protobuf:"fixed64,13,opt,name=first,proto3" json:"first,omitempty"`
+	Last      float64                `protobuf:"fixed64,14,opt,name=last,proto3" json:"last,omitempty"`
+	FirstWhen *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=first_when,json=firstWhen,proto3" json:"first_when,omitempty"`
+	LastWhen  *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=last_when,json=lastWhen,proto3" json:"last_when,omitempty"`
+}
+
+func (x *RollupBucket) Reset() {
+	*x = RollupBucket{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_server_proto_msgTypes[7]
+		mi := &file_server_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *TimeRange) String() string {
+func (x *RollupBucket) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TimeRange) ProtoMessage() {}
+func (*RollupBucket) ProtoMessage() {}
 
-func (x *TimeRange) ProtoReflect() protoreflect.Message {
-	mi := &file_server_proto_msgTypes[7]
+func (x *RollupBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -844,173 +1218,152 @@ func (x *TimeRange) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TimeRange.ProtoReflect.Descriptor instead.
-func (*TimeRange) Descriptor() ([]byte, []int) {
-	return file_server_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use RollupBucket.ProtoReflect.Descriptor instead.
+func (*RollupBucket) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *TimeRange) GetStart() *timestamppb.Timestamp {
+func (x *RollupBucket) GetDataset() string {
 	if x != nil {
-		return x.Start
+		return x.Dataset
 	}
-	return nil
+	return ""
 }
 
-func (x *TimeRange) GetEnd() *timestamppb.Timestamp {
+func (x *RollupBucket) GetX() int32 {
 	if x != nil {
-		return x.End
+		return x.X
 	}
-	return nil
+	return 0
 }
 
-// A Record is a specific reading for a set of X,Y coordinates and
-// a theta representing aspect.
-//
-// Alongside this, a Record includes metadata values and a float representing
-// an actual value
-type Record struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Meta *Metadata `protobuf:"bytes,6,opt,name=meta,proto3" json:"meta,omitempty"`
-	X    int32     `protobuf:"zigzag32,1,opt,name=X,proto3" json:"X,omitempty"`
-	Y    int32     `protobuf:"zigzag32,2,opt,name=Y,proto3" json:"Y,omitempty"`
-	T    int32     `protobuf:"zigzag32,3,opt,name=T,proto3" json:"T,omitempty"`
-	// a Dataset is analogous to a database and is best thought of as
-	// a specific location to be mapped, alongside a specific purpose.
-	//
-	// # For a racing bike it might be the race course
-	//
-	// # For an autonomous robot it might be a particular warehouse
-	//
-	// Ultimately, it provides some context toward what a X, Y, T value
-	// actually means in practice
-	Dataset string `protobuf:"bytes,4,opt,name=dataset,proto3" json:"dataset,omitempty"`
-	// Value represents the actual value this record represents
-	Value float64 `protobuf:"fixed64,5,opt,name=value,proto3" json:"value,omitempty"`
-	// Name provides context for what a value is for
-	Name string `protobuf:"bytes,7,opt,name=name,proto3" json:"name,omitempty"`
+func (x *RollupBucket) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
 }
 
-func (x *Record) Reset() {
-	*x = Record{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_server_proto_msgTypes[8]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *RollupBucket) GetName() string {
+	if x != nil {
+		return x.Name
 	}
+	return ""
 }
 
-func (x *Record) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *RollupBucket) GetTier() uint32 {
+	if x != nil {
+		return x.Tier
+	}
+	return 0
 }
 
-func (*Record) ProtoMessage() {}
+func (x *RollupBucket) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
 
-func (x *Record) ProtoReflect() protoreflect.Message {
-	mi := &file_server_proto_msgTypes[8]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *RollupBucket) GetCount() uint64 {
+	if x != nil {
+		return x.Count
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use Record.ProtoReflect.Descriptor instead.
-func (*Record) Descriptor() ([]byte, []int) {
-	return file_server_proto_rawDescGZIP(), []int{8}
+func (x *RollupBucket) GetSum() float64 {
+	if x != nil {
+		return x.Sum
+	}
+	return 0
 }
 
-func (x *Record) GetMeta() *Metadata {
+func (x *RollupBucket) GetMin() float64 {
 	if x != nil {
-		return x.Meta
+		return x.Min
 	}
-	return nil
+	return 0
 }
 
-func (x *Record) GetX() int32 {
+func (x *RollupBucket) GetMax() float64 {
 	if x != nil {
-		return x.X
+		return x.Max
 	}
 	return 0
 }
 
-func (x *Record) GetY() int32 {
+func (x *RollupBucket) GetMean() float64 {
 	if x != nil {
-		return x.Y
+		return x.Mean
 	}
 	return 0
 }
 
-func (x *Record) GetT() int32 {
+func (x *RollupBucket) GetM2() float64 {
 	if x != nil {
-		return x.T
+		return x.M2
 	}
 	return 0
 }
 
-func (x *Record) GetDataset() string {
+func (x *RollupBucket) GetFirst() float64 {
 	if x != nil {
-		return x.Dataset
+		return x.First
 	}
-	return ""
+	return 0
 }
 
-func (x *Record) GetValue() float64 {
+func (x *RollupBucket) GetLast() float64 {
 	if x != nil {
-		return x.Value
+		return x.Last
 	}
 	return 0
 }
 
-func (x *Record) GetName() string {
+func (x *RollupBucket) GetFirstWhen() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Name
+		return x.FirstWhen
 	}
-	return ""
+	return nil
 }
 
-type Metadata struct {
+func (x *RollupBucket) GetLastWhen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastWhen
+	}
+	return nil
+}
+
+type SchemaStats struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// When is a pointer for when a Record is for; what that specifically
-	// means (when the value was captured, the start of capture, when the
-	// data was calculated, whatever) for a given dataset is up to the
-	// client
-	When *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=when,proto3" json:"when,omitempty"`
-	// Labels are arbitrary key/values; they are not indexed and so can
-	// be as unique or wide ranging or as wacky as you want... go nuts
-	Labels map[string]string `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	// Indices are used to provide filtering and so come slighly more
-	// expensively than labels.
-	//
-	// Beware the wrath of the cardinality gods
-	Indices map[string]string `protobuf:"bytes,3,rep,name=indices,proto3" json:"indices,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Schema      *Schema  `protobuf:"bytes,1,opt,name=schema,proto3" json:"schema,omitempty"`
+	Records     uint32   `protobuf:"varint,2,opt,name=records,proto3" json:"records,omitempty"`
+	TotalSize   uint64   `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	AverageSize uint64   `protobuf:"varint,4,opt,name=average_size,json=averageSize,proto3" json:"average_size,omitempty"`
+	Fields      []string `protobuf:"bytes,5,rep,name=fields,proto3" json:"fields,omitempty"`
 }
 
-func (x *Metadata) Reset() {
-	*x = Metadata{}
+func (x *SchemaStats) Reset() {
+	*x = SchemaStats{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_server_proto_msgTypes[9]
+		mi := &file_server_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Metadata) String() string {
+func (x *SchemaStats) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Metadata) ProtoMessage() {}
+func (*SchemaStats) ProtoMessage() {}
 
-func (x *Metadata) ProtoReflect() protoreflect.Message {
-	mi := &file_server_proto_msgTypes[9]
+func (x *SchemaStats) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1021,63 +1374,134 @@ func (x *Metadata) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Metadata.ProtoReflect.Descriptor instead.
-func (*Metadata) Descriptor() ([]byte, []int) {
-	return file_server_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use SchemaStats.ProtoReflect.Descriptor instead.
+func (*SchemaStats) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *Metadata) GetWhen() *timestamppb.Timestamp {
+func (x *SchemaStats) GetSchema() *Schema {
 	if x != nil {
-		return x.When
+		return x.Schema
 	}
 	return nil
 }
 
-func (x *Metadata) GetLabels() map[string]string {
+func (x *SchemaStats) GetRecords() uint32 {
 	if x != nil {
-		return x.Labels
+		return x.Records
 	}
-	return nil
+	return 0
 }
 
-func (x *Metadata) GetIndices() map[string]string {
+func (x *SchemaStats) GetTotalSize() uint64 {
 	if x != nil {
-		return x.Indices
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *SchemaStats) GetAverageSize() uint64 {
+	if x != nil {
+		return x.AverageSize
+	}
+	return 0
+}
+
+func (x *SchemaStats) GetFields() []string {
+	if x != nil {
+		return x.Fields
 	}
 	return nil
 }
 
-// Version holds contains data pertaining to the version
-// of xyt which is running
-type VersionMessage struct {
+type Query struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Ref       string `protobuf:"bytes,1,opt,name=ref,proto3" json:"ref,omitempty"`
-	BuildUser string `protobuf:"bytes,2,opt,name=build_user,json=buildUser,proto3" json:"build_user,omitempty"`
-	// We store this as a string, rather than a datetime, because
-	// we never need to do anything clever with it, beyond showing it
-	BuiltOn string `protobuf:"bytes,3,opt,name=built_on,json=builtOn,proto3" json:"built_on,omitempty"`
+	Dataset string `protobuf:"bytes,1,opt,name=dataset,proto3" json:"dataset,omitempty"`
+	// Types that are assignable to X:
+	//
+	//	*Query_XAll
+	//	*Query_XValue
+	//	*Query_XRange
+	X isQuery_X `protobuf_oneof:"x"`
+	// Types that are assignable to Y:
+	//
+	//	*Query_YAll
+	//	*Query_YValue
+	//	*Query_YRange
+	Y isQuery_Y `protobuf_oneof:"y"`
+	// t_range wraps around 0/360 where start is greater than end, so
+	// a range of 350 to 10 covers 350 through to 359, and 0 through to 9
+	//
+	// Types that are assignable to T:
+	//
+	//	*Query_TAll
+	//	*Query_TValue
+	//	*Query_TRange
+	//	*Query_THeading
+	T isQuery_T `protobuf_oneof:"t"`
+	// Types that are assignable to Time:
+	//
+	//	*Query_TimeAll
+	//	*Query_TimeLatest
+	//	*Query_TimeRange
+	Time isQuery_Time `protobuf_oneof:"time"`
+	// index_key and index_value, when both set, limit results to records
+	// with a matching entry in Metadata.indices
+	IndexKey   string `protobuf:"bytes,11,opt,name=index_key,json=indexKey,proto3" json:"index_key,omitempty"`
+	IndexValue string `protobuf:"bytes,12,opt,name=index_value,json=indexValue,proto3" json:"index_value,omitempty"`
+	// names, when set, limits results to records with one of
+	// these names
+	Names []string `protobuf:"bytes,16,rep,name=names,proto3" json:"names,omitempty"`
+	// within, when set, limits results to locations within a circle, on
+	// top of any X and Y limits. Results are ordered by distance from the
+	// circle's centre, nearest first
+	Within *Circle `protobuf:"bytes,17,opt,name=within,proto3" json:"within,omitempty"`
+	// nearest, when set, limits results to those from the closest locations
+	// to a point holding matching records, ordered by distance, nearest first.
+	//
+	// within and nearest can't be used together
+	Nearest *Nearest `protobuf:"bytes,18,opt,name=nearest,proto3" json:"nearest,omitempty"`
+	// polygons, when set, limits results to locations within at least one
+	// of these polygons, on top of any other limits
+	Polygons []*Polygon `protobuf:"bytes,19,rep,name=polygons,proto3" json:"polygons,omitempty"`
+	// zones, when set, limits results to locations within at least one
+	// of these zones, as per polygons; each must exist on the dataset
+	Zones []string `protobuf:"bytes,20,rep,name=zones,proto3" json:"zones,omitempty"`
+	// world_polygons are polygons in world coordinates, which work as
+	// per polygons, but match cells whose centres fall within them. They
+	// require the dataset to have a Transform
+	WorldPolygons []*Polygon `protobuf:"bytes,22,rep,name=world_polygons,json=worldPolygons,proto3" json:"world_polygons,omitempty"`
+	// z limits results by Z, where the dataset has a Z axis; where
+	// unset, every Z matches
+	//
+	// Types that are assignable to Z:
+	//
+	//	*Query_ZAll
+	//	*Query_ZValue
+	//	*Query_ZRange
+	Z isQuery_Z `protobuf_oneof:"z"`
 }
 
-func (x *VersionMessage) Reset() {
-	*x = VersionMessage{}
+func (x *Query) Reset() {
+	*x = Query{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_server_proto_msgTypes[10]
+		mi := &file_server_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *VersionMessage) String() string {
+func (x *Query) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*VersionMessage) ProtoMessage() {}
+func (*Query) ProtoMessage() {}
 
-func (x *VersionMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_server_proto_msgTypes[10]
+func (x *Query) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1088,53 +1512,2581 @@ func (x *VersionMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use VersionMessage.ProtoReflect.Descriptor instead.
-func (*VersionMessage) Descriptor() ([]byte, []int) {
-	return file_server_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use Query.ProtoReflect.Descriptor instead.
+func (*Query) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *VersionMessage) GetRef() string {
+func (x *Query) GetDataset() string {
 	if x != nil {
-		return x.Ref
+		return x.Dataset
 	}
 	return ""
 }
 
-func (x *VersionMessage) GetBuildUser() string {
-	if x != nil {
-		return x.BuildUser
+func (m *Query) GetX() isQuery_X {
+	if m != nil {
+		return m.X
 	}
-	return ""
+	return nil
 }
 
-func (x *VersionMessage) GetBuiltOn() string {
-	if x != nil {
-		return x.BuiltOn
+func (x *Query) GetXAll() bool {
+	if x, ok := x.GetX().(*Query_XAll); ok {
+		return x.XAll
 	}
-	return ""
+	return false
 }
 
-var File_server_proto protoreflect.FileDescriptor
+func (x *Query) GetXValue() int32 {
+	if x, ok := x.GetX().(*Query_XValue); ok {
+		return x.XValue
+	}
+	return 0
+}
 
-var file_server_proto_rawDesc = []byte{
-	0x0a, 0x0c, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06,
-	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf4, 0x01, 0x0a, 0x0c, 0x53, 0x74, 0x61, 0x74, 0x73, 0x4d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x20, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x48, 0x6f, 0x73,
-	0x74, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x30, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
-	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65,
-	0x72, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3e, 0x0a, 0x08, 0x64, 0x61, 0x74,
-	0x61, 0x73, 0x65, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x73, 0x65,
-	0x72, 0x76, 0x65, 0x72, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
-	0x08, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x73, 0x1a, 0x50, 0x0a, 0x0d, 0x44, 0x61, 0x74,
-	0x61, 0x73, 0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
-	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x29, 0x0a, 0x05,
+func (x *Query) GetXRange() *QueryRange {
+	if x, ok := x.GetX().(*Query_XRange); ok {
+		return x.XRange
+	}
+	return nil
+}
+
+func (m *Query) GetY() isQuery_Y {
+	if m != nil {
+		return m.Y
+	}
+	return nil
+}
+
+func (x *Query) GetYAll() bool {
+	if x, ok := x.GetY().(*Query_YAll); ok {
+		return x.YAll
+	}
+	return false
+}
+
+func (x *Query) GetYValue() int32 {
+	if x, ok := x.GetY().(*Query_YValue); ok {
+		return x.YValue
+	}
+	return 0
+}
+
+func (x *Query) GetYRange() *QueryRange {
+	if x, ok := x.GetY().(*Query_YRange); ok {
+		return x.YRange
+	}
+	return nil
+}
+
+func (m *Query) GetT() isQuery_T {
+	if m != nil {
+		return m.T
+	}
+	return nil
+}
+
+func (x *Query) GetTAll() bool {
+	if x, ok := x.GetT().(*Query_TAll); ok {
+		return x.TAll
+	}
+	return false
+}
+
+func (x *Query) GetTValue() int32 {
+	if x, ok := x.GetT().(*Query_TValue); ok {
+		return x.TValue
+	}
+	return 0
+}
+
+func (x *Query) GetTRange() *QueryRange {
+	if x, ok := x.GetT().(*Query_TRange); ok {
+		return x.TRange
+	}
+	return nil
+}
+
+func (x *Query) GetTHeading() *Heading {
+	if x, ok := x.GetT().(*Query_THeading); ok {
+		return x.THeading
+	}
+	return nil
+}
+
+func (m *Query) GetTime() isQuery_Time {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (x *Query) GetTimeAll() bool {
+	if x, ok := x.GetTime().(*Query_TimeAll); ok {
+		return x.TimeAll
+	}
+	return false
+}
+
+func (x *Query) GetTimeLatest() bool {
+	if x, ok := x.GetTime().(*Query_TimeLatest); ok {
+		return x.TimeLatest
+	}
+	return false
+}
+
+func (x *Query) GetTimeRange() *TimeRange {
+	if x, ok := x.GetTime().(*Query_TimeRange); ok {
+		return x.TimeRange
+	}
+	return nil
+}
+
+func (x *Query) GetIndexKey() string {
+	if x != nil {
+		return x.IndexKey
+	}
+	return ""
+}
+
+func (x *Query) GetIndexValue() string {
+	if x != nil {
+		return x.IndexValue
+	}
+	return ""
+}
+
+func (x *Query) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *Query) GetWithin() *Circle {
+	if x != nil {
+		return x.Within
+	}
+	return nil
+}
+
+func (x *Query) GetNearest() *Nearest {
+	if x != nil {
+		return x.Nearest
+	}
+	return nil
+}
+
+func (x *Query) GetPolygons() []*Polygon {
+	if x != nil {
+		return x.Polygons
+	}
+	return nil
+}
+
+func (x *Query) GetZones() []string {
+	if x != nil {
+		return x.Zones
+	}
+	return nil
+}
+
+func (x *Query) GetWorldPolygons() []*Polygon {
+	if x != nil {
+		return x.WorldPolygons
+	}
+	return nil
+}
+
+func (m *Query) GetZ() isQuery_Z {
+	if m != nil {
+		return m.Z
+	}
+	return nil
+}
+
+func (x *Query) GetZAll() bool {
+	if x, ok := x.GetZ().(*Query_ZAll); ok {
+		return x.ZAll
+	}
+	return false
+}
+
+func (x *Query) GetZValue() int32 {
+	if x, ok := x.GetZ().(*Query_ZValue); ok {
+		return x.ZValue
+	}
+	return 0
+}
+
+func (x *Query) GetZRange() *QueryRange {
+	if x, ok := x.GetZ().(*Query_ZRange); ok {
+		return x.ZRange
+	}
+	return nil
+}
+
+type isQuery_X interface {
+	isQuery_X()
+}
+
+type Query_XAll struct {
+	XAll bool `protobuf:"varint,13,opt,name=x_all,json=xAll,proto3,oneof"`
+}
+
+type Query_XValue struct {
+	XValue int32 `protobuf:"zigzag32,2,opt,name=x_value,json=xValue,proto3,oneof"`
+}
+
+type Query_XRange struct {
+	XRange *QueryRange `protobuf:"bytes,3,opt,name=x_range,json=xRange,proto3,oneof"`
+}
+
+func (*Query_XAll) isQuery_X() {}
+
+func (*Query_XValue) isQuery_X() {}
+
+func (*Query_XRange) isQuery_X() {}
+
+type isQuery_Y interface {
+	isQuery_Y()
+}
+
+type Query_YAll struct {
+	YAll bool `protobuf:"varint,14,opt,name=y_all,json=yAll,proto3,oneof"`
+}
+
+type Query_YValue struct {
+	YValue int32 `protobuf:"zigzag32,4,opt,name=y_value,json=yValue,proto3,oneof"`
+}
+
+type Query_YRange struct {
+	YRange *QueryRange `protobuf:"bytes,5,opt,name=y_range,json=yRange,proto3,oneof"`
+}
+
+func (*Query_YAll) isQuery_Y() {}
+
+func (*Query_YValue) isQuery_Y() {}
+
+func (*Query_YRange) isQuery_Y() {}
+
+type isQuery_T interface {
+	isQuery_T()
+}
+
+type Query_TAll struct {
+	TAll bool `protobuf:"varint,15,opt,name=t_all,json=tAll,proto3,oneof"`
+}
+
+type Query_TValue struct {
+	TValue int32 `protobuf:"zigzag32,6,opt,name=t_value,json=tValue,proto3,oneof"`
+}
+
+type Query_TRange struct {
+	TRange *QueryRange `protobuf:"bytes,7,opt,name=t_range,json=tRange,proto3,oneof"`
+}
+
+type Query_THeading struct {
+	THeading *Heading `protobuf:"bytes,21,opt,name=t_heading,json=tHeading,proto3,oneof"`
+}
+
+func (*Query_TAll) isQuery_T() {}
+
+func (*Query_TValue) isQuery_T() {}
+
+func (*Query_TRange) isQuery_T() {}
+
+func (*Query_THeading) isQuery_T() {}
+
+type isQuery_Time interface {
+	isQuery_Time()
+}
+
+type Query_TimeAll struct {
+	TimeAll bool `protobuf:"varint,8,opt,name=time_all,json=timeAll,proto3,oneof"`
+}
+
+type Query_TimeLatest struct {
+	TimeLatest bool `protobuf:"varint,9,opt,name=time_latest,json=timeLatest,proto3,oneof"`
+}
+
+type Query_TimeRange struct {
+	TimeRange *TimeRange `protobuf:"bytes,10,opt,name=time_range,json=timeRange,proto3,oneof"`
+}
+
+func (*Query_TimeAll) isQuery_Time() {}
+
+func (*Query_TimeLatest) isQuery_Time() {}
+
+func (*Query_TimeRange) isQuery_Time() {}
+
+type isQuery_Z interface {
+	isQuery_Z()
+}
+
+type Query_ZAll struct {
+	ZAll bool `protobuf:"varint,23,opt,name=z_all,json=zAll,proto3,oneof"`
+}
+
+type Query_ZValue struct {
+	ZValue int32 `protobuf:"zigzag32,24,opt,name=z_value,json=zValue,proto3,oneof"`
+}
+
+type Query_ZRange struct {
+	ZRange *QueryRange `protobuf:"bytes,25,opt,name=z_range,json=zRange,proto3,oneof"`
+}
+
+func (*Query_ZAll) isQuery_Z() {}
+
+func (*Query_ZValue) isQuery_Z() {}
+
+func (*Query_ZRange) isQuery_Z() {}
+
+// A Polygon describes an arbitrarily shaped region of locations, such as
+// a warehouse zone. Locations on the polygon's edges are within it
+type Polygon struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Outline *Ring `protobuf:"bytes,1,opt,name=outline,proto3" json:"outline,omitempty"`
+	// holes are cut out of the polygon; locations on
+	// the edge of a hole are still within the polygon
+	Holes []*Ring `protobuf:"bytes,2,rep,name=holes,proto3" json:"holes,omitempty"`
+}
+
+func (x *Polygon) Reset() {
+	*x = Polygon{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Polygon) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Polygon) ProtoMessage() {}
+
+func (x *Polygon) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Polygon.ProtoReflect.Descriptor instead.
+func (*Polygon) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Polygon) GetOutline() *Ring {
+	if x != nil {
+		return x.Outline
+	}
+	return nil
+}
+
+func (x *Polygon) GetHoles() []*Ring {
+	if x != nil {
+		return x.Holes
+	}
+	return nil
+}
+
+// A Ring is a closed loop of vertices; the last vertex joins back up
+// to the first, and a ring needs at least three vertices
+type Ring struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vertices []*Vertex `protobuf:"bytes,1,rep,name=vertices,proto3" json:"vertices,omitempty"`
+}
+
+func (x *Ring) Reset() {
+	*x = Ring{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Ring) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ring) ProtoMessage() {}
+
+func (x *Ring) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ring.ProtoReflect.Descriptor instead.
+func (*Ring) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Ring) GetVertices() []*Vertex {
+	if x != nil {
+		return x.Vertices
+	}
+	return nil
+}
+
+type Vertex struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	X float64 `protobuf:"fixed64,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y float64 `protobuf:"fixed64,2,opt,name=y,proto3" json:"y,omitempty"`
+}
+
+func (x *Vertex) Reset() {
+	*x = Vertex{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Vertex) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vertex) ProtoMessage() {}
+
+func (x *Vertex) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vertex.ProtoReflect.Descriptor instead.
+func (*Vertex) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Vertex) GetX() float64 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Vertex) GetY() float64 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+// A Circle describes a circular region of locations
+type Circle struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	X int32 `protobuf:"zigzag32,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y int32 `protobuf:"zigzag32,2,opt,name=y,proto3" json:"y,omitempty"`
+	// radius is measured in locations, and must not be negative; locations
+	// exactly radius away from the centre are within the circle
+	Radius float64 `protobuf:"fixed64,3,opt,name=radius,proto3" json:"radius,omitempty"`
+}
+
+func (x *Circle) Reset() {
+	*x = Circle{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Circle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Circle) ProtoMessage() {}
+
+func (x *Circle) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Circle.ProtoReflect.Descriptor instead.
+func (*Circle) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Circle) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Circle) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *Circle) GetRadius() float64 {
+	if x != nil {
+		return x.Radius
+	}
+	return 0
+}
+
+// Nearest describes a k-nearest-neighbour search from a single location
+type Nearest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	X int32 `protobuf:"zigzag32,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y int32 `protobuf:"zigzag32,2,opt,name=y,proto3" json:"y,omitempty"`
+	// k is the number of locations to return records from, and
+	// must be greater than zero
+	K uint32 `protobuf:"varint,3,opt,name=k,proto3" json:"k,omitempty"`
+	// max_distance, when set, stops the search looking any further
+	// than this many locations away
+	MaxDistance float64 `protobuf:"fixed64,4,opt,name=max_distance,json=maxDistance,proto3" json:"max_distance,omitempty"`
+}
+
+func (x *Nearest) Reset() {
+	*x = Nearest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Nearest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Nearest) ProtoMessage() {}
+
+func (x *Nearest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Nearest.ProtoReflect.Descriptor instead.
+func (*Nearest) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Nearest) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Nearest) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *Nearest) GetK() uint32 {
+	if x != nil {
+		return x.K
+	}
+	return 0
+}
+
+func (x *Nearest) GetMaxDistance() float64 {
+	if x != nil {
+		return x.MaxDistance
+	}
+	return 0
+}
+
+type SubscribeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Query *Query `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// replay sends every record already matching query before
+	// any newly inserted records
+	Replay bool `protobuf:"varint,2,opt,name=replay,proto3" json:"replay,omitempty"`
+	// buffer_size is the number of records held for a subscriber
+	// which hasn't yet received them; where unset, a default is used
+	BufferSize uint32               `protobuf:"varint,3,opt,name=buffer_size,json=bufferSize,proto3" json:"buffer_size,omitempty"`
+	SlowPolicy SlowSubscriberPolicy `protobuf:"varint,4,opt,name=slow_policy,json=slowPolicy,proto3,enum=server.SlowSubscriberPolicy" json:"slow_policy,omitempty"`
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SubscribeRequest) GetQuery() *Query {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *SubscribeRequest) GetReplay() bool {
+	if x != nil {
+		return x.Replay
+	}
+	return false
+}
+
+func (x *SubscribeRequest) GetBufferSize() uint32 {
+	if x != nil {
+		return x.BufferSize
+	}
+	return 0
+}
+
+func (x *SubscribeRequest) GetSlowPolicy() SlowSubscriberPolicy {
+	if x != nil {
+		return x.SlowPolicy
+	}
+	return SlowSubscriberPolicy_DropRecords
+}
+
+type AggregateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Query        *Query        `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Aggregations []Aggregation `protobuf:"varint,2,rep,packed,name=aggregations,proto3,enum=server.Aggregation" json:"aggregations,omitempty"`
+	// by_name returns a separate set of results per record Name,
+	// rather than a single set of results across every record
+	ByName bool `protobuf:"varint,3,opt,name=by_name,json=byName,proto3" json:"by_name,omitempty"`
+}
+
+func (x *AggregateRequest) Reset() {
+	*x = AggregateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AggregateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AggregateRequest) ProtoMessage() {}
+
+func (x *AggregateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AggregateRequest.ProtoReflect.Descriptor instead.
+func (*AggregateRequest) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AggregateRequest) GetQuery() *Query {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *AggregateRequest) GetAggregations() []Aggregation {
+	if x != nil {
+		return x.Aggregations
+	}
+	return nil
+}
+
+func (x *AggregateRequest) GetByName() bool {
+	if x != nil {
+		return x.ByName
+	}
+	return false
+}
+
+type AggregateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results []*AggregateResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *AggregateResponse) Reset() {
+	*x = AggregateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AggregateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AggregateResponse) ProtoMessage() {}
+
+func (x *AggregateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AggregateResponse.ProtoReflect.Descriptor instead.
+func (*AggregateResponse) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AggregateResponse) GetResults() []*AggregateResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type AggregateResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// name is the record Name these results are for, and is empty
+	// when results weren't requested by name
+	Name   string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Count  uint64            `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Values []*AggregateValue `protobuf:"bytes,3,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (x *AggregateResult) Reset() {
+	*x = AggregateResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AggregateResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AggregateResult) ProtoMessage() {}
+
+func (x *AggregateResult) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AggregateResult.ProtoReflect.Descriptor instead.
+func (*AggregateResult) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AggregateResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AggregateResult) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *AggregateResult) GetValues() []*AggregateValue {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type AggregateValue struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Aggregation Aggregation `protobuf:"varint,1,opt,name=aggregation,proto3,enum=server.Aggregation" json:"aggregation,omitempty"`
+	Value       float64     `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *AggregateValue) Reset() {
+	*x = AggregateValue{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AggregateValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AggregateValue) ProtoMessage() {}
+
+func (x *AggregateValue) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AggregateValue.ProtoReflect.Descriptor instead.
+func (*AggregateValue) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *AggregateValue) GetAggregation() Aggregation {
+	if x != nil {
+		return x.Aggregation
+	}
+	return Aggregation_Count
+}
+
+func (x *AggregateValue) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type HeatmapRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Dataset string `protobuf:"bytes,1,opt,name=dataset,proto3" json:"dataset,omitempty"`
+	Name    string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// time_range limits the records considered; when unset, every
+	// record is considered
+	TimeRange   *TimeRange  `protobuf:"bytes,3,opt,name=time_range,json=timeRange,proto3" json:"time_range,omitempty"`
+	Aggregation Aggregation `protobuf:"varint,4,opt,name=aggregation,proto3,enum=server.Aggregation" json:"aggregation,omitempty"`
+	// downsample groups downsample x downsample cells into each value
+	// of the heatmap; both 0 and 1 return a value per cell
+	Downsample uint32 `protobuf:"varint,5,opt,name=downsample,proto3" json:"downsample,omitempty"`
+}
+
+func (x *HeatmapRequest) Reset() {
+	*x = HeatmapRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HeatmapRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeatmapRequest) ProtoMessage() {}
+
+func (x *HeatmapRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeatmapRequest.ProtoReflect.Descriptor instead.
+func (*HeatmapRequest) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *HeatmapRequest) GetDataset() string {
+	if x != nil {
+		return x.Dataset
+	}
+	return ""
+}
+
+func (x *HeatmapRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *HeatmapRequest) GetTimeRange() *TimeRange {
+	if x != nil {
+		return x.TimeRange
+	}
+	return nil
+}
+
+func (x *HeatmapRequest) GetAggregation() Aggregation {
+	if x != nil {
+		return x.Aggregation
+	}
+	return Aggregation_Count
+}
+
+func (x *HeatmapRequest) GetDownsample() uint32 {
+	if x != nil {
+		return x.Downsample
+	}
+	return 0
+}
+
+// A HeatmapResponse is a dense grid of aggregated values, where the
+// value for bucket (bx, by) is at values[by * width + bx], and covers
+// the cells from (x_min + bx * downsample, y_min + by * downsample)
+// to (x_min + (bx+1) * downsample, y_min + (by+1) * downsample)
+type HeatmapResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Width      uint32 `protobuf:"varint,1,opt,name=width,proto3" json:"width,omitempty"`
+	Height     uint32 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	XMin       int32  `protobuf:"zigzag32,3,opt,name=x_min,json=xMin,proto3" json:"x_min,omitempty"`
+	YMin       int32  `protobuf:"zigzag32,4,opt,name=y_min,json=yMin,proto3" json:"y_min,omitempty"`
+	Downsample uint32 `protobuf:"varint,5,opt,name=downsample,proto3" json:"downsample,omitempty"`
+	// values holds the aggregated value of each bucket; buckets with
+	// no records hold NaN, except for Count and Sum heatmaps, where
+	// they hold zero
+	Values []float64 `protobuf:"fixed64,6,rep,packed,name=values,proto3" json:"values,omitempty"`
+	// counts holds the number of records in each bucket
+	Counts []uint64 `protobuf:"varint,7,rep,packed,name=counts,proto3" json:"counts,omitempty"`
+}
+
+func (x *HeatmapResponse) Reset() {
+	*x = HeatmapResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HeatmapResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeatmapResponse) ProtoMessage() {}
+
+func (x *HeatmapResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeatmapResponse.ProtoReflect.Descriptor instead.
+func (*HeatmapResponse) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *HeatmapResponse) GetWidth() uint32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *HeatmapResponse) GetHeight() uint32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *HeatmapResponse) GetXMin() int32 {
+	if x != nil {
+		return x.XMin
+	}
+	return 0
+}
+
+func (x *HeatmapResponse) GetYMin() int32 {
+	if x != nil {
+		return x.YMin
+	}
+	return 0
+}
+
+func (x *HeatmapResponse) GetDownsample() uint32 {
+	if x != nil {
+		return x.Downsample
+	}
+	return 0
+}
+
+func (x *HeatmapResponse) GetValues() []float64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *HeatmapResponse) GetCounts() []uint64 {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+type TimeSeriesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// query selects the records to aggregate; where the query has a
+	// time_range, the series covers that range, otherwise it covers
+	// the earliest to the latest matching record
+	Query       *Query               `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Aggregation Aggregation          `protobuf:"varint,2,opt,name=aggregation,proto3,enum=server.Aggregation" json:"aggregation,omitempty"`
+	BucketWidth *durationpb.Duration `protobuf:"bytes,3,opt,name=bucket_width,json=bucketWidth,proto3" json:"bucket_width,omitempty"`
+	// alignment is a point in time that bucket boundaries line up
+	// with; when unset, buckets are aligned to the unix epoch
+	Alignment *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=alignment,proto3" json:"alignment,omitempty"`
+	Fill      FillPolicy             `protobuf:"varint,5,opt,name=fill,proto3,enum=server.FillPolicy" json:"fill,omitempty"`
+}
+
+func (x *TimeSeriesRequest) Reset() {
+	*x = TimeSeriesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TimeSeriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeSeriesRequest) ProtoMessage() {}
+
+func (x *TimeSeriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeSeriesRequest.ProtoReflect.Descriptor instead.
+func (*TimeSeriesRequest) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *TimeSeriesRequest) GetQuery() *Query {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *TimeSeriesRequest) GetAggregation() Aggregation {
+	if x != nil {
+		return x.Aggregation
+	}
+	return Aggregation_Count
+}
+
+func (x *TimeSeriesRequest) GetBucketWidth() *durationpb.Duration {
+	if x != nil {
+		return x.BucketWidth
+	}
+	return nil
+}
+
+func (x *TimeSeriesRequest) GetAlignment() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Alignment
+	}
+	return nil
+}
+
+func (x *TimeSeriesRequest) GetFill() FillPolicy {
+	if x != nil {
+		return x.Fill
+	}
+	return FillPolicy_FillNone
+}
+
+type TimeSeriesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Series []*Series `protobuf:"bytes,1,rep,name=series,proto3" json:"series,omitempty"`
+}
+
+func (x *TimeSeriesResponse) Reset() {
+	*x = TimeSeriesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TimeSeriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeSeriesResponse) ProtoMessage() {}
+
+func (x *TimeSeriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeSeriesResponse.ProtoReflect.Descriptor instead.
+func (*TimeSeriesResponse) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *TimeSeriesResponse) GetSeries() []*Series {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+// A Series holds the bucketed values for a single record Name
+type Series struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name   string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Points []*Point `protobuf:"bytes,2,rep,name=points,proto3" json:"points,omitempty"`
+}
+
+func (x *Series) Reset() {
+	*x = Series{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Series) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Series) ProtoMessage() {}
+
+func (x *Series) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Series.ProtoReflect.Descriptor instead.
+func (*Series) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Series) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Series) GetPoints() []*Point {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+type Point struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// start is the start of the bucket this point covers
+	Start *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	Value float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	Count uint64                 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	// null is true where the bucket is empty, and couldn't be
+	// filled; value should be ignored
+	Null bool `protobuf:"varint,4,opt,name=null,proto3" json:"null,omitempty"`
+}
+
+func (x *Point) Reset() {
+	*x = Point{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Point) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Point) ProtoMessage() {}
+
+func (x *Point) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Point.ProtoReflect.Descriptor instead.
+func (*Point) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Point) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Point) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Point) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *Point) GetNull() bool {
+	if x != nil {
+		return x.Null
+	}
+	return false
+}
+
+// A Heading matches every theta within tolerance degrees of
+// heading, either way, wrapping around 0/360 where necessary
+type Heading struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Heading   int32  `protobuf:"zigzag32,1,opt,name=heading,proto3" json:"heading,omitempty"`
+	Tolerance uint32 `protobuf:"varint,2,opt,name=tolerance,proto3" json:"tolerance,omitempty"`
+}
+
+func (x *Heading) Reset() {
+	*x = Heading{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Heading) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Heading) ProtoMessage() {}
+
+func (x *Heading) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Heading.ProtoReflect.Descriptor instead.
+func (*Heading) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *Heading) GetHeading() int32 {
+	if x != nil {
+		return x.Heading
+	}
+	return 0
+}
+
+func (x *Heading) GetTolerance() uint32 {
+	if x != nil {
+		return x.Tolerance
+	}
+	return 0
+}
+
+type QueryRange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Start int32 `protobuf:"zigzag32,1,opt,name=start,proto3" json:"start,omitempty"`
+	End   int32 `protobuf:"zigzag32,2,opt,name=end,proto3" json:"end,omitempty"`
+}
+
+func (x *QueryRange) Reset() {
+	*x = QueryRange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryRange) ProtoMessage() {}
+
+func (x *QueryRange) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryRange.ProtoReflect.Descriptor instead.
+func (*QueryRange) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *QueryRange) GetStart() int32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *QueryRange) GetEnd() int32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+type TimeRange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Start *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+}
+
+func (x *TimeRange) Reset() {
+	*x = TimeRange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TimeRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeRange) ProtoMessage() {}
+
+func (x *TimeRange) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeRange.ProtoReflect.Descriptor instead.
+func (*TimeRange) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *TimeRange) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *TimeRange) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+// A Record is a specific reading for a set of X,Y coordinates and
+// a theta representing aspect.
+//
+// Alongside this, a Record includes metadata values and a float representing
+// an actual value
+type Record struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta *Metadata `protobuf:"bytes,6,opt,name=meta,proto3" json:"meta,omitempty"`
+	X    int32     `protobuf:"zigzag32,1,opt,name=X,proto3" json:"X,omitempty"`
+	Y    int32     `protobuf:"zigzag32,2,opt,name=Y,proto3" json:"Y,omitempty"`
+	// T is a heading, in the dataset's theta buckets (which are degrees,
+	// unless the schema sets a ThetaResolution), from 0 up to, but not
+	// including, a full turn; a full turn is accepted, but is stored as 0
+	T int32 `protobuf:"zigzag32,3,opt,name=T,proto3" json:"T,omitempty"`
+	// theta, when set, is a heading in degrees, from 0 to 360, with as much
+	// precision as the sender has. It's binned into T as the record is
+	// inserted, replacing whatever T was sent
+	Theta *float64 `protobuf:"fixed64,8,opt,name=theta,proto3,oneof" json:"theta,omitempty"`
+	// world, when set, is this record's location in world coordinates,
+	// which the dataset's Transform quantises into X and Y on insert,
+	// replacing whatever X and Y were sent. Records inserted without it,
+	// into datasets with a Transform, have it set to their cell's centre
+	World *WorldPoint `protobuf:"bytes,9,opt,name=world,proto3" json:"world,omitempty"`
+	// geo, when set, is this record's latitude and longitude, which the
+	// dataset's Geo projects into world coordinates on insert, replacing
+	// whatever world location, X and Y were sent
+	Geo *GeoPoint `protobuf:"bytes,10,opt,name=geo,proto3" json:"geo,omitempty"`
+	// Z is this record's position on the dataset's Z axis, such as a
+	// floor number; it must be 0 for datasets without one
+	Z int32 `protobuf:"zigzag32,11,opt,name=Z,proto3" json:"Z,omitempty"`
+	// a Dataset is analogous to a database and is best thought of as
+	// a specific location to be mapped, alongside a specific purpose.
+	//
+	// # For a racing bike it might be the race course
+	//
+	// # For an autonomous robot it might be a particular warehouse
+	//
+	// Ultimately, it provides some context toward what a X, Y, T value
+	// actually means in practice
+	Dataset string `protobuf:"bytes,4,opt,name=dataset,proto3" json:"dataset,omitempty"`
+	// Value represents the actual value this record represents
+	Value float64 `protobuf:"fixed64,5,opt,name=value,proto3" json:"value,omitempty"`
+	// Name provides context for what a value is for
+	Name string `protobuf:"bytes,7,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *Record) Reset() {
+	*x = Record{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Record) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Record) ProtoMessage() {}
+
+func (x *Record) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Record.ProtoReflect.Descriptor instead.
+func (*Record) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *Record) GetMeta() *Metadata {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *Record) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Record) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *Record) GetT() int32 {
+	if x != nil {
+		return x.T
+	}
+	return 0
+}
+
+func (x *Record) GetTheta() float64 {
+	if x != nil && x.Theta != nil {
+		return *x.Theta
+	}
+	return 0
+}
+
+func (x *Record) GetWorld() *WorldPoint {
+	if x != nil {
+		return x.World
+	}
+	return nil
+}
+
+func (x *Record) GetGeo() *GeoPoint {
+	if x != nil {
+		return x.Geo
+	}
+	return nil
+}
+
+func (x *Record) GetZ() int32 {
+	if x != nil {
+		return x.Z
+	}
+	return 0
+}
+
+func (x *Record) GetDataset() string {
+	if x != nil {
+		return x.Dataset
+	}
+	return ""
+}
+
+func (x *Record) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Record) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type Metadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// When is a pointer for when a Record is for; what that specifically
+	// means (when the value was captured, the start of capture, when the
+	// data was calculated, whatever) for a given dataset is up to the
+	// client
+	When *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=when,proto3" json:"when,omitempty"`
+	// Labels are arbitrary key/values; they are not indexed and so can
+	// be as unique or wide ranging or as wacky as you want... go nuts
+	Labels map[string]string `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Indices are used to provide filtering and so come slighly more
+	// expensively than labels.
+	//
+	// Beware the wrath of the cardinality gods
+	Indices map[string]string `protobuf:"bytes,3,rep,name=indices,proto3" json:"indices,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *Metadata) Reset() {
+	*x = Metadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Metadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Metadata) ProtoMessage() {}
+
+func (x *Metadata) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Metadata.ProtoReflect.Descriptor instead.
+func (*Metadata) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *Metadata) GetWhen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.When
+	}
+	return nil
+}
+
+func (x *Metadata) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Metadata) GetIndices() map[string]string {
+	if x != nil {
+		return x.Indices
+	}
+	return nil
+}
+
+// TruncateRequest drops every record in a dataset from before a
+// given time
+type TruncateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Dataset string `protobuf:"bytes,1,opt,name=dataset,proto3" json:"dataset,omitempty"`
+	// before is the cutoff; records with a `When` value before this
+	// are dropped
+	Before *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=before,proto3" json:"before,omitempty"`
+}
+
+func (x *TruncateRequest) Reset() {
+	*x = TruncateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TruncateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TruncateRequest) ProtoMessage() {}
+
+func (x *TruncateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TruncateRequest.ProtoReflect.Descriptor instead.
+func (*TruncateRequest) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *TruncateRequest) GetDataset() string {
+	if x != nil {
+		return x.Dataset
+	}
+	return ""
+}
+
+func (x *TruncateRequest) GetBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+// TruncateResponse holds the number of records a truncation dropped
+type TruncateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Removed uint64 `protobuf:"varint,1,opt,name=removed,proto3" json:"removed,omitempty"`
+}
+
+func (x *TruncateResponse) Reset() {
+	*x = TruncateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TruncateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TruncateResponse) ProtoMessage() {}
+
+func (x *TruncateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TruncateResponse.ProtoReflect.Descriptor instead.
+func (*TruncateResponse) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *TruncateResponse) GetRemoved() uint64 {
+	if x != nil {
+		return x.Removed
+	}
+	return 0
+}
+
+// AlterSchemaRequest describes changes to a dataset's schema; only
+// the fields which are set are changed
+type AlterSchemaRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Dataset string `protobuf:"bytes,1,opt,name=dataset,proto3" json:"dataset,omitempty"`
+	// Bounds may only grow, so that every existing record still fits
+	XMin      *int32     `protobuf:"zigzag32,2,opt,name=x_min,json=xMin,proto3,oneof" json:"x_min,omitempty"`
+	XMax      *int32     `protobuf:"zigzag32,3,opt,name=x_max,json=xMax,proto3,oneof" json:"x_max,omitempty"`
+	YMin      *int32     `protobuf:"zigzag32,4,opt,name=y_min,json=yMin,proto3,oneof" json:"y_min,omitempty"`
+	YMax      *int32     `protobuf:"zigzag32,5,opt,name=y_max,json=yMax,proto3,oneof" json:"y_max,omitempty"`
+	ZMin      *int32     `protobuf:"zigzag32,6,opt,name=z_min,json=zMin,proto3,oneof" json:"z_min,omitempty"`
+	ZMax      *int32     `protobuf:"zigzag32,7,opt,name=z_max,json=zMax,proto3,oneof" json:"z_max,omitempty"`
+	Frequency *Frequency `protobuf:"varint,8,opt,name=frequency,proto3,enum=server.Frequency,oneof" json:"frequency,omitempty"`
+	// Turning sort_on_insert on sorts every location's existing records
+	SortOnInsert *bool `protobuf:"varint,9,opt,name=sort_on_insert,json=sortOnInsert,proto3,oneof" json:"sort_on_insert,omitempty"`
+	// retention, when set, replaces the dataset's Retention; a zero
+	// duration keeps records forever
+	Retention *durationpb.Duration `protobuf:"bytes,10,opt,name=retention,proto3" json:"retention,omitempty"`
+}
+
+func (x *AlterSchemaRequest) Reset() {
+	*x = AlterSchemaRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AlterSchemaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlterSchemaRequest) ProtoMessage() {}
+
+func (x *AlterSchemaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlterSchemaRequest.ProtoReflect.Descriptor instead.
+func (*AlterSchemaRequest) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *AlterSchemaRequest) GetDataset() string {
+	if x != nil {
+		return x.Dataset
+	}
+	return ""
+}
+
+func (x *AlterSchemaRequest) GetXMin() int32 {
+	if x != nil && x.XMin != nil {
+		return *x.XMin
+	}
+	return 0
+}
+
+func (x *AlterSchemaRequest) GetXMax() int32 {
+	if x != nil && x.XMax != nil {
+		return *x.XMax
+	}
+	return 0
+}
+
+func (x *AlterSchemaRequest) GetYMin() int32 {
+	if x != nil && x.YMin != nil {
+		return *x.YMin
+	}
+	return 0
+}
+
+func (x *AlterSchemaRequest) GetYMax() int32 {
+	if x != nil && x.YMax != nil {
+		return *x.YMax
+	}
+	return 0
+}
+
+func (x *AlterSchemaRequest) GetZMin() int32 {
+	if x != nil && x.ZMin != nil {
+		return *x.ZMin
+	}
+	return 0
+}
+
+func (x *AlterSchemaRequest) GetZMax() int32 {
+	if x != nil && x.ZMax != nil {
+		return *x.ZMax
+	}
+	return 0
+}
+
+func (x *AlterSchemaRequest) GetFrequency() Frequency {
+	if x != nil && x.Frequency != nil {
+		return *x.Frequency
+	}
+	return Frequency_F1Hz
+}
+
+func (x *AlterSchemaRequest) GetSortOnInsert() bool {
+	if x != nil && x.SortOnInsert != nil {
+		return *x.SortOnInsert
+	}
+	return false
+}
+
+func (x *AlterSchemaRequest) GetRetention() *durationpb.Duration {
+	if x != nil {
+		return x.Retention
+	}
+	return nil
+}
+
+type DeleteDatasetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Dataset string `protobuf:"bytes,1,opt,name=dataset,proto3" json:"dataset,omitempty"`
+}
+
+func (x *DeleteDatasetRequest) Reset() {
+	*x = DeleteDatasetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteDatasetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDatasetRequest) ProtoMessage() {}
+
+func (x *DeleteDatasetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDatasetRequest.ProtoReflect.Descriptor instead.
+func (*DeleteDatasetRequest) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *DeleteDatasetRequest) GetDataset() string {
+	if x != nil {
+		return x.Dataset
+	}
+	return ""
+}
+
+type DeleteRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// query selects the records to drop; nearest and
+	// latest record queries can't be used
+	Query *Query `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// dry_run counts the records query matches,
+	// without dropping anything
+	DryRun bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *DeleteRequest) GetQuery() *Query {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *DeleteRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type DeleteResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// removed is the number of records dropped or,
+	// for a dry run, which would have been
+	Removed uint64 `protobuf:"varint,1,opt,name=removed,proto3" json:"removed,omitempty"`
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *DeleteResponse) GetRemoved() uint64 {
+	if x != nil {
+		return x.Removed
+	}
+	return 0
+}
+
+type Zone struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Dataset string   `protobuf:"bytes,1,opt,name=dataset,proto3" json:"dataset,omitempty"`
+	Name    string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Polygon *Polygon `protobuf:"bytes,3,opt,name=polygon,proto3" json:"polygon,omitempty"`
+}
+
+func (x *Zone) Reset() {
+	*x = Zone{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Zone) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Zone) ProtoMessage() {}
+
+func (x *Zone) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Zone.ProtoReflect.Descriptor instead.
+func (*Zone) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *Zone) GetDataset() string {
+	if x != nil {
+		return x.Dataset
+	}
+	return ""
+}
+
+func (x *Zone) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Zone) GetPolygon() *Polygon {
+	if x != nil {
+		return x.Polygon
+	}
+	return nil
+}
+
+type ListZonesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Dataset string `protobuf:"bytes,1,opt,name=dataset,proto3" json:"dataset,omitempty"`
+}
+
+func (x *ListZonesRequest) Reset() {
+	*x = ListZonesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListZonesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListZonesRequest) ProtoMessage() {}
+
+func (x *ListZonesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListZonesRequest.ProtoReflect.Descriptor instead.
+func (*ListZonesRequest) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ListZonesRequest) GetDataset() string {
+	if x != nil {
+		return x.Dataset
+	}
+	return ""
+}
+
+type ListZonesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Zones []*Zone `protobuf:"bytes,1,rep,name=zones,proto3" json:"zones,omitempty"`
+}
+
+func (x *ListZonesResponse) Reset() {
+	*x = ListZonesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListZonesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListZonesResponse) ProtoMessage() {}
+
+func (x *ListZonesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListZonesResponse.ProtoReflect.Descriptor instead.
+func (*ListZonesResponse) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ListZonesResponse) GetZones() []*Zone {
+	if x != nil {
+		return x.Zones
+	}
+	return nil
+}
+
+type DeleteZoneRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Dataset string `protobuf:"bytes,1,opt,name=dataset,proto3" json:"dataset,omitempty"`
+	Name    string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *DeleteZoneRequest) Reset() {
+	*x = DeleteZoneRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteZoneRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteZoneRequest) ProtoMessage() {}
+
+func (x *DeleteZoneRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteZoneRequest.ProtoReflect.Descriptor instead.
+func (*DeleteZoneRequest) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *DeleteZoneRequest) GetDataset() string {
+	if x != nil {
+		return x.Dataset
+	}
+	return ""
+}
+
+func (x *DeleteZoneRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// ExportChunk is a piece of an export; concatenating
+// every chunk, in order, gives the whole export
+type ExportChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *ExportChunk) Reset() {
+	*x = ExportChunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportChunk) ProtoMessage() {}
+
+func (x *ExportChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportChunk.ProtoReflect.Descriptor instead.
+func (*ExportChunk) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ExportChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// SnapshotChunk is an opaque piece of a snapshot; chunks must be
+// passed to Restore in the same order Snapshot returned them
+type SnapshotChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *SnapshotChunk) Reset() {
+	*x = SnapshotChunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SnapshotChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotChunk) ProtoMessage() {}
+
+func (x *SnapshotChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotChunk.ProtoReflect.Descriptor instead.
+func (*SnapshotChunk) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *SnapshotChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// Version holds contains data pertaining to the version
+// of xyt which is running
+type VersionMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ref       string `protobuf:"bytes,1,opt,name=ref,proto3" json:"ref,omitempty"`
+	BuildUser string `protobuf:"bytes,2,opt,name=build_user,json=buildUser,proto3" json:"build_user,omitempty"`
+	// We store this as a string, rather than a datetime, because
+	// we never need to do anything clever with it, beyond showing it
+	BuiltOn string `protobuf:"bytes,3,opt,name=built_on,json=builtOn,proto3" json:"built_on,omitempty"`
+}
+
+func (x *VersionMessage) Reset() {
+	*x = VersionMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VersionMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionMessage) ProtoMessage() {}
+
+func (x *VersionMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_server_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionMessage.ProtoReflect.Descriptor instead.
+func (*VersionMessage) Descriptor() ([]byte, []int) {
+	return file_server_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *VersionMessage) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+func (x *VersionMessage) GetBuildUser() string {
+	if x != nil {
+		return x.BuildUser
+	}
+	return ""
+}
+
+func (x *VersionMessage) GetBuiltOn() string {
+	if x != nil {
+		return x.BuiltOn
+	}
+	return ""
+}
+
+var File_server_proto protoreflect.FileDescriptor
+
+var file_server_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf4, 0x01, 0x0a, 0x0c, 0x53, 0x74, 0x61, 0x74, 0x73, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x20, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x48, 0x6f, 0x73,
+	0x74, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x30, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3e, 0x0a, 0x08, 0x64, 0x61, 0x74,
+	0x61, 0x73, 0x65, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x08, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x73, 0x1a, 0x50, 0x0a, 0x0d, 0x44, 0x61, 0x74,
+	0x61, 0x73, 0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x29, 0x0a, 0x05,
 	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x73, 0x65,
 	0x72, 0x76, 0x65, 0x72, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x53, 0x74, 0x61, 0x74, 0x73,
 	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x8e, 0x01, 0x0a, 0x04,
@@ -1152,7 +4104,7 @@ var file_server_proto_rawDesc = []byte{
 	0x52, 0x0e, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73,
 	0x12, 0x20, 0x0a, 0x0b, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18,
 	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x42, 0x79, 0x74,
-	0x65, 0x73, 0x22, 0x81, 0x02, 0x0a, 0x06, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x18, 0x0a,
+	0x65, 0x73, 0x22, 0xf1, 0x05, 0x0a, 0x06, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x18, 0x0a,
 	0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
 	0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x12, 0x2f, 0x0a, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75,
 	0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x73, 0x65, 0x72,
@@ -1168,119 +4120,516 @@ var file_server_proto_rawDesc = []byte{
 	0x72, 0x74, 0x12, 0x32, 0x0a, 0x15, 0x6c, 0x61, 0x7a, 0x79, 0x5f, 0x69, 0x6e, 0x69, 0x74, 0x69,
 	0x61, 0x6c, 0x5f, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28,
 	0x08, 0x52, 0x13, 0x6c, 0x61, 0x7a, 0x79, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x41, 0x6c,
-	0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x22, 0xa9, 0x01, 0x0a, 0x0b, 0x53, 0x63, 0x68, 0x65, 0x6d,
-	0x61, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e,
-	0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x18,
-	0x0a, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
-	0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61,
-	0x6c, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x6f,
-	0x74, 0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x76, 0x65, 0x72, 0x61,
-	0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x61,
-	0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x69,
-	0x65, 0x6c, 0x64, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c,
-	0x64, 0x73, 0x22, 0x8d, 0x04, 0x0a, 0x05, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x18, 0x0a, 0x07,
-	0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64,
-	0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x12, 0x15, 0x0a, 0x05, 0x78, 0x5f, 0x61, 0x6c, 0x6c, 0x18,
-	0x0d, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x04, 0x78, 0x41, 0x6c, 0x6c, 0x12, 0x19, 0x0a,
-	0x07, 0x78, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x11, 0x48, 0x00,
-	0x52, 0x06, 0x78, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x2d, 0x0a, 0x07, 0x78, 0x5f, 0x72, 0x61,
-	0x6e, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x73, 0x65, 0x72, 0x76,
-	0x65, 0x72, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x48, 0x00, 0x52,
-	0x06, 0x78, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x15, 0x0a, 0x05, 0x79, 0x5f, 0x61, 0x6c, 0x6c,
-	0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x48, 0x01, 0x52, 0x04, 0x79, 0x41, 0x6c, 0x6c, 0x12, 0x19,
-	0x0a, 0x07, 0x79, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x11, 0x48,
-	0x01, 0x52, 0x06, 0x79, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x2d, 0x0a, 0x07, 0x79, 0x5f, 0x72,
-	0x61, 0x6e, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x73, 0x65, 0x72,
-	0x76, 0x65, 0x72, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x48, 0x01,
-	0x52, 0x06, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x15, 0x0a, 0x05, 0x74, 0x5f, 0x61, 0x6c,
-	0x6c, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x08, 0x48, 0x02, 0x52, 0x04, 0x74, 0x41, 0x6c, 0x6c, 0x12,
-	0x19, 0x0a, 0x07, 0x74, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x11,
-	0x48, 0x02, 0x52, 0x06, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x2d, 0x0a, 0x07, 0x74, 0x5f,
-	0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x73, 0x65,
+	0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x12, 0x32, 0x0a, 0x15, 0x6d, 0x61, 0x78, 0x5f, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x5f, 0x63, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x13, 0x6d, 0x61, 0x78, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x43,
+	0x61, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x37, 0x0a, 0x09, 0x72, 0x65,
+	0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x2c, 0x0a, 0x07, 0x72, 0x6f, 0x6c, 0x6c, 0x75, 0x70, 0x73, 0x18, 0x0b,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x52, 0x6f,
+	0x6c, 0x6c, 0x75, 0x70, 0x54, 0x69, 0x65, 0x72, 0x52, 0x07, 0x72, 0x6f, 0x6c, 0x6c, 0x75, 0x70,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x61, 0x72, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x61, 0x72, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x70, 0x61, 0x72, 0x73, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x73,
+	0x70, 0x61, 0x72, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x05, 0x7a, 0x6f, 0x6e, 0x65, 0x73, 0x18, 0x0e,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x53, 0x63,
+	0x68, 0x65, 0x6d, 0x61, 0x2e, 0x5a, 0x6f, 0x6e, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x05, 0x7a, 0x6f, 0x6e, 0x65, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x74, 0x68, 0x65, 0x74, 0x61, 0x5f,
+	0x72, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x0f, 0x74, 0x68, 0x65, 0x74, 0x61, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x2f, 0x0a, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x10,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x66, 0x6f, 0x72, 0x6d, 0x52, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x6f,
+	0x72, 0x6d, 0x12, 0x1d, 0x0a, 0x03, 0x67, 0x65, 0x6f, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0b, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x6f, 0x52, 0x03, 0x67, 0x65,
+	0x6f, 0x12, 0x13, 0x0a, 0x05, 0x7a, 0x5f, 0x6d, 0x69, 0x6e, 0x18, 0x12, 0x20, 0x01, 0x28, 0x11,
+	0x52, 0x04, 0x7a, 0x4d, 0x69, 0x6e, 0x12, 0x13, 0x0a, 0x05, 0x7a, 0x5f, 0x6d, 0x61, 0x78, 0x18,
+	0x13, 0x20, 0x01, 0x28, 0x11, 0x52, 0x04, 0x7a, 0x4d, 0x61, 0x78, 0x1a, 0x49, 0x0a, 0x0a, 0x5a,
+	0x6f, 0x6e, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x25, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x2e, 0x50, 0x6f, 0x6c, 0x79, 0x67, 0x6f, 0x6e, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xe6, 0x01, 0x0a, 0x03, 0x47, 0x65, 0x6f, 0x12, 0x32,
+	0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x12, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x6a,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x0f, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x5f, 0x6c, 0x61, 0x74,
+	0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x6f, 0x72, 0x69,
+	0x67, 0x69, 0x6e, 0x4c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x6f,
+	0x72, 0x69, 0x67, 0x69, 0x6e, 0x5f, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x4c, 0x6f, 0x6e,
+	0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x75, 0x74, 0x6d, 0x5f, 0x7a, 0x6f,
+	0x6e, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x75, 0x74, 0x6d, 0x5a, 0x6f, 0x6e,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x6f, 0x75, 0x74, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x05, 0x73, 0x6f, 0x75, 0x74, 0x68, 0x12, 0x26, 0x0a, 0x06, 0x62, 0x6f, 0x75, 0x6e, 0x64,
+	0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x2e, 0x47, 0x65, 0x6f, 0x42, 0x6f, 0x78, 0x52, 0x06, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x22,
+	0x98, 0x01, 0x0a, 0x06, 0x47, 0x65, 0x6f, 0x42, 0x6f, 0x78, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x69,
+	0x6e, 0x5f, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x0b, 0x6d, 0x69, 0x6e, 0x4c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x23, 0x0a,
+	0x0d, 0x6d, 0x69, 0x6e, 0x5f, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x6d, 0x69, 0x6e, 0x4c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75,
+	0x64, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75,
+	0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x4c, 0x61, 0x74,
+	0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x61, 0x78, 0x5f, 0x6c, 0x6f, 0x6e,
+	0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x6d, 0x61,
+	0x78, 0x4c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x22, 0x44, 0x0a, 0x08, 0x47, 0x65,
+	0x6f, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75,
+	0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75,
+	0x64, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65,
+	0x22, 0x7a, 0x0a, 0x09, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x1b, 0x0a,
+	0x09, 0x63, 0x65, 0x6c, 0x6c, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x08, 0x63, 0x65, 0x6c, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6f, 0x72,
+	0x69, 0x67, 0x69, 0x6e, 0x5f, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x6f, 0x72,
+	0x69, 0x67, 0x69, 0x6e, 0x58, 0x12, 0x19, 0x0a, 0x08, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x5f,
+	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x59,
+	0x12, 0x1a, 0x0a, 0x08, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x08, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x28, 0x0a, 0x0a,
+	0x57, 0x6f, 0x72, 0x6c, 0x64, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x01, 0x79, 0x22, 0x80, 0x01, 0x0a, 0x0a, 0x52, 0x6f, 0x6c, 0x6c, 0x75,
+	0x70, 0x54, 0x69, 0x65, 0x72, 0x12, 0x39, 0x0a, 0x0a, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x37, 0x0a, 0x09, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09,
+	0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xac, 0x03, 0x0a, 0x0c, 0x52, 0x6f,
+	0x6c, 0x6c, 0x75, 0x70, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x61,
+	0x74, 0x61, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x61, 0x74,
+	0x61, 0x73, 0x65, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x11, 0x52,
+	0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x11, 0x52, 0x01, 0x79,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x04, 0x74, 0x69, 0x65, 0x72, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x10, 0x0a, 0x03, 0x73, 0x75, 0x6d, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x73,
+	0x75, 0x6d, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x03, 0x6d, 0x69, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x65, 0x61, 0x6e, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x6d, 0x65, 0x61, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x6d, 0x32,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x01, 0x52, 0x02, 0x6d, 0x32, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69,
+	0x72, 0x73, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x66, 0x69, 0x72, 0x73, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x6c, 0x61, 0x73, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04,
+	0x6c, 0x61, 0x73, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x77, 0x68,
+	0x65, 0x6e, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x66, 0x69, 0x72, 0x73, 0x74, 0x57, 0x68, 0x65, 0x6e, 0x12,
+	0x37, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x77, 0x68, 0x65, 0x6e, 0x18, 0x10, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08,
+	0x6c, 0x61, 0x73, 0x74, 0x57, 0x68, 0x65, 0x6e, 0x22, 0xa9, 0x01, 0x0a, 0x0b, 0x53, 0x63, 0x68,
+	0x65, 0x6d, 0x61, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x06, 0x73, 0x63, 0x68, 0x65,
+	0x6d, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61,
+	0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x76, 0x65,
+	0x72, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0b, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x16, 0x0a, 0x06,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x66, 0x69,
+	0x65, 0x6c, 0x64, 0x73, 0x22, 0x87, 0x07, 0x0a, 0x05, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x18,
+	0x0a, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x12, 0x15, 0x0a, 0x05, 0x78, 0x5f, 0x61, 0x6c,
+	0x6c, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x04, 0x78, 0x41, 0x6c, 0x6c, 0x12,
+	0x19, 0x0a, 0x07, 0x78, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x11,
+	0x48, 0x00, 0x52, 0x06, 0x78, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x2d, 0x0a, 0x07, 0x78, 0x5f,
+	0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x73, 0x65,
 	0x72, 0x76, 0x65, 0x72, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x48,
-	0x02, 0x52, 0x06, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x1b, 0x0a, 0x08, 0x74, 0x69, 0x6d,
-	0x65, 0x5f, 0x61, 0x6c, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x48, 0x03, 0x52, 0x07, 0x74,
-	0x69, 0x6d, 0x65, 0x41, 0x6c, 0x6c, 0x12, 0x21, 0x0a, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x6c,
-	0x61, 0x74, 0x65, 0x73, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x48, 0x03, 0x52, 0x0a, 0x74,
-	0x69, 0x6d, 0x65, 0x4c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x0a, 0x74, 0x69, 0x6d,
-	0x65, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
-	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65,
-	0x48, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x1b, 0x0a,
-	0x09, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x08, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x4b, 0x65, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e,
-	0x64, 0x65, 0x78, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0a, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x03, 0x0a, 0x01, 0x78,
-	0x42, 0x03, 0x0a, 0x01, 0x79, 0x42, 0x03, 0x0a, 0x01, 0x74, 0x42, 0x06, 0x0a, 0x04, 0x74, 0x69,
-	0x6d, 0x65, 0x22, 0x34, 0x0a, 0x0a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65,
-	0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x11, 0x52,
-	0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x11, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x22, 0x6b, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65,
-	0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
-	0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x2c, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
-	0x52, 0x03, 0x65, 0x6e, 0x64, 0x22, 0x9c, 0x01, 0x0a, 0x06, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
-	0x12, 0x24, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10,
-	0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
-	0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12, 0x0c, 0x0a, 0x01, 0x58, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x11, 0x52, 0x01, 0x58, 0x12, 0x0c, 0x0a, 0x01, 0x59, 0x18, 0x02, 0x20, 0x01, 0x28, 0x11, 0x52,
-	0x01, 0x59, 0x12, 0x0c, 0x0a, 0x01, 0x54, 0x18, 0x03, 0x20, 0x01, 0x28, 0x11, 0x52, 0x01, 0x54,
-	0x12, 0x18, 0x0a, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
-	0x6c, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
-	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x22, 0xa0, 0x02, 0x0a, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
-	0x61, 0x12, 0x2e, 0x0a, 0x04, 0x77, 0x68, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x77, 0x68, 0x65,
-	0x6e, 0x12, 0x34, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x1c, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64,
-	0x61, 0x74, 0x61, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
-	0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x37, 0x0a, 0x07, 0x69, 0x6e, 0x64, 0x69, 0x63,
-	0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65,
-	0x72, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x49, 0x6e, 0x64, 0x69, 0x63,
-	0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x69, 0x6e, 0x64, 0x69, 0x63, 0x65, 0x73,
-	0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
-	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
-	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3a, 0x0a, 0x0c, 0x49,
-	0x6e, 0x64, 0x69, 0x63, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
-	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
-	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
-	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x5c, 0x0a, 0x0e, 0x56, 0x65, 0x72, 0x73, 0x69,
-	0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x65, 0x66,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72, 0x65, 0x66, 0x12, 0x1d, 0x0a, 0x0a, 0x62,
-	0x75, 0x69, 0x6c, 0x64, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x09, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x55, 0x73, 0x65, 0x72, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75,
-	0x69, 0x6c, 0x74, 0x5f, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75,
-	0x69, 0x6c, 0x74, 0x4f, 0x6e, 0x2a, 0x3c, 0x0a, 0x09, 0x46, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e,
-	0x63, 0x79, 0x12, 0x08, 0x0a, 0x04, 0x46, 0x31, 0x48, 0x7a, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06,
-	0x46, 0x31, 0x30, 0x30, 0x48, 0x7a, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x31, 0x30, 0x30,
-	0x30, 0x48, 0x7a, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x46, 0x31, 0x30, 0x30, 0x30, 0x30, 0x48,
-	0x7a, 0x10, 0x03, 0x32, 0x95, 0x02, 0x0a, 0x03, 0x58, 0x79, 0x74, 0x12, 0x37, 0x0a, 0x05, 0x53,
-	0x74, 0x61, 0x74, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x14, 0x2e, 0x73,
-	0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x22, 0x00, 0x12, 0x35, 0x0a, 0x09, 0x41, 0x64, 0x64, 0x53, 0x63, 0x68, 0x65, 0x6d,
-	0x61, 0x12, 0x0e, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x6d,
-	0x61, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x34, 0x0a, 0x06, 0x49,
-	0x6e, 0x73, 0x65, 0x72, 0x74, 0x12, 0x0e, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x52,
-	0x65, 0x63, 0x6f, 0x72, 0x64, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x28,
-	0x01, 0x12, 0x2b, 0x0a, 0x06, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x12, 0x0d, 0x2e, 0x73, 0x65,
-	0x72, 0x76, 0x65, 0x72, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x1a, 0x0e, 0x2e, 0x73, 0x65, 0x72,
-	0x76, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x22, 0x00, 0x30, 0x01, 0x12, 0x3b,
-	0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x00, 0x52, 0x06, 0x78, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x15, 0x0a, 0x05, 0x79, 0x5f, 0x61,
+	0x6c, 0x6c, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x48, 0x01, 0x52, 0x04, 0x79, 0x41, 0x6c, 0x6c,
+	0x12, 0x19, 0x0a, 0x07, 0x79, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x11, 0x48, 0x01, 0x52, 0x06, 0x79, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x2d, 0x0a, 0x07, 0x79,
+	0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65,
+	0x48, 0x01, 0x52, 0x06, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x15, 0x0a, 0x05, 0x74, 0x5f,
+	0x61, 0x6c, 0x6c, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x08, 0x48, 0x02, 0x52, 0x04, 0x74, 0x41, 0x6c,
+	0x6c, 0x12, 0x19, 0x0a, 0x07, 0x74, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x11, 0x48, 0x02, 0x52, 0x06, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x2d, 0x0a, 0x07,
+	0x74, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67,
+	0x65, 0x48, 0x02, 0x52, 0x06, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x2e, 0x0a, 0x09, 0x74,
+	0x5f, 0x68, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x48,
+	0x02, 0x52, 0x08, 0x74, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x1b, 0x0a, 0x08, 0x74,
+	0x69, 0x6d, 0x65, 0x5f, 0x61, 0x6c, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x48, 0x03, 0x52,
+	0x07, 0x74, 0x69, 0x6d, 0x65, 0x41, 0x6c, 0x6c, 0x12, 0x21, 0x0a, 0x0b, 0x74, 0x69, 0x6d, 0x65,
+	0x5f, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x48, 0x03, 0x52,
+	0x0a, 0x74, 0x69, 0x6d, 0x65, 0x4c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x0a, 0x74,
+	0x69, 0x6d, 0x65, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x61, 0x6e,
+	0x67, 0x65, 0x48, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12,
+	0x1b, 0x0a, 0x09, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x4b, 0x65, 0x79, 0x12, 0x1f, 0x0a, 0x0b,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x10, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x61,
+	0x6d, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x06, 0x77, 0x69, 0x74, 0x68, 0x69, 0x6e, 0x18, 0x11, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x43, 0x69, 0x72,
+	0x63, 0x6c, 0x65, 0x52, 0x06, 0x77, 0x69, 0x74, 0x68, 0x69, 0x6e, 0x12, 0x29, 0x0a, 0x07, 0x6e,
+	0x65, 0x61, 0x72, 0x65, 0x73, 0x74, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x4e, 0x65, 0x61, 0x72, 0x65, 0x73, 0x74, 0x52, 0x07, 0x6e,
+	0x65, 0x61, 0x72, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x08, 0x70, 0x6f, 0x6c, 0x79, 0x67, 0x6f,
+	0x6e, 0x73, 0x18, 0x13, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x2e, 0x50, 0x6f, 0x6c, 0x79, 0x67, 0x6f, 0x6e, 0x52, 0x08, 0x70, 0x6f, 0x6c, 0x79, 0x67,
+	0x6f, 0x6e, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x7a, 0x6f, 0x6e, 0x65, 0x73, 0x18, 0x14, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x05, 0x7a, 0x6f, 0x6e, 0x65, 0x73, 0x12, 0x36, 0x0a, 0x0e, 0x77, 0x6f, 0x72,
+	0x6c, 0x64, 0x5f, 0x70, 0x6f, 0x6c, 0x79, 0x67, 0x6f, 0x6e, 0x73, 0x18, 0x16, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0f, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x50, 0x6f, 0x6c, 0x79, 0x67,
+	0x6f, 0x6e, 0x52, 0x0d, 0x77, 0x6f, 0x72, 0x6c, 0x64, 0x50, 0x6f, 0x6c, 0x79, 0x67, 0x6f, 0x6e,
+	0x73, 0x12, 0x15, 0x0a, 0x05, 0x7a, 0x5f, 0x61, 0x6c, 0x6c, 0x18, 0x17, 0x20, 0x01, 0x28, 0x08,
+	0x48, 0x04, 0x52, 0x04, 0x7a, 0x41, 0x6c, 0x6c, 0x12, 0x19, 0x0a, 0x07, 0x7a, 0x5f, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x18, 0x20, 0x01, 0x28, 0x11, 0x48, 0x04, 0x52, 0x06, 0x7a, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x12, 0x2d, 0x0a, 0x07, 0x7a, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x19,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x51, 0x75,
+	0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x48, 0x04, 0x52, 0x06, 0x7a, 0x52, 0x61, 0x6e,
+	0x67, 0x65, 0x42, 0x03, 0x0a, 0x01, 0x78, 0x42, 0x03, 0x0a, 0x01, 0x79, 0x42, 0x03, 0x0a, 0x01,
+	0x74, 0x42, 0x06, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x03, 0x0a, 0x01, 0x7a, 0x22, 0x55,
+	0x0a, 0x07, 0x50, 0x6f, 0x6c, 0x79, 0x67, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x07, 0x6f, 0x75, 0x74,
+	0x6c, 0x69, 0x6e, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x2e, 0x52, 0x69, 0x6e, 0x67, 0x52, 0x07, 0x6f, 0x75, 0x74, 0x6c, 0x69, 0x6e,
+	0x65, 0x12, 0x22, 0x0a, 0x05, 0x68, 0x6f, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0c, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x52, 0x69, 0x6e, 0x67, 0x52, 0x05,
+	0x68, 0x6f, 0x6c, 0x65, 0x73, 0x22, 0x32, 0x0a, 0x04, 0x52, 0x69, 0x6e, 0x67, 0x12, 0x2a, 0x0a,
+	0x08, 0x76, 0x65, 0x72, 0x74, 0x69, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x56, 0x65, 0x72, 0x74, 0x65, 0x78, 0x52,
+	0x08, 0x76, 0x65, 0x72, 0x74, 0x69, 0x63, 0x65, 0x73, 0x22, 0x24, 0x0a, 0x06, 0x56, 0x65, 0x72,
+	0x74, 0x65, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01,
+	0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x79, 0x22,
+	0x3c, 0x0a, 0x06, 0x43, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x11, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x11, 0x52, 0x01, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x22, 0x56, 0x0a,
+	0x07, 0x4e, 0x65, 0x61, 0x72, 0x65, 0x73, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x11, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x11, 0x52, 0x01, 0x79, 0x12, 0x0c, 0x0a, 0x01, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x01, 0x6b, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x44, 0x69, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x22, 0xaf, 0x01, 0x0a, 0x10, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x62, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x05, 0x71, 0x75,
+	0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12,
+	0x16, 0x0a, 0x06, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x06, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x75, 0x66, 0x66, 0x65,
+	0x72, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x62, 0x75,
+	0x66, 0x66, 0x65, 0x72, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x3d, 0x0a, 0x0b, 0x73, 0x6c, 0x6f, 0x77,
+	0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1c, 0x2e,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x53, 0x6c, 0x6f, 0x77, 0x53, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x62, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x0a, 0x73, 0x6c, 0x6f,
+	0x77, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x22, 0x89, 0x01, 0x0a, 0x10, 0x41, 0x67, 0x67, 0x72,
+	0x65, 0x67, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x05,
+	0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72,
+	0x79, 0x12, 0x37, 0x0a, 0x0c, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x2e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x61, 0x67,
+	0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x62, 0x79,
+	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x62, 0x79, 0x4e,
+	0x61, 0x6d, 0x65, 0x22, 0x46, 0x0a, 0x11, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x2e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x6b, 0x0a, 0x0f, 0x41,
+	0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x2e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x5d, 0x0a, 0x0e, 0x41, 0x67, 0x67, 0x72,
+	0x65, 0x67, 0x61, 0x74, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x35, 0x0a, 0x0b, 0x61, 0x67,
+	0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x13, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xc7, 0x01, 0x0a, 0x0e, 0x48, 0x65, 0x61, 0x74,
+	0x6d, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x61,
+	0x74, 0x61, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x61, 0x74,
+	0x61, 0x73, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x30, 0x0a, 0x0a, 0x74, 0x69, 0x6d, 0x65,
+	0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x35, 0x0a, 0x0b, 0x61, 0x67,
+	0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x13, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x6f, 0x77, 0x6e, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x64, 0x6f, 0x77, 0x6e, 0x73, 0x61, 0x6d, 0x70, 0x6c,
+	0x65, 0x22, 0xb9, 0x01, 0x0a, 0x0f, 0x48, 0x65, 0x61, 0x74, 0x6d, 0x61, 0x70, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x68, 0x65, 0x69,
+	0x67, 0x68, 0x74, 0x12, 0x13, 0x0a, 0x05, 0x78, 0x5f, 0x6d, 0x69, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x11, 0x52, 0x04, 0x78, 0x4d, 0x69, 0x6e, 0x12, 0x13, 0x0a, 0x05, 0x79, 0x5f, 0x6d, 0x69,
+	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x11, 0x52, 0x04, 0x79, 0x4d, 0x69, 0x6e, 0x12, 0x1e, 0x0a,
+	0x0a, 0x64, 0x6f, 0x77, 0x6e, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0a, 0x64, 0x6f, 0x77, 0x6e, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x01, 0x52, 0x06, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18,
+	0x07, 0x20, 0x03, 0x28, 0x04, 0x52, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x22, 0x8f, 0x02,
+	0x0a, 0x11, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x35, 0x0a, 0x0b, 0x61, 0x67, 0x67, 0x72,
+	0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x0b, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x3c, 0x0a, 0x0c, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x5f, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x0b, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x57, 0x69, 0x64, 0x74, 0x68, 0x12, 0x38, 0x0a,
+	0x09, 0x61, 0x6c, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x61, 0x6c,
+	0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x6c, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x46,
+	0x69, 0x6c, 0x6c, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x6c, 0x22,
+	0x3c, 0x0a, 0x12, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x69, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x53,
+	0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x06, 0x73, 0x65, 0x72, 0x69, 0x65, 0x73, 0x22, 0x43, 0x0a,
+	0x06, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x25, 0x0a, 0x06, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x06, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x73, 0x22, 0x79, 0x0a, 0x05, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x30, 0x0a, 0x05, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x75, 0x6c,
+	0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x6e, 0x75, 0x6c, 0x6c, 0x22, 0x41, 0x0a,
+	0x07, 0x48, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64,
+	0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x11, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x69,
+	0x6e, 0x67, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x6f, 0x6c, 0x65, 0x72, 0x61, 0x6e, 0x63, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x74, 0x6f, 0x6c, 0x65, 0x72, 0x61, 0x6e, 0x63, 0x65,
+	0x22, 0x34, 0x0a, 0x0a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x11, 0x52, 0x05, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x11, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x22, 0x6b, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x61,
+	0x6e, 0x67, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x2c, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x03,
+	0x65, 0x6e, 0x64, 0x22, 0x9d, 0x02, 0x0a, 0x06, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x24,
+	0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x04,
+	0x6d, 0x65, 0x74, 0x61, 0x12, 0x0c, 0x0a, 0x01, 0x58, 0x18, 0x01, 0x20, 0x01, 0x28, 0x11, 0x52,
+	0x01, 0x58, 0x12, 0x0c, 0x0a, 0x01, 0x59, 0x18, 0x02, 0x20, 0x01, 0x28, 0x11, 0x52, 0x01, 0x59,
+	0x12, 0x0c, 0x0a, 0x01, 0x54, 0x18, 0x03, 0x20, 0x01, 0x28, 0x11, 0x52, 0x01, 0x54, 0x12, 0x19,
+	0x0a, 0x05, 0x74, 0x68, 0x65, 0x74, 0x61, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52,
+	0x05, 0x74, 0x68, 0x65, 0x74, 0x61, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x05, 0x77, 0x6f, 0x72,
+	0x6c, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x2e, 0x57, 0x6f, 0x72, 0x6c, 0x64, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x05, 0x77, 0x6f,
+	0x72, 0x6c, 0x64, 0x12, 0x22, 0x0a, 0x03, 0x67, 0x65, 0x6f, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x10, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x6f, 0x50, 0x6f, 0x69,
+	0x6e, 0x74, 0x52, 0x03, 0x67, 0x65, 0x6f, 0x12, 0x0c, 0x0a, 0x01, 0x5a, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x11, 0x52, 0x01, 0x5a, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x74, 0x68,
+	0x65, 0x74, 0x61, 0x22, 0xa0, 0x02, 0x0a, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x12, 0x2e, 0x0a, 0x04, 0x77, 0x68, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x77, 0x68, 0x65, 0x6e,
+	0x12, 0x34, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1c, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06,
+	0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x37, 0x0a, 0x07, 0x69, 0x6e, 0x64, 0x69, 0x63, 0x65,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x49, 0x6e, 0x64, 0x69, 0x63, 0x65,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x69, 0x6e, 0x64, 0x69, 0x63, 0x65, 0x73, 0x1a,
+	0x39, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3a, 0x0a, 0x0c, 0x49, 0x6e,
+	0x64, 0x69, 0x63, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x5f, 0x0a, 0x0f, 0x54, 0x72, 0x75, 0x6e, 0x63, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x61, 0x74,
+	0x61, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x61, 0x74, 0x61,
+	0x73, 0x65, 0x74, 0x12, 0x32, 0x0a, 0x06, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x06, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x22, 0x2c, 0x0a, 0x10, 0x54, 0x72, 0x75, 0x6e, 0x63,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x72,
+	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x72, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x64, 0x22, 0xc1, 0x03, 0x0a, 0x12, 0x41, 0x6c, 0x74, 0x65, 0x72, 0x53,
+	0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07,
+	0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64,
+	0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x12, 0x18, 0x0a, 0x05, 0x78, 0x5f, 0x6d, 0x69, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x11, 0x48, 0x00, 0x52, 0x04, 0x78, 0x4d, 0x69, 0x6e, 0x88, 0x01, 0x01,
+	0x12, 0x18, 0x0a, 0x05, 0x78, 0x5f, 0x6d, 0x61, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x11, 0x48,
+	0x01, 0x52, 0x04, 0x78, 0x4d, 0x61, 0x78, 0x88, 0x01, 0x01, 0x12, 0x18, 0x0a, 0x05, 0x79, 0x5f,
+	0x6d, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x11, 0x48, 0x02, 0x52, 0x04, 0x79, 0x4d, 0x69,
+	0x6e, 0x88, 0x01, 0x01, 0x12, 0x18, 0x0a, 0x05, 0x79, 0x5f, 0x6d, 0x61, 0x78, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x11, 0x48, 0x03, 0x52, 0x04, 0x79, 0x4d, 0x61, 0x78, 0x88, 0x01, 0x01, 0x12, 0x18,
+	0x0a, 0x05, 0x7a, 0x5f, 0x6d, 0x69, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x11, 0x48, 0x04, 0x52,
+	0x04, 0x7a, 0x4d, 0x69, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x18, 0x0a, 0x05, 0x7a, 0x5f, 0x6d, 0x61,
+	0x78, 0x18, 0x07, 0x20, 0x01, 0x28, 0x11, 0x48, 0x05, 0x52, 0x04, 0x7a, 0x4d, 0x61, 0x78, 0x88,
+	0x01, 0x01, 0x12, 0x34, 0x0a, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x46,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x48, 0x06, 0x52, 0x09, 0x66, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x6e, 0x63, 0x79, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a, 0x0e, 0x73, 0x6f, 0x72, 0x74,
+	0x5f, 0x6f, 0x6e, 0x5f, 0x69, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08,
+	0x48, 0x07, 0x52, 0x0c, 0x73, 0x6f, 0x72, 0x74, 0x4f, 0x6e, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74,
+	0x88, 0x01, 0x01, 0x12, 0x37, 0x0a, 0x09, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x09, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x08, 0x0a, 0x06,
+	0x5f, 0x78, 0x5f, 0x6d, 0x69, 0x6e, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x78, 0x5f, 0x6d, 0x61, 0x78,
+	0x42, 0x08, 0x0a, 0x06, 0x5f, 0x79, 0x5f, 0x6d, 0x69, 0x6e, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x79,
+	0x5f, 0x6d, 0x61, 0x78, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x7a, 0x5f, 0x6d, 0x69, 0x6e, 0x42, 0x08,
+	0x0a, 0x06, 0x5f, 0x7a, 0x5f, 0x6d, 0x61, 0x78, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x66, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x73, 0x6f, 0x72, 0x74, 0x5f,
+	0x6f, 0x6e, 0x5f, 0x69, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x22, 0x30, 0x0a, 0x14, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x22, 0x4d, 0x0a, 0x0d, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x05,
+	0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72,
+	0x79, 0x12, 0x17, 0x0a, 0x07, 0x64, 0x72, 0x79, 0x5f, 0x72, 0x75, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x06, 0x64, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x22, 0x2a, 0x0a, 0x0e, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x72,
+	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x22, 0x5f, 0x0a, 0x04, 0x5a, 0x6f, 0x6e, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x07,
+	0x70, 0x6f, 0x6c, 0x79, 0x67, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x50, 0x6f, 0x6c, 0x79, 0x67, 0x6f, 0x6e, 0x52, 0x07,
+	0x70, 0x6f, 0x6c, 0x79, 0x67, 0x6f, 0x6e, 0x22, 0x2c, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x5a,
+	0x6f, 0x6e, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x64,
+	0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x61,
+	0x74, 0x61, 0x73, 0x65, 0x74, 0x22, 0x37, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x5a, 0x6f, 0x6e,
+	0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x05, 0x7a, 0x6f,
+	0x6e, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x2e, 0x5a, 0x6f, 0x6e, 0x65, 0x52, 0x05, 0x7a, 0x6f, 0x6e, 0x65, 0x73, 0x22, 0x41,
+	0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x22, 0x21, 0x0a, 0x0b, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b,
+	0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x22, 0x23, 0x0a, 0x0d, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x5c, 0x0a, 0x0e, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x72,
+	0x65, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72, 0x65, 0x66, 0x12, 0x1d, 0x0a,
+	0x0a, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x55, 0x73, 0x65, 0x72, 0x12, 0x19, 0x0a, 0x08,
+	0x62, 0x75, 0x69, 0x6c, 0x74, 0x5f, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x62, 0x75, 0x69, 0x6c, 0x74, 0x4f, 0x6e, 0x2a, 0x3c, 0x0a, 0x09, 0x46, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x6e, 0x63, 0x79, 0x12, 0x08, 0x0a, 0x04, 0x46, 0x31, 0x48, 0x7a, 0x10, 0x00, 0x12, 0x0a,
+	0x0a, 0x06, 0x46, 0x31, 0x30, 0x30, 0x48, 0x7a, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x31,
+	0x30, 0x30, 0x30, 0x48, 0x7a, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x46, 0x31, 0x30, 0x30, 0x30,
+	0x30, 0x48, 0x7a, 0x10, 0x03, 0x2a, 0x1e, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x07, 0x0a, 0x03, 0x45, 0x4e, 0x55, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03,
+	0x55, 0x54, 0x4d, 0x10, 0x01, 0x2a, 0x37, 0x0a, 0x14, 0x53, 0x6c, 0x6f, 0x77, 0x53, 0x75, 0x62,
+	0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x0f, 0x0a,
+	0x0b, 0x44, 0x72, 0x6f, 0x70, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x10, 0x00, 0x12, 0x0e,
+	0x0a, 0x0a, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x10, 0x01, 0x2a, 0x5e,
+	0x0a, 0x0b, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x09, 0x0a,
+	0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x75, 0x6d, 0x10,
+	0x01, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x69, 0x6e, 0x10, 0x02, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x61,
+	0x78, 0x10, 0x03, 0x12, 0x08, 0x0a, 0x04, 0x4d, 0x65, 0x61, 0x6e, 0x10, 0x04, 0x12, 0x0a, 0x0a,
+	0x06, 0x53, 0x74, 0x64, 0x64, 0x65, 0x76, 0x10, 0x05, 0x12, 0x09, 0x0a, 0x05, 0x46, 0x69, 0x72,
+	0x73, 0x74, 0x10, 0x06, 0x12, 0x08, 0x0a, 0x04, 0x4c, 0x61, 0x73, 0x74, 0x10, 0x07, 0x2a, 0x4a,
+	0x0a, 0x0a, 0x46, 0x69, 0x6c, 0x6c, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x0c, 0x0a, 0x08,
+	0x46, 0x69, 0x6c, 0x6c, 0x4e, 0x6f, 0x6e, 0x65, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x46, 0x69,
+	0x6c, 0x6c, 0x4e, 0x75, 0x6c, 0x6c, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x46, 0x69, 0x6c, 0x6c,
+	0x50, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x46, 0x69,
+	0x6c, 0x6c, 0x4c, 0x69, 0x6e, 0x65, 0x61, 0x72, 0x10, 0x03, 0x32, 0x93, 0x09, 0x0a, 0x03, 0x58,
+	0x79, 0x74, 0x12, 0x37, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x1a, 0x14, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x53, 0x74, 0x61,
+	0x74, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x12, 0x35, 0x0a, 0x09, 0x41,
+	0x64, 0x64, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x0e, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x22, 0x00, 0x12, 0x34, 0x0a, 0x06, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x12, 0x0e, 0x2e, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x1a, 0x16, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x28, 0x01, 0x12, 0x2b, 0x0a, 0x06, 0x53, 0x65, 0x6c, 0x65,
+	0x63, 0x74, 0x12, 0x0d, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x1a, 0x0e, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x22, 0x00, 0x30, 0x01, 0x12, 0x42, 0x0a, 0x09, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61,
+	0x74, 0x65, 0x12, 0x18, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x41, 0x67, 0x67, 0x72,
+	0x65, 0x67, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x07, 0x48, 0x65, 0x61,
+	0x74, 0x6d, 0x61, 0x70, 0x12, 0x16, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x48, 0x65,
+	0x61, 0x74, 0x6d, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x48, 0x65, 0x61, 0x74, 0x6d, 0x61, 0x70, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x45, 0x0a, 0x0a, 0x54, 0x69, 0x6d, 0x65, 0x53,
+	0x65, 0x72, 0x69, 0x65, 0x73, 0x12, 0x19, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1a, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x65,
+	0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x39,
+	0x0a, 0x09, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x18, 0x2e, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x52,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x22, 0x00, 0x30, 0x01, 0x12, 0x3d, 0x0a, 0x08, 0x53, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x15, 0x2e,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x43,
+	0x68, 0x75, 0x6e, 0x6b, 0x22, 0x00, 0x30, 0x01, 0x12, 0x3c, 0x0a, 0x07, 0x52, 0x65, 0x73, 0x74,
+	0x6f, 0x72, 0x65, 0x12, 0x15, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x53, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x22, 0x00, 0x28, 0x01, 0x12, 0x3f, 0x0a, 0x08, 0x54, 0x72, 0x75, 0x6e, 0x63, 0x61,
+	0x74, 0x65, 0x12, 0x17, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x54, 0x72, 0x75, 0x6e,
+	0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x2e, 0x54, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x0b, 0x41, 0x6c, 0x74, 0x65, 0x72,
+	0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x1a, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e,
+	0x41, 0x6c, 0x74, 0x65, 0x72, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x47, 0x0a, 0x0d,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x12, 0x1c, 0x2e,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x61, 0x74,
+	0x61, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x06, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12,
+	0x15, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x31, 0x0a, 0x07, 0x41, 0x64, 0x64, 0x5a, 0x6f, 0x6e, 0x65, 0x12, 0x0c, 0x2e, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x2e, 0x5a, 0x6f, 0x6e, 0x65, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
 	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
-	0x79, 0x1a, 0x16, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69,
-	0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x42, 0x1e, 0x5a, 0x1c, 0x67,
-	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x78, 0x79, 0x74, 0x2d, 0x64, 0x62,
-	0x2f, 0x78, 0x79, 0x74, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+	0x79, 0x22, 0x00, 0x12, 0x42, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x5a, 0x6f, 0x6e, 0x65, 0x73,
+	0x12, 0x18, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x5a, 0x6f,
+	0x6e, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x5a, 0x6f, 0x6e, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x41, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x12, 0x19, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x37, 0x0a, 0x0d, 0x45, 0x78,
+	0x70, 0x6f, 0x72, 0x74, 0x47, 0x65, 0x6f, 0x4a, 0x53, 0x4f, 0x4e, 0x12, 0x0d, 0x2e, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x1a, 0x13, 0x2e, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x22,
+	0x00, 0x30, 0x01, 0x12, 0x3b, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00,
+	0x42, 0x1e, 0x5a, 0x1c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x78,
+	0x79, 0x74, 0x2d, 0x64, 0x62, 0x2f, 0x78, 0x79, 0x74, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1295,60 +4644,177 @@ func file_server_proto_rawDescGZIP() []byte {
 	return file_server_proto_rawDescData
 }
 
-var file_server_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_server_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_server_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_server_proto_msgTypes = make([]protoimpl.MessageInfo, 51)
 var file_server_proto_goTypes = []interface{}{
 	(Frequency)(0),                // 0: server.Frequency
-	(*StatsMessage)(nil),          // 1: server.StatsMessage
-	(*Host)(nil),                  // 2: server.Host
-	(*Memstats)(nil),              // 3: server.Memstats
-	(*Schema)(nil),                // 4: server.Schema
-	(*SchemaStats)(nil),           // 5: server.SchemaStats
-	(*Query)(nil),                 // 6: server.Query
-	(*QueryRange)(nil),            // 7: server.QueryRange
-	(*TimeRange)(nil),             // 8: server.TimeRange
-	(*Record)(nil),                // 9: server.Record
-	(*Metadata)(nil),              // 10: server.Metadata
-	(*VersionMessage)(nil),        // 11: server.VersionMessage
-	nil,                           // 12: server.StatsMessage.DatasetsEntry
-	nil,                           // 13: server.Metadata.LabelsEntry
-	nil,                           // 14: server.Metadata.IndicesEntry
-	(*timestamppb.Timestamp)(nil), // 15: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),         // 16: google.protobuf.Empty
+	(Projection)(0),               // 1: server.Projection
+	(SlowSubscriberPolicy)(0),     // 2: server.SlowSubscriberPolicy
+	(Aggregation)(0),              // 3: server.Aggregation
+	(FillPolicy)(0),               // 4: server.FillPolicy
+	(*StatsMessage)(nil),          // 5: server.StatsMessage
+	(*Host)(nil),                  // 6: server.Host
+	(*Memstats)(nil),              // 7: server.Memstats
+	(*Schema)(nil),                // 8: server.Schema
+	(*Geo)(nil),                   // 9: server.Geo
+	(*GeoBox)(nil),                // 10: server.GeoBox
+	(*GeoPoint)(nil),              // 11: server.GeoPoint
+	(*Transform)(nil),             // 12: server.Transform
+	(*WorldPoint)(nil),            // 13: server.WorldPoint
+	(*RollupTier)(nil),            // 14: server.RollupTier
+	(*RollupBucket)(nil),          // 15: server.RollupBucket
+	(*SchemaStats)(nil),           // 16: server.SchemaStats
+	(*Query)(nil),                 // 17: server.Query
+	(*Polygon)(nil),               // 18: server.Polygon
+	(*Ring)(nil),                  // 19: server.Ring
+	(*Vertex)(nil),                // 20: server.Vertex
+	(*Circle)(nil),                // 21: server.Circle
+	(*Nearest)(nil),               // 22: server.Nearest
+	(*SubscribeRequest)(nil),      // 23: server.SubscribeRequest
+	(*AggregateRequest)(nil),      // 24: server.AggregateRequest
+	(*AggregateResponse)(nil),     // 25: server.AggregateResponse
+	(*AggregateResult)(nil),       // 26: server.AggregateResult
+	(*AggregateValue)(nil),        // 27: server.AggregateValue
+	(*HeatmapRequest)(nil),        // 28: server.HeatmapRequest
+	(*HeatmapResponse)(nil),       // 29: server.HeatmapResponse
+	(*TimeSeriesRequest)(nil),     // 30: server.TimeSeriesRequest
+	(*TimeSeriesResponse)(nil),    // 31: server.TimeSeriesResponse
+	(*Series)(nil),                // 32: server.Series
+	(*Point)(nil),                 // 33: server.Point
+	(*Heading)(nil),               // 34: server.Heading
+	(*QueryRange)(nil),            // 35: server.QueryRange
+	(*TimeRange)(nil),             // 36: server.TimeRange
+	(*Record)(nil),                // 37: server.Record
+	(*Metadata)(nil),              // 38: server.Metadata
+	(*TruncateRequest)(nil),       // 39: server.TruncateRequest
+	(*TruncateResponse)(nil),      // 40: server.TruncateResponse
+	(*AlterSchemaRequest)(nil),    // 41: server.AlterSchemaRequest
+	(*DeleteDatasetRequest)(nil),  // 42: server.DeleteDatasetRequest
+	(*DeleteRequest)(nil),         // 43: server.DeleteRequest
+	(*DeleteResponse)(nil),        // 44: server.DeleteResponse
+	(*Zone)(nil),                  // 45: server.Zone
+	(*ListZonesRequest)(nil),      // 46: server.ListZonesRequest
+	(*ListZonesResponse)(nil),     // 47: server.ListZonesResponse
+	(*DeleteZoneRequest)(nil),     // 48: server.DeleteZoneRequest
+	(*ExportChunk)(nil),           // 49: server.ExportChunk
+	(*SnapshotChunk)(nil),         // 50: server.SnapshotChunk
+	(*VersionMessage)(nil),        // 51: server.VersionMessage
+	nil,                           // 52: server.StatsMessage.DatasetsEntry
+	nil,                           // 53: server.Schema.ZonesEntry
+	nil,                           // 54: server.Metadata.LabelsEntry
+	nil,                           // 55: server.Metadata.IndicesEntry
+	(*durationpb.Duration)(nil),   // 56: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 57: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),         // 58: google.protobuf.Empty
 }
 var file_server_proto_depIdxs = []int32{
-	2,  // 0: server.StatsMessage.host:type_name -> server.Host
-	11, // 1: server.StatsMessage.version:type_name -> server.VersionMessage
-	12, // 2: server.StatsMessage.datasets:type_name -> server.StatsMessage.DatasetsEntry
-	3,  // 3: server.Host.memstats:type_name -> server.Memstats
+	6,  // 0: server.StatsMessage.host:type_name -> server.Host
+	51, // 1: server.StatsMessage.version:type_name -> server.VersionMessage
+	52, // 2: server.StatsMessage.datasets:type_name -> server.StatsMessage.DatasetsEntry
+	7,  // 3: server.Host.memstats:type_name -> server.Memstats
 	0,  // 4: server.Schema.frequency:type_name -> server.Frequency
-	4,  // 5: server.SchemaStats.schema:type_name -> server.Schema
-	7,  // 6: server.Query.x_range:type_name -> server.QueryRange
-	7,  // 7: server.Query.y_range:type_name -> server.QueryRange
-	7,  // 8: server.Query.t_range:type_name -> server.QueryRange
-	8,  // 9: server.Query.time_range:type_name -> server.TimeRange
-	15, // 10: server.TimeRange.start:type_name -> google.protobuf.Timestamp
-	15, // 11: server.TimeRange.end:type_name -> google.protobuf.Timestamp
-	10, // 12: server.Record.meta:type_name -> server.Metadata
-	15, // 13: server.Metadata.when:type_name -> google.protobuf.Timestamp
-	13, // 14: server.Metadata.labels:type_name -> server.Metadata.LabelsEntry
-	14, // 15: server.Metadata.indices:type_name -> server.Metadata.IndicesEntry
-	5,  // 16: server.StatsMessage.DatasetsEntry.value:type_name -> server.SchemaStats
-	16, // 17: server.Xyt.Stats:input_type -> google.protobuf.Empty
-	4,  // 18: server.Xyt.AddSchema:input_type -> server.Schema
-	9,  // 19: server.Xyt.Insert:input_type -> server.Record
-	6,  // 20: server.Xyt.Select:input_type -> server.Query
-	16, // 21: server.Xyt.Version:input_type -> google.protobuf.Empty
-	1,  // 22: server.Xyt.Stats:output_type -> server.StatsMessage
-	16, // 23: server.Xyt.AddSchema:output_type -> google.protobuf.Empty
-	16, // 24: server.Xyt.Insert:output_type -> google.protobuf.Empty
-	9,  // 25: server.Xyt.Select:output_type -> server.Record
-	11, // 26: server.Xyt.Version:output_type -> server.VersionMessage
-	22, // [22:27] is the sub-list for method output_type
-	17, // [17:22] is the sub-list for method input_type
-	17, // [17:17] is the sub-list for extension type_name
-	17, // [17:17] is the sub-list for extension extendee
-	0,  // [0:17] is the sub-list for field type_name
+	56, // 5: server.Schema.retention:type_name -> google.protobuf.Duration
+	14, // 6: server.Schema.rollups:type_name -> server.RollupTier
+	53, // 7: server.Schema.zones:type_name -> server.Schema.ZonesEntry
+	12, // 8: server.Schema.transform:type_name -> server.Transform
+	9,  // 9: server.Schema.geo:type_name -> server.Geo
+	1,  // 10: server.Geo.projection:type_name -> server.Projection
+	10, // 11: server.Geo.bounds:type_name -> server.GeoBox
+	56, // 12: server.RollupTier.resolution:type_name -> google.protobuf.Duration
+	56, // 13: server.RollupTier.retention:type_name -> google.protobuf.Duration
+	57, // 14: server.RollupBucket.start:type_name -> google.protobuf.Timestamp
+	57, // 15: server.RollupBucket.first_when:type_name -> google.protobuf.Timestamp
+	57, // 16: server.RollupBucket.last_when:type_name -> google.protobuf.Timestamp
+	8,  // 17: server.SchemaStats.schema:type_name -> server.Schema
+	35, // 18: server.Query.x_range:type_name -> server.QueryRange
+	35, // 19: server.Query.y_range:type_name -> server.QueryRange
+	35, // 20: server.Query.t_range:type_name -> server.QueryRange
+	34, // 21: server.Query.t_heading:type_name -> server.Heading
+	36, // 22: server.Query.time_range:type_name -> server.TimeRange
+	21, // 23: server.Query.within:type_name -> server.Circle
+	22, // 24: server.Query.nearest:type_name -> server.Nearest
+	18, // 25: server.Query.polygons:type_name -> server.Polygon
+	18, // 26: server.Query.world_polygons:type_name -> server.Polygon
+	35, // 27: server.Query.z_range:type_name -> server.QueryRange
+	19, // 28: server.Polygon.outline:type_name -> server.Ring
+	19, // 29: server.Polygon.holes:type_name -> server.Ring
+	20, // 30: server.Ring.vertices:type_name -> server.Vertex
+	17, // 31: server.SubscribeRequest.query:type_name -> server.Query
+	2,  // 32: server.SubscribeRequest.slow_policy:type_name -> server.SlowSubscriberPolicy
+	17, // 33: server.AggregateRequest.query:type_name -> server.Query
+	3,  // 34: server.AggregateRequest.aggregations:type_name -> server.Aggregation
+	26, // 35: server.AggregateResponse.results:type_name -> server.AggregateResult
+	27, // 36: server.AggregateResult.values:type_name -> server.AggregateValue
+	3,  // 37: server.AggregateValue.aggregation:type_name -> server.Aggregation
+	36, // 38: server.HeatmapRequest.time_range:type_name -> server.TimeRange
+	3,  // 39: server.HeatmapRequest.aggregation:type_name -> server.Aggregation
+	17, // 40: server.TimeSeriesRequest.query:type_name -> server.Query
+	3,  // 41: server.TimeSeriesRequest.aggregation:type_name -> server.Aggregation
+	56, // 42: server.TimeSeriesRequest.bucket_width:type_name -> google.protobuf.Duration
+	57, // 43: server.TimeSeriesRequest.alignment:type_name -> google.protobuf.Timestamp
+	4,  // 44: server.TimeSeriesRequest.fill:type_name -> server.FillPolicy
+	32, // 45: server.TimeSeriesResponse.series:type_name -> server.Series
+	33, // 46: server.Series.points:type_name -> server.Point
+	57, // 47: server.Point.start:type_name -> google.protobuf.Timestamp
+	57, // 48: server.TimeRange.start:type_name -> google.protobuf.Timestamp
+	57, // 49: server.TimeRange.end:type_name -> google.protobuf.Timestamp
+	38, // 50: server.Record.meta:type_name -> server.Metadata
+	13, // 51: server.Record.world:type_name -> server.WorldPoint
+	11, // 52: server.Record.geo:type_name -> server.GeoPoint
+	57, // 53: server.Metadata.when:type_name -> google.protobuf.Timestamp
+	54, // 54: server.Metadata.labels:type_name -> server.Metadata.LabelsEntry
+	55, // 55: server.Metadata.indices:type_name -> server.Metadata.IndicesEntry
+	57, // 56: server.TruncateRequest.before:type_name -> google.protobuf.Timestamp
+	0,  // 57: server.AlterSchemaRequest.frequency:type_name -> server.Frequency
+	56, // 58: server.AlterSchemaRequest.retention:type_name -> google.protobuf.Duration
+	17, // 59: server.DeleteRequest.query:type_name -> server.Query
+	18, // 60: server.Zone.polygon:type_name -> server.Polygon
+	45, // 61: server.ListZonesResponse.zones:type_name -> server.Zone
+	16, // 62: server.StatsMessage.DatasetsEntry.value:type_name -> server.SchemaStats
+	18, // 63: server.Schema.ZonesEntry.value:type_name -> server.Polygon
+	58, // 64: server.Xyt.Stats:input_type -> google.protobuf.Empty
+	8,  // 65: server.Xyt.AddSchema:input_type -> server.Schema
+	37, // 66: server.Xyt.Insert:input_type -> server.Record
+	17, // 67: server.Xyt.Select:input_type -> server.Query
+	24, // 68: server.Xyt.Aggregate:input_type -> server.AggregateRequest
+	28, // 69: server.Xyt.Heatmap:input_type -> server.HeatmapRequest
+	30, // 70: server.Xyt.TimeSeries:input_type -> server.TimeSeriesRequest
+	23, // 71: server.Xyt.Subscribe:input_type -> server.SubscribeRequest
+	58, // 72: server.Xyt.Snapshot:input_type -> google.protobuf.Empty
+	50, // 73: server.Xyt.Restore:input_type -> server.SnapshotChunk
+	39, // 74: server.Xyt.Truncate:input_type -> server.TruncateRequest
+	41, // 75: server.Xyt.AlterSchema:input_type -> server.AlterSchemaRequest
+	42, // 76: server.Xyt.DeleteDataset:input_type -> server.DeleteDatasetRequest
+	43, // 77: server.Xyt.Delete:input_type -> server.DeleteRequest
+	45, // 78: server.Xyt.AddZone:input_type -> server.Zone
+	46, // 79: server.Xyt.ListZones:input_type -> server.ListZonesRequest
+	48, // 80: server.Xyt.DeleteZone:input_type -> server.DeleteZoneRequest
+	17, // 81: server.Xyt.ExportGeoJSON:input_type -> server.Query
+	58, // 82: server.Xyt.Version:input_type -> google.protobuf.Empty
+	5,  // 83: server.Xyt.Stats:output_type -> server.StatsMessage
+	58, // 84: server.Xyt.AddSchema:output_type -> google.protobuf.Empty
+	58, // 85: server.Xyt.Insert:output_type -> google.protobuf.Empty
+	37, // 86: server.Xyt.Select:output_type -> server.Record
+	25, // 87: server.Xyt.Aggregate:output_type -> server.AggregateResponse
+	29, // 88: server.Xyt.Heatmap:output_type -> server.HeatmapResponse
+	31, // 89: server.Xyt.TimeSeries:output_type -> server.TimeSeriesResponse
+	37, // 90: server.Xyt.Subscribe:output_type -> server.Record
+	50, // 91: server.Xyt.Snapshot:output_type -> server.SnapshotChunk
+	58, // 92: server.Xyt.Restore:output_type -> google.protobuf.Empty
+	40, // 93: server.Xyt.Truncate:output_type -> server.TruncateResponse
+	58, // 94: server.Xyt.AlterSchema:output_type -> google.protobuf.Empty
+	58, // 95: server.Xyt.DeleteDataset:output_type -> google.protobuf.Empty
+	44, // 96: server.Xyt.Delete:output_type -> server.DeleteResponse
+	58, // 97: server.Xyt.AddZone:output_type -> google.protobuf.Empty
+	47, // 98: server.Xyt.ListZones:output_type -> server.ListZonesResponse
+	58, // 99: server.Xyt.DeleteZone:output_type -> google.protobuf.Empty
+	49, // 100: server.Xyt.ExportGeoJSON:output_type -> server.ExportChunk
+	51, // 101: server.Xyt.Version:output_type -> server.VersionMessage
+	83, // [83:102] is the sub-list for method output_type
+	64, // [64:83] is the sub-list for method input_type
+	64, // [64:64] is the sub-list for extension type_name
+	64, // [64:64] is the sub-list for extension extendee
+	0,  // [0:64] is the sub-list for field type_name
 }
 
 func init() { file_server_proto_init() }
@@ -1369,8 +4835,152 @@ func file_server_proto_init() {
 				return nil
 			}
 		}
-		file_server_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Host); i {
+		file_server_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Host); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Memstats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Schema); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Geo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GeoBox); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GeoPoint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Transform); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorldPoint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RollupTier); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RollupBucket); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SchemaStats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Query); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Polygon); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1381,8 +4991,8 @@ func file_server_proto_init() {
 				return nil
 			}
 		}
-		file_server_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Memstats); i {
+		file_server_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Ring); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1393,8 +5003,8 @@ func file_server_proto_init() {
 				return nil
 			}
 		}
-		file_server_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Schema); i {
+		file_server_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Vertex); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1405,8 +5015,8 @@ func file_server_proto_init() {
 				return nil
 			}
 		}
-		file_server_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SchemaStats); i {
+		file_server_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Circle); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1417,8 +5027,8 @@ func file_server_proto_init() {
 				return nil
 			}
 		}
-		file_server_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Query); i {
+		file_server_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Nearest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1429,7 +5039,151 @@ func file_server_proto_init() {
 				return nil
 			}
 		}
-		file_server_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+		file_server_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AggregateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AggregateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AggregateResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AggregateValue); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HeatmapRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HeatmapResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TimeSeriesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TimeSeriesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Series); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Point); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Heading); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*QueryRange); i {
 			case 0:
 				return &v.state
@@ -1441,7 +5195,7 @@ func file_server_proto_init() {
 				return nil
 			}
 		}
-		file_server_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+		file_server_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*TimeRange); i {
 			case 0:
 				return &v.state
@@ -1453,7 +5207,7 @@ func file_server_proto_init() {
 				return nil
 			}
 		}
-		file_server_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+		file_server_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Record); i {
 			case 0:
 				return &v.state
@@ -1465,7 +5219,7 @@ func file_server_proto_init() {
 				return nil
 			}
 		}
-		file_server_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+		file_server_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Metadata); i {
 			case 0:
 				return &v.state
@@ -1477,7 +5231,151 @@ func file_server_proto_init() {
 				return nil
 			}
 		}
-		file_server_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+		file_server_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TruncateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TruncateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AlterSchemaRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteDatasetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Zone); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListZonesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListZonesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteZoneRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportChunk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SnapshotChunk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*VersionMessage); i {
 			case 0:
 				return &v.state
@@ -1490,7 +5388,7 @@ func file_server_proto_init() {
 			}
 		}
 	}
-	file_server_proto_msgTypes[5].OneofWrappers = []interface{}{
+	file_server_proto_msgTypes[12].OneofWrappers = []interface{}{
 		(*Query_XAll)(nil),
 		(*Query_XValue)(nil),
 		(*Query_XRange)(nil),
@@ -1500,17 +5398,23 @@ func file_server_proto_init() {
 		(*Query_TAll)(nil),
 		(*Query_TValue)(nil),
 		(*Query_TRange)(nil),
+		(*Query_THeading)(nil),
 		(*Query_TimeAll)(nil),
 		(*Query_TimeLatest)(nil),
 		(*Query_TimeRange)(nil),
+		(*Query_ZAll)(nil),
+		(*Query_ZValue)(nil),
+		(*Query_ZRange)(nil),
 	}
+	file_server_proto_msgTypes[32].OneofWrappers = []interface{}{}
+	file_server_proto_msgTypes[36].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_server_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   14,
+			NumEnums:      5,
+			NumMessages:   51,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
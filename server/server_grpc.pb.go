@@ -20,11 +20,25 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Xyt_Stats_FullMethodName     = "/server.Xyt/Stats"
-	Xyt_AddSchema_FullMethodName = "/server.Xyt/AddSchema"
-	Xyt_Insert_FullMethodName    = "/server.Xyt/Insert"
-	Xyt_Select_FullMethodName    = "/server.Xyt/Select"
-	Xyt_Version_FullMethodName   = "/server.Xyt/Version"
+	Xyt_Stats_FullMethodName         = "/server.Xyt/Stats"
+	Xyt_AddSchema_FullMethodName     = "/server.Xyt/AddSchema"
+	Xyt_Insert_FullMethodName        = "/server.Xyt/Insert"
+	Xyt_Select_FullMethodName        = "/server.Xyt/Select"
+	Xyt_Aggregate_FullMethodName     = "/server.Xyt/Aggregate"
+	Xyt_Heatmap_FullMethodName       = "/server.Xyt/Heatmap"
+	Xyt_TimeSeries_FullMethodName    = "/server.Xyt/TimeSeries"
+	Xyt_Subscribe_FullMethodName     = "/server.Xyt/Subscribe"
+	Xyt_Snapshot_FullMethodName      = "/server.Xyt/Snapshot"
+	Xyt_Restore_FullMethodName       = "/server.Xyt/Restore"
+	Xyt_Truncate_FullMethodName      = "/server.Xyt/Truncate"
+	Xyt_AlterSchema_FullMethodName   = "/server.Xyt/AlterSchema"
+	Xyt_DeleteDataset_FullMethodName = "/server.Xyt/DeleteDataset"
+	Xyt_Delete_FullMethodName        = "/server.Xyt/Delete"
+	Xyt_AddZone_FullMethodName       = "/server.Xyt/AddZone"
+	Xyt_ListZones_FullMethodName     = "/server.Xyt/ListZones"
+	Xyt_DeleteZone_FullMethodName    = "/server.Xyt/DeleteZone"
+	Xyt_ExportGeoJSON_FullMethodName = "/server.Xyt/ExportGeoJSON"
+	Xyt_Version_FullMethodName       = "/server.Xyt/Version"
 )
 
 // XytClient is the client API for Xyt service.
@@ -35,6 +49,35 @@ type XytClient interface {
 	AddSchema(ctx context.Context, in *Schema, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	Insert(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[Record, emptypb.Empty], error)
 	Select(ctx context.Context, in *Query, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Record], error)
+	Aggregate(ctx context.Context, in *AggregateRequest, opts ...grpc.CallOption) (*AggregateResponse, error)
+	Heatmap(ctx context.Context, in *HeatmapRequest, opts ...grpc.CallOption) (*HeatmapResponse, error)
+	TimeSeries(ctx context.Context, in *TimeSeriesRequest, opts ...grpc.CallOption) (*TimeSeriesResponse, error)
+	// Subscribe streams every newly inserted record matching a query,
+	// optionally after first replaying the records which already match
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Record], error)
+	// Snapshot streams a point-in-time copy of the whole database, which
+	// can be fed back into Restore on this, or another, server
+	Snapshot(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SnapshotChunk], error)
+	Restore(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[SnapshotChunk, emptypb.Empty], error)
+	// Truncate drops every record in a dataset older than a given time
+	Truncate(ctx context.Context, in *TruncateRequest, opts ...grpc.CallOption) (*TruncateResponse, error)
+	// AlterSchema changes an existing dataset's schema, such as growing
+	// its bounds; changes which would drop records are rejected
+	AlterSchema(ctx context.Context, in *AlterSchemaRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// DeleteDataset drops a dataset entirely; its schema, records,
+	// rollups, indices, zones, and stats
+	DeleteDataset(ctx context.Context, in *DeleteDatasetRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Delete drops every record matching a query, or, for a dry run,
+	// counts the records which would be dropped
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Zones are named polygons, stored against a dataset, which
+	// queries can then refer to by name
+	AddZone(ctx context.Context, in *Zone, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListZones(ctx context.Context, in *ListZonesRequest, opts ...grpc.CallOption) (*ListZonesResponse, error)
+	DeleteZone(ctx context.Context, in *DeleteZoneRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// ExportGeoJSON streams the records matching a query, from a dataset
+	// with a Geo projection, as a GeoJSON FeatureCollection
+	ExportGeoJSON(ctx context.Context, in *Query, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error)
 	Version(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*VersionMessage, error)
 }
 
@@ -98,6 +141,176 @@ func (c *xytClient) Select(ctx context.Context, in *Query, opts ...grpc.CallOpti
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Xyt_SelectClient = grpc.ServerStreamingClient[Record]
 
+func (c *xytClient) Aggregate(ctx context.Context, in *AggregateRequest, opts ...grpc.CallOption) (*AggregateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AggregateResponse)
+	err := c.cc.Invoke(ctx, Xyt_Aggregate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *xytClient) Heatmap(ctx context.Context, in *HeatmapRequest, opts ...grpc.CallOption) (*HeatmapResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HeatmapResponse)
+	err := c.cc.Invoke(ctx, Xyt_Heatmap_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *xytClient) TimeSeries(ctx context.Context, in *TimeSeriesRequest, opts ...grpc.CallOption) (*TimeSeriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TimeSeriesResponse)
+	err := c.cc.Invoke(ctx, Xyt_TimeSeries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *xytClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Record], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Xyt_ServiceDesc.Streams[2], Xyt_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, Record]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Xyt_SubscribeClient = grpc.ServerStreamingClient[Record]
+
+func (c *xytClient) Snapshot(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SnapshotChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Xyt_ServiceDesc.Streams[3], Xyt_Snapshot_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[emptypb.Empty, SnapshotChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Xyt_SnapshotClient = grpc.ServerStreamingClient[SnapshotChunk]
+
+func (c *xytClient) Restore(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[SnapshotChunk, emptypb.Empty], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Xyt_ServiceDesc.Streams[4], Xyt_Restore_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SnapshotChunk, emptypb.Empty]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Xyt_RestoreClient = grpc.ClientStreamingClient[SnapshotChunk, emptypb.Empty]
+
+func (c *xytClient) Truncate(ctx context.Context, in *TruncateRequest, opts ...grpc.CallOption) (*TruncateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TruncateResponse)
+	err := c.cc.Invoke(ctx, Xyt_Truncate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *xytClient) AlterSchema(ctx context.Context, in *AlterSchemaRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Xyt_AlterSchema_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *xytClient) DeleteDataset(ctx context.Context, in *DeleteDatasetRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Xyt_DeleteDataset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *xytClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, Xyt_Delete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *xytClient) AddZone(ctx context.Context, in *Zone, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Xyt_AddZone_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *xytClient) ListZones(ctx context.Context, in *ListZonesRequest, opts ...grpc.CallOption) (*ListZonesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListZonesResponse)
+	err := c.cc.Invoke(ctx, Xyt_ListZones_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *xytClient) DeleteZone(ctx context.Context, in *DeleteZoneRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Xyt_DeleteZone_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *xytClient) ExportGeoJSON(ctx context.Context, in *Query, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Xyt_ServiceDesc.Streams[5], Xyt_ExportGeoJSON_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Query, ExportChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Xyt_ExportGeoJSONClient = grpc.ServerStreamingClient[ExportChunk]
+
 func (c *xytClient) Version(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*VersionMessage, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(VersionMessage)
@@ -116,6 +329,35 @@ type XytServer interface {
 	AddSchema(context.Context, *Schema) (*emptypb.Empty, error)
 	Insert(grpc.ClientStreamingServer[Record, emptypb.Empty]) error
 	Select(*Query, grpc.ServerStreamingServer[Record]) error
+	Aggregate(context.Context, *AggregateRequest) (*AggregateResponse, error)
+	Heatmap(context.Context, *HeatmapRequest) (*HeatmapResponse, error)
+	TimeSeries(context.Context, *TimeSeriesRequest) (*TimeSeriesResponse, error)
+	// Subscribe streams every newly inserted record matching a query,
+	// optionally after first replaying the records which already match
+	Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[Record]) error
+	// Snapshot streams a point-in-time copy of the whole database, which
+	// can be fed back into Restore on this, or another, server
+	Snapshot(*emptypb.Empty, grpc.ServerStreamingServer[SnapshotChunk]) error
+	Restore(grpc.ClientStreamingServer[SnapshotChunk, emptypb.Empty]) error
+	// Truncate drops every record in a dataset older than a given time
+	Truncate(context.Context, *TruncateRequest) (*TruncateResponse, error)
+	// AlterSchema changes an existing dataset's schema, such as growing
+	// its bounds; changes which would drop records are rejected
+	AlterSchema(context.Context, *AlterSchemaRequest) (*emptypb.Empty, error)
+	// DeleteDataset drops a dataset entirely; its schema, records,
+	// rollups, indices, zones, and stats
+	DeleteDataset(context.Context, *DeleteDatasetRequest) (*emptypb.Empty, error)
+	// Delete drops every record matching a query, or, for a dry run,
+	// counts the records which would be dropped
+	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	// Zones are named polygons, stored against a dataset, which
+	// queries can then refer to by name
+	AddZone(context.Context, *Zone) (*emptypb.Empty, error)
+	ListZones(context.Context, *ListZonesRequest) (*ListZonesResponse, error)
+	DeleteZone(context.Context, *DeleteZoneRequest) (*emptypb.Empty, error)
+	// ExportGeoJSON streams the records matching a query, from a dataset
+	// with a Geo projection, as a GeoJSON FeatureCollection
+	ExportGeoJSON(*Query, grpc.ServerStreamingServer[ExportChunk]) error
 	Version(context.Context, *emptypb.Empty) (*VersionMessage, error)
 	mustEmbedUnimplementedXytServer()
 }
@@ -139,6 +381,48 @@ func (UnimplementedXytServer) Insert(grpc.ClientStreamingServer[Record, emptypb.
 func (UnimplementedXytServer) Select(*Query, grpc.ServerStreamingServer[Record]) error {
 	return status.Errorf(codes.Unimplemented, "method Select not implemented")
 }
+func (UnimplementedXytServer) Aggregate(context.Context, *AggregateRequest) (*AggregateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Aggregate not implemented")
+}
+func (UnimplementedXytServer) Heatmap(context.Context, *HeatmapRequest) (*HeatmapResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heatmap not implemented")
+}
+func (UnimplementedXytServer) TimeSeries(context.Context, *TimeSeriesRequest) (*TimeSeriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TimeSeries not implemented")
+}
+func (UnimplementedXytServer) Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[Record]) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedXytServer) Snapshot(*emptypb.Empty, grpc.ServerStreamingServer[SnapshotChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method Snapshot not implemented")
+}
+func (UnimplementedXytServer) Restore(grpc.ClientStreamingServer[SnapshotChunk, emptypb.Empty]) error {
+	return status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedXytServer) Truncate(context.Context, *TruncateRequest) (*TruncateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Truncate not implemented")
+}
+func (UnimplementedXytServer) AlterSchema(context.Context, *AlterSchemaRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AlterSchema not implemented")
+}
+func (UnimplementedXytServer) DeleteDataset(context.Context, *DeleteDatasetRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDataset not implemented")
+}
+func (UnimplementedXytServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedXytServer) AddZone(context.Context, *Zone) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddZone not implemented")
+}
+func (UnimplementedXytServer) ListZones(context.Context, *ListZonesRequest) (*ListZonesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListZones not implemented")
+}
+func (UnimplementedXytServer) DeleteZone(context.Context, *DeleteZoneRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteZone not implemented")
+}
+func (UnimplementedXytServer) ExportGeoJSON(*Query, grpc.ServerStreamingServer[ExportChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportGeoJSON not implemented")
+}
 func (UnimplementedXytServer) Version(context.Context, *emptypb.Empty) (*VersionMessage, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Version not implemented")
 }
@@ -217,6 +501,226 @@ func _Xyt_Select_Handler(srv interface{}, stream grpc.ServerStream) error {
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Xyt_SelectServer = grpc.ServerStreamingServer[Record]
 
+func _Xyt_Aggregate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AggregateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XytServer).Aggregate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Xyt_Aggregate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XytServer).Aggregate(ctx, req.(*AggregateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Xyt_Heatmap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeatmapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XytServer).Heatmap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Xyt_Heatmap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XytServer).Heatmap(ctx, req.(*HeatmapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Xyt_TimeSeries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimeSeriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XytServer).TimeSeries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Xyt_TimeSeries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XytServer).TimeSeries(ctx, req.(*TimeSeriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Xyt_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(XytServer).Subscribe(m, &grpc.GenericServerStream[SubscribeRequest, Record]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Xyt_SubscribeServer = grpc.ServerStreamingServer[Record]
+
+func _Xyt_Snapshot_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(emptypb.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(XytServer).Snapshot(m, &grpc.GenericServerStream[emptypb.Empty, SnapshotChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Xyt_SnapshotServer = grpc.ServerStreamingServer[SnapshotChunk]
+
+func _Xyt_Restore_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(XytServer).Restore(&grpc.GenericServerStream[SnapshotChunk, emptypb.Empty]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Xyt_RestoreServer = grpc.ClientStreamingServer[SnapshotChunk, emptypb.Empty]
+
+func _Xyt_Truncate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TruncateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XytServer).Truncate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Xyt_Truncate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XytServer).Truncate(ctx, req.(*TruncateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Xyt_AlterSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AlterSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XytServer).AlterSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Xyt_AlterSchema_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XytServer).AlterSchema(ctx, req.(*AlterSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Xyt_DeleteDataset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDatasetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XytServer).DeleteDataset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Xyt_DeleteDataset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XytServer).DeleteDataset(ctx, req.(*DeleteDatasetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Xyt_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XytServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Xyt_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XytServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Xyt_AddZone_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Zone)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XytServer).AddZone(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Xyt_AddZone_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XytServer).AddZone(ctx, req.(*Zone))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Xyt_ListZones_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListZonesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XytServer).ListZones(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Xyt_ListZones_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XytServer).ListZones(ctx, req.(*ListZonesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Xyt_DeleteZone_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteZoneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XytServer).DeleteZone(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Xyt_DeleteZone_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XytServer).DeleteZone(ctx, req.(*DeleteZoneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Xyt_ExportGeoJSON_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Query)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(XytServer).ExportGeoJSON(m, &grpc.GenericServerStream[Query, ExportChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Xyt_ExportGeoJSONServer = grpc.ServerStreamingServer[ExportChunk]
+
 func _Xyt_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
@@ -250,6 +754,46 @@ var Xyt_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddSchema",
 			Handler:    _Xyt_AddSchema_Handler,
 		},
+		{
+			MethodName: "Aggregate",
+			Handler:    _Xyt_Aggregate_Handler,
+		},
+		{
+			MethodName: "Heatmap",
+			Handler:    _Xyt_Heatmap_Handler,
+		},
+		{
+			MethodName: "TimeSeries",
+			Handler:    _Xyt_TimeSeries_Handler,
+		},
+		{
+			MethodName: "Truncate",
+			Handler:    _Xyt_Truncate_Handler,
+		},
+		{
+			MethodName: "AlterSchema",
+			Handler:    _Xyt_AlterSchema_Handler,
+		},
+		{
+			MethodName: "DeleteDataset",
+			Handler:    _Xyt_DeleteDataset_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Xyt_Delete_Handler,
+		},
+		{
+			MethodName: "AddZone",
+			Handler:    _Xyt_AddZone_Handler,
+		},
+		{
+			MethodName: "ListZones",
+			Handler:    _Xyt_ListZones_Handler,
+		},
+		{
+			MethodName: "DeleteZone",
+			Handler:    _Xyt_DeleteZone_Handler,
+		},
 		{
 			MethodName: "Version",
 			Handler:    _Xyt_Version_Handler,
@@ -266,6 +810,26 @@ var Xyt_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Xyt_Select_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Subscribe",
+			Handler:       _Xyt_Subscribe_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Snapshot",
+			Handler:       _Xyt_Snapshot_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Restore",
+			Handler:       _Xyt_Restore_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportGeoJSON",
+			Handler:       _Xyt_ExportGeoJSON_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "server.proto",
 }
@@ -0,0 +1,609 @@
+package xyt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/proto"
+)
+
+// snapshotMagic prefixes every snapshot, so we can fail fast when
+// handed something which isn't one
+var snapshotMagic = [4]byte{'X', 'Y', 'T', 'S'}
+
+// snapshotVersion is bumped whenever the snapshot format changes; Restore
+// will refuse to read snapshots from a version it doesn't know about.
+//
+// Version 2 added rollups, and version 3 replaced the full grid of cells
+// with just those cells which hold records, so that snapshots of sparse
+// datasets stay small. Older snapshots are still readable, with rollups
+// rebuilt from records where the snapshot predates them
+const snapshotVersion uint16 = 3
+
+// datasetSnapshot holds a point-in-time copy of a single dataset
+type datasetSnapshot struct {
+	schema  *server.Schema
+	stats   Stats
+	cells   []cellSnapshot
+	rollups []*server.RollupBucket
+}
+
+// cellSnapshot holds the records from a single location
+type cellSnapshot struct {
+	x, y    int32
+	records []*server.Record
+}
+
+// Snapshot writes a point-in-time copy of every dataset in the Database,
+// its schema, records and stats, to w.
+//
+// The format is a simple, versioned, binary format:
+//
+//	magic ("XYTS") | version (uint16) | dataset count (uint32) | datasets...
+//
+// Where each dataset is:
+//
+//	schema | record count (uint32) | total size (uint64) | fields | cell count | cells... | rollup count | rollups...
+//
+// And each cell is its X and Y location, as varints, followed by a record count and that
+// many records; only cells holding records are written. Schemas, records, and rollups are
+// length-prefixed protobuf messages, fields are length-prefixed strings, and all counts/
+// lengths within a dataset are uvarints.
+//
+// Inserts are only blocked for as long as it takes to copy references to each
+// record, rather than for the duration of the write, so snapshots can be taken
+// on a busy Database.
+func (d *Database) Snapshot(w io.Writer) (err error) {
+	snapshots := d.snapshotDatasets()
+
+	bw := bufio.NewWriter(w)
+
+	_, err = bw.Write(snapshotMagic[:])
+	if err != nil {
+		return
+	}
+
+	err = binary.Write(bw, binary.LittleEndian, snapshotVersion)
+	if err != nil {
+		return
+	}
+
+	// #nosec: G115
+	err = binary.Write(bw, binary.LittleEndian, uint32(len(snapshots)))
+	if err != nil {
+		return
+	}
+
+	for _, ds := range snapshots {
+		err = ds.write(bw)
+		if err != nil {
+			return
+		}
+	}
+
+	return bw.Flush()
+}
+
+func (d *Database) snapshotDatasets() (snapshots []datasetSnapshot) {
+	d.mutx.RLock()
+	defer d.mutx.RUnlock()
+
+	snapshots = make([]datasetSnapshot, 0, len(d.schemata))
+	for name, schema := range d.schemata {
+		// Each dataset is consistent within itself, though inserts
+		// into other datasets may carry on as this one is copied
+		d.locks[name].RLock()
+
+		ds := datasetSnapshot{
+			schema: proto.Clone(schema).(*server.Schema),
+		}
+
+		ds.stats = *d.stats[name].clone()
+
+		d.data[name].each(schema.XMin, schema.XMax, schema.YMin, schema.YMax, func(x, y int32, c *cell) {
+			// Copying records out, rather than referencing the cell, means that
+			// sort-on-insert datasets can't reorder records mid-snapshot
+			if records := c.records(name, x, y); len(records) > 0 {
+				ds.cells = append(ds.cells, cellSnapshot{x: x, y: y, records: records})
+			}
+
+			ds.rollups = append(ds.rollups, c.rollupBuckets(name, x, y)...)
+		})
+
+		d.locks[name].RUnlock()
+
+		snapshots = append(snapshots, ds)
+	}
+
+	return
+}
+
+func (ds datasetSnapshot) write(w *bufio.Writer) (err error) {
+	err = writeMessage(w, ds.schema)
+	if err != nil {
+		return
+	}
+
+	err = binary.Write(w, binary.LittleEndian, ds.stats.RecordCount)
+	if err != nil {
+		return
+	}
+
+	err = binary.Write(w, binary.LittleEndian, ds.stats.TotalSize)
+	if err != nil {
+		return
+	}
+
+	writeUvarint(w, len(ds.stats.Fields))
+	for _, f := range ds.stats.Fields {
+		writeUvarint(w, len(f))
+
+		_, err = w.WriteString(f)
+		if err != nil {
+			return
+		}
+	}
+
+	writeUvarint(w, len(ds.cells))
+	for _, c := range ds.cells {
+		writeVarint(w, c.x)
+		writeVarint(w, c.y)
+		writeUvarint(w, len(c.records))
+
+		for _, r := range c.records {
+			err = writeMessage(w, r)
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	writeUvarint(w, len(ds.rollups))
+	for _, b := range ds.rollups {
+		err = writeMessage(w, b)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Restore reads a snapshot, as written by Snapshot, from r and adds every
+// dataset within it to the Database.
+//
+// Restore will refuse to overwrite datasets which already exist; the whole
+// snapshot is read and checked before anything is added, so a failed Restore
+// leaves the Database untouched.
+//
+// If the Database has a WAL then every restored schema, record and rollup is
+// logged, so that restored data survives restarts
+func (d *Database) Restore(r io.Reader) (err error) {
+	br := bufio.NewReader(r)
+
+	var magic [4]byte
+
+	_, err = io.ReadFull(br, magic[:])
+	if err != nil {
+		return snapshotReadError(err)
+	}
+
+	if magic != snapshotMagic {
+		return InvalidSnapshotError
+	}
+
+	var version uint16
+
+	err = binary.Read(br, binary.LittleEndian, &version)
+	if err != nil {
+		return snapshotReadError(err)
+	}
+
+	if version == 0 || version > snapshotVersion {
+		return UnsupportedSnapshotVersionError{version: version}
+	}
+
+	var count uint32
+
+	err = binary.Read(br, binary.LittleEndian, &count)
+	if err != nil {
+		return snapshotReadError(err)
+	}
+
+	snapshots := make([]datasetSnapshot, count)
+	for i := range snapshots {
+		snapshots[i], err = readDatasetSnapshot(br, version)
+		if err != nil {
+			return snapshotReadError(err)
+		}
+
+		err = d.validateSchema(snapshots[i].schema)
+		if err != nil {
+			return
+		}
+
+		for _, cs := range snapshots[i].cells {
+			err = validateCellSnapshot(snapshots[i].schema, cs)
+			if err != nil {
+				return
+			}
+		}
+
+		for _, b := range snapshots[i].rollups {
+			if b.Dataset != snapshots[i].schema.Dataset {
+				return InvalidRollupBucketError
+			}
+
+			err = validateRollupBucket(snapshots[i].schema, b)
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	d.mutx.Lock()
+	defer d.mutx.Unlock()
+
+	seen := make(map[string]bool)
+	for _, ds := range snapshots {
+		if _, ok := d.data[ds.schema.Dataset]; ok || seen[ds.schema.Dataset] {
+			return DuplicateDatasetError
+		}
+
+		seen[ds.schema.Dataset] = true
+	}
+
+	for _, ds := range snapshots {
+		err = d.restoreDataset(ds)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// validateCellSnapshot ensures cs, and every record within it, fits within
+// schema, so that nothing from a damaged, or hand-crafted, snapshot is
+// restored only to break queries later on
+func validateCellSnapshot(schema *server.Schema, cs cellSnapshot) error {
+	if cs.x < schema.XMin || cs.x >= schema.XMax || cs.y < schema.YMin || cs.y >= schema.YMax {
+		return InvalidSnapshotError
+	}
+
+	zMin, zMax := zBounds(schema)
+
+	for _, r := range cs.records {
+		switch {
+		case r.Meta == nil, r.Meta.When == nil, r.Meta.When.AsTime().IsZero(),
+			r.Name == "",
+			r.Dataset != schema.Dataset,
+			r.X != cs.x, r.Y != cs.y,
+			r.Z < zMin, r.Z >= zMax,
+
+			// Snapshots from before T was wrapped on insert
+			// may still hold a full turn
+			r.T < 0, r.T > thetaBuckets(schema),
+			r.Theta != nil && !(*r.Theta >= 0 && *r.Theta <= 360):
+
+			return InvalidSnapshotError
+		}
+	}
+
+	return nil
+}
+
+// restoreDataset must be called with d.mutx held for writing
+func (d *Database) restoreDataset(ds datasetSnapshot) (err error) {
+	name := ds.schema.Dataset
+
+	if d.wal != nil {
+		err = d.wal.appendSchema(ds.schema)
+		if err != nil {
+			return
+		}
+	}
+
+	stats := newStats()
+	data := newGrid(ds.schema)
+	tiles := newQuadtree(ds.schema)
+
+	d.addDataset(ds.schema, data, tiles, stats)
+
+	// Rebuild stats and fields from the records themselves, rather than
+	// from the snapshotted stats; older versions of xyt updated stats in
+	// the background, and so may have snapshotted them before they'd
+	// caught up with the records they describe.
+	//
+	// Cells and indices aren't part of the snapshot format, so they get
+	// rebuilt here too
+	for _, cs := range ds.cells {
+		c := data.allocate(cs.x, cs.y)
+
+		for _, r := range cs.records {
+			// Snapshots from before T was wrapped on insert
+			// may still hold a full turn
+			r.T = recordTheta(ds.schema, r)
+
+			d.fields[name][r.Name] = nil
+			stats.addRecord(r)
+
+			tiles.add(cs.x, cs.y, unixNano(r.Meta.When.AsTime()))
+
+			c.insert(r, ds.schema)
+			c.rollup(r, ds.schema.Rollups)
+
+			d.indexRecord(ds.schema, r)
+
+			if d.wal != nil {
+				err = d.wal.appendRecord(r)
+				if err != nil {
+					return
+				}
+			}
+		}
+	}
+
+	// Rollups rebuilt from records above only cover the records the
+	// snapshot still held, so replace them with the real thing
+	for _, b := range ds.rollups {
+		err = d.setRollup(b)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func readDatasetSnapshot(r *bufio.Reader, version uint16) (ds datasetSnapshot, err error) {
+	ds.schema = new(server.Schema)
+
+	err = readMessage(r, ds.schema)
+	if err != nil {
+		return
+	}
+
+	err = binary.Read(r, binary.LittleEndian, &ds.stats.RecordCount)
+	if err != nil {
+		return
+	}
+
+	err = binary.Read(r, binary.LittleEndian, &ds.stats.TotalSize)
+	if err != nil {
+		return
+	}
+
+	fieldCount, err := binary.ReadUvarint(r)
+	if err != nil {
+		return
+	}
+
+	ds.stats.Fields = make([]string, 0, min(fieldCount, 1024))
+	for i := uint64(0); i < fieldCount; i++ {
+		var b []byte
+
+		b, err = readBytes(r)
+		if err != nil {
+			return
+		}
+
+		ds.stats.Fields = append(ds.stats.Fields, string(b))
+	}
+
+	if version < 3 {
+		ds.cells, err = readGridCells(r, ds.schema)
+	} else {
+		ds.cells, err = readCells(r, ds.schema)
+	}
+
+	if err != nil || version < 2 {
+		return
+	}
+
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return
+	}
+
+	ds.rollups = make([]*server.RollupBucket, 0, min(n, 1024))
+	for i := uint64(0); i < n; i++ {
+		b := new(server.RollupBucket)
+
+		err = readMessage(r, b)
+		if err != nil {
+			return
+		}
+
+		ds.rollups = append(ds.rollups, b)
+	}
+
+	return
+}
+
+// readCells reads cells as written by version 3 snapshots onwards
+func readCells(r *bufio.Reader, schema *server.Schema) (cells []cellSnapshot, err error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return
+	}
+
+	cells = make([]cellSnapshot, 0, min(n, 1024))
+	for i := uint64(0); i < n; i++ {
+		var c cellSnapshot
+
+		c.x, err = readVarint(r)
+		if err != nil {
+			return
+		}
+
+		c.y, err = readVarint(r)
+		if err != nil {
+			return
+		}
+
+		// Make sure the cell fits the schema, otherwise we'll end
+		// up with a dataset which panics on insert or query
+		if c.x < schema.XMin || c.x >= schema.XMax || c.y < schema.YMin || c.y >= schema.YMax {
+			return nil, InvalidSnapshotError
+		}
+
+		c.records, err = readRecords(r)
+		if err != nil {
+			return
+		}
+
+		cells = append(cells, c)
+	}
+
+	return
+}
+
+// readGridCells reads cells as written by snapshots before version 3,
+// which held every location within the schema's bounds
+func readGridCells(r *bufio.Reader, schema *server.Schema) (cells []cellSnapshot, err error) {
+	var size [2]uint32
+
+	err = binary.Read(r, binary.LittleEndian, &size)
+	if err != nil {
+		return
+	}
+
+	// Make sure the grid we're being handed is the grid the schema describes,
+	// otherwise we'll end up with a dataset which panics on insert or query
+	// #nosec: G115
+	if int64(size[0]) != int64(schema.XMax)-int64(schema.XMin) ||
+		int64(size[1]) != int64(schema.YMax)-int64(schema.YMin) {
+		return nil, InvalidSnapshotError
+	}
+
+	for xi := range size[0] {
+		for yi := range size[1] {
+			var records []*server.Record
+
+			records, err = readRecords(r)
+			if err != nil {
+				return
+			}
+
+			if len(records) > 0 {
+				// #nosec: G115
+				cells = append(cells, cellSnapshot{x: int32(xi) + schema.XMin, y: int32(yi) + schema.YMin, records: records})
+			}
+		}
+	}
+
+	return
+}
+
+// readRecords reads a record count, followed by that many records
+func readRecords(r *bufio.Reader) (records []*server.Record, err error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return
+	}
+
+	records = make([]*server.Record, 0, min(n, 1024))
+	for i := uint64(0); i < n; i++ {
+		record := new(server.Record)
+
+		err = readMessage(r, record)
+		if err != nil {
+			return
+		}
+
+		records = append(records, record)
+	}
+
+	return
+}
+
+func writeVarint(w *bufio.Writer, i int32) {
+	var b [binary.MaxVarintLen32]byte
+
+	n := binary.PutVarint(b[:], int64(i))
+
+	// As per writeUvarint, errors here are sticky
+	_, _ = w.Write(b[:n])
+}
+
+func readVarint(r *bufio.Reader) (i int32, err error) {
+	v, err := binary.ReadVarint(r)
+	if err != nil {
+		return
+	}
+
+	if v < math.MinInt32 || v > math.MaxInt32 {
+		return 0, InvalidSnapshotError
+	}
+
+	return int32(v), nil
+}
+
+func writeUvarint(w *bufio.Writer, i int) {
+	var b [binary.MaxVarintLen64]byte
+
+	// #nosec: G115
+	n := binary.PutUvarint(b[:], uint64(i))
+
+	// Errors on a bufio.Writer are sticky, and are returned
+	// from the next call to Write or Flush, so we can skip
+	// checking them here
+	_, _ = w.Write(b[:n])
+}
+
+func writeMessage(w *bufio.Writer, m proto.Message) (err error) {
+	b, err := proto.Marshal(m)
+	if err != nil {
+		return
+	}
+
+	writeUvarint(w, len(b))
+
+	_, err = w.Write(b)
+
+	return
+}
+
+// maxSnapshotItemSize protects us from allocating silly amounts of memory
+// when reading a corrupt snapshot
+const maxSnapshotItemSize = 64 << 20
+
+func readBytes(r *bufio.Reader) (b []byte, err error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return
+	}
+
+	if n > maxSnapshotItemSize {
+		return nil, InvalidSnapshotError
+	}
+
+	b = make([]byte, n)
+	_, err = io.ReadFull(r, b)
+
+	return
+}
+
+func readMessage(r *bufio.Reader, m proto.Message) (err error) {
+	b, err := readBytes(r)
+	if err != nil {
+		return
+	}
+
+	return proto.Unmarshal(b, m)
+}
+
+// snapshotReadError turns the various flavours of EOF into
+// something a little more descriptive
+func snapshotReadError(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return errors.Join(InvalidSnapshotError, err)
+	}
+
+	return err
+}
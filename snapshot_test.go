@@ -0,0 +1,333 @@
+package xyt
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestDatabase_Snapshot(t *testing.T) {
+	d := snapshotTestDatabase(t)
+
+	buf := new(bytes.Buffer)
+
+	err := d.Snapshot(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = restored.Restore(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ds := range []string{"site-a", "site-b"} {
+		t.Run(ds, func(t *testing.T) {
+			expect, err := d.RetrieveRecords(&server.Query{Dataset: ds})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rcvd, err := restored.RetrieveRecords(&server.Query{Dataset: ds})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(expect) != len(rcvd) {
+				t.Fatalf("expected %d records, received %d", len(expect), len(rcvd))
+			}
+
+			for i := range expect {
+				if expect[i].X != rcvd[i].X || expect[i].Y != rcvd[i].Y || expect[i].Value != rcvd[i].Value {
+					t.Errorf("record %d: expected %v, received %v", i, expect[i], rcvd[i])
+				}
+			}
+
+			if d.Datasets()[ds].XMax != restored.Datasets()[ds].XMax {
+				t.Errorf("expected XMax %d, received %d", d.Datasets()[ds].XMax, restored.Datasets()[ds].XMax)
+			}
+
+			// Records can now be inserted into the restored dataset as normal
+			err = restored.InsertRecord(&server.Record{
+				Meta:    &server.Metadata{When: timestamppb.Now()},
+				Dataset: ds,
+				Name:    "temperature",
+				X:       1,
+				Y:       1,
+			})
+			if err != nil {
+				t.Errorf("unexpected error %#v", err)
+			}
+		})
+	}
+}
+
+func TestDatabase_Restore(t *testing.T) {
+	d := snapshotTestDatabase(t)
+
+	buf := new(bytes.Buffer)
+
+	err := d.Snapshot(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := buf.Bytes()
+
+	badVersion := bytes.Clone(valid)
+	badVersion[4] = 0xff
+
+	for _, test := range []struct {
+		name        string
+		snapshot    []byte
+		expectError error
+	}{
+		{"Empty snapshots fail", []byte{}, InvalidSnapshotError},
+		{"Non-snapshots fail", []byte("hello, world!"), InvalidSnapshotError},
+		{"Truncated snapshots fail", valid[:len(valid)-10], InvalidSnapshotError},
+		{"Unknown versions fail", badVersion, UnsupportedSnapshotVersionError{version: 0xff}},
+		{"Restoring over existing datasets fails", valid, DuplicateDatasetError},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := d.Restore(bytes.NewReader(test.snapshot))
+			if !errors.Is(err, test.expectError) {
+				t.Errorf("expected %#v, received %#v", test.expectError, err)
+			}
+		})
+	}
+}
+
+func TestDatabase_Restore_Records(t *testing.T) {
+	schema := &server.Schema{Dataset: "site-a", XMax: 10, YMax: 10, ZMax: 2}
+
+	for _, test := range []struct {
+		name   string
+		x, y   int32
+		mangle func(*server.Record)
+	}{
+		{"Missing metadata fails", 1, 2, func(r *server.Record) { r.Meta = nil }},
+		{"Missing when fails", 1, 2, func(r *server.Record) { r.Meta.When = nil }},
+		{"Missing names fail", 1, 2, func(r *server.Record) { r.Name = "" }},
+		{"Other datasets fail", 1, 2, func(r *server.Record) { r.Dataset = "site-b" }},
+		{"Records from other cells fail", 1, 2, func(r *server.Record) { r.X = 3 }},
+		{"Cells outside of the schema fail", 11, 2, func(r *server.Record) { r.X = 11 }},
+		{"Out of bounds Z fails", 1, 2, func(r *server.Record) { r.Z = 2 }},
+		{"Out of bounds T fails", 1, 2, func(r *server.Record) { r.T = 361 }},
+		{"Out of bounds Theta fails", 1, 2, func(r *server.Record) { r.Theta = proto.Float64(-1) }},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			r := &server.Record{
+				Meta:    &server.Metadata{When: timestamppb.Now()},
+				Dataset: "site-a",
+				Name:    "temperature",
+				X:       1,
+				Y:       2,
+			}
+
+			test.mangle(r)
+
+			buf := new(bytes.Buffer)
+			w := bufio.NewWriter(buf)
+
+			_, _ = w.Write(snapshotMagic[:])
+			_ = binary.Write(w, binary.LittleEndian, snapshotVersion)
+			_ = binary.Write(w, binary.LittleEndian, uint32(1))
+
+			err := datasetSnapshot{
+				schema: schema,
+				cells:  []cellSnapshot{{x: test.x, y: test.y, records: []*server.Record{r}}},
+			}.write(w)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = w.Flush()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			d, err := New()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = d.Restore(buf)
+			if err != InvalidSnapshotError {
+				t.Errorf("expected %#v, received %#v", InvalidSnapshotError, err)
+			}
+
+			if n := len(d.Datasets()); n != 0 {
+				t.Errorf("expected nothing to be restored, received %d datasets", n)
+			}
+		})
+	}
+}
+
+func TestDatabase_Restore_Stats(t *testing.T) {
+	records := make([]*server.Record, 0, 10)
+	for i := range 10 {
+		records = append(records, &server.Record{
+			Meta:    &server.Metadata{When: timestamppb.New(time.Unix(int64(i), 0))},
+			Dataset: "site-a",
+			Name:    []string{"temperature", "humidity"}[i%2],
+			X:       1,
+			Y:       2,
+		})
+	}
+
+	buf := new(bytes.Buffer)
+	w := bufio.NewWriter(buf)
+
+	_, _ = w.Write(snapshotMagic[:])
+	_ = binary.Write(w, binary.LittleEndian, snapshotVersion)
+	_ = binary.Write(w, binary.LittleEndian, uint32(1))
+
+	// Older versions of xyt could snapshot stats before they'd
+	// caught up with the records, leaving them zeroed here
+	err := datasetSnapshot{
+		schema: &server.Schema{Dataset: "site-a", XMax: 10, YMax: 10},
+		cells:  []cellSnapshot{{x: 1, y: 2, records: records}},
+	}.write(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = w.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.Restore(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := d.Stats()["site-a"]
+	if stats.RecordCount != 10 {
+		t.Errorf("expected RecordCount 10, received %d", stats.RecordCount)
+	}
+
+	if stats.TotalSize != 10*recordSize {
+		t.Errorf("expected TotalSize %d, received %d", 10*recordSize, stats.TotalSize)
+	}
+
+	if expect := []string{"temperature", "humidity"}; !slices.Equal(expect, stats.Fields) {
+		t.Errorf("expected %v, received %v", expect, stats.Fields)
+	}
+}
+
+// snapshotTestDatabase returns a database holding two datasets,
+// site-a and site-b, each with the same 50 records
+func snapshotTestDatabase(t *testing.T) *Database {
+	t.Helper()
+
+	var records []*server.Record
+
+	ts := time.Now()
+	for i := int32(0); i < 50; i++ {
+		for _, ds := range []string{"site-a", "site-b"} {
+			records = append(records, &server.Record{
+				Meta: &server.Metadata{
+					When:   timestamppb.New(ts.Add(time.Duration(i) * time.Second)),
+					Labels: map[string]string{"robot": "robo-001"},
+				},
+				Dataset: ds,
+				Name:    "temperature",
+				Value:   float64(i),
+				X:       i % 5,
+				Y:       i % 10,
+				T:       i,
+			})
+		}
+	}
+
+	return testDatabase(t, nil, []*server.Schema{
+		{Dataset: "site-a", XMax: 10, YMax: 10, SortOnInsert: true},
+		{Dataset: "site-b", XMax: 5, YMax: 20, LazyInitialAllocate: true},
+	}, records)
+}
+
+func TestDatabase_Restore_Version2(t *testing.T) {
+	schema := &server.Schema{Dataset: "site-a", XMin: -1, XMax: 1, YMin: 5, YMax: 7}
+
+	buf := new(bytes.Buffer)
+	w := bufio.NewWriter(buf)
+
+	// Version 2 snapshots hold a record count for every
+	// location, whether or not that location holds records
+	_, _ = w.Write(snapshotMagic[:])
+	_ = binary.Write(w, binary.LittleEndian, uint16(2))
+	_ = binary.Write(w, binary.LittleEndian, uint32(1))
+	_ = writeMessage(w, schema)
+	_ = binary.Write(w, binary.LittleEndian, uint32(1))
+	_ = binary.Write(w, binary.LittleEndian, uint64(0))
+	writeUvarint(w, 0)
+	_ = binary.Write(w, binary.LittleEndian, [2]uint32{2, 2})
+
+	for xi := range 2 {
+		for yi := range 2 {
+			if xi != 1 || yi != 0 {
+				writeUvarint(w, 0)
+
+				continue
+			}
+
+			writeUvarint(w, 1)
+			_ = writeMessage(w, &server.Record{
+				Meta:    &server.Metadata{When: timestamppb.Now()},
+				Dataset: "site-a",
+				Name:    "temperature",
+				X:       0,
+				Y:       5,
+			})
+		}
+	}
+
+	writeUvarint(w, 0)
+
+	err := w.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.Restore(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := d.RetrieveRecords(&server.Query{
+		Dataset: "site-a",
+		X:       &server.Query_XValue{XValue: 0},
+		Y:       &server.Query_YValue{YValue: 5},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 1 {
+		t.Errorf("expected 1 record, received %d", len(records))
+	}
+}
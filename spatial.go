@@ -0,0 +1,175 @@
+package xyt
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/xyt-db/xyt/server"
+)
+
+// locations passes each location within the (exclusive) ranges passed
+// which might hold matching records to loc, in X then Y order, along with
+// a function which visits that location's matches.
+//
+// This lets spatial queries order and pick locations without caring
+// whether matches come from records, rollups, or an index
+type locations func(xMin, xMax, yMin, yMax int32, loc func(x, y int32, visit func(visitFunc)))
+
+// inPolygons wraps each, skipping locations which fall
+// outside of every one of the matcher's polygons
+func (m matcher) inPolygons(each locations) locations {
+	return func(xMin, xMax, yMin, yMax int32, loc func(x, y int32, visit func(visitFunc))) {
+		each(xMin, xMax, yMin, yMax, func(x, y int32, visit func(visitFunc)) {
+			if inPolygons(m.polygons, x, y) {
+				loc(x, y, visit)
+			}
+		})
+	}
+}
+
+// A candidate is a location picked out by a spatial query
+type candidate struct {
+	x, y     int32
+	distance float64
+	visit    func(visitFunc)
+
+	// matches holds buffered matches, for searches
+	// which need to look before they leap
+	matches []match
+}
+
+type match struct {
+	r      *server.Record
+	rolled *aggregator
+}
+
+// distance returns the straight line distance between two locations
+func distance(x0, y0, x1, y1 int32) float64 {
+	return math.Hypot(float64(x1)-float64(x0), float64(y1)-float64(y0))
+}
+
+// clampInt32 converts f to an int32, clamping it to
+// the range of an int32 rather than overflowing
+func clampInt32(f float64) int32 {
+	return int32(min(max(f, math.MinInt32), math.MaxInt32))
+}
+
+// bounds returns the (exclusive) ranges of the square around (x,y) which
+// holds every location within radius of it, clamped to the matcher's ranges
+func (m matcher) bounds(x, y int32, radius float64) (xMin, xMax, yMin, yMax int32) {
+	clamp := func(centre int32, lower, upper int32) (int32, int32) {
+		start := clampInt32(math.Ceil(float64(centre) - radius))
+		end := clampInt32(math.Floor(float64(centre)+radius) + 1)
+
+		return clampRange(start, end, lower, upper)
+	}
+
+	xMin, xMax = clamp(x, m.xMin, m.xMax)
+	yMin, yMax = clamp(y, m.yMin, m.yMax)
+
+	return
+}
+
+// walkWithin passes the matches from every location within c to fn,
+// nearest to c's centre first, and in X then Y order where locations
+// are equally distant
+func (m matcher) walkWithin(c *server.Circle, each locations, fn visitFunc) {
+	var candidates []candidate
+
+	xMin, xMax, yMin, yMax := m.bounds(c.X, c.Y, c.Radius)
+	each(xMin, xMax, yMin, yMax, func(x, y int32, visit func(visitFunc)) {
+		if d := distance(c.X, c.Y, x, y); d <= c.Radius {
+			candidates = append(candidates, candidate{x: x, y: y, distance: d, visit: visit})
+		}
+	})
+
+	sortCandidates(candidates)
+
+	for _, c := range candidates {
+		c.visit(fn)
+	}
+}
+
+// walkNearest passes the matches from the n.K closest locations to n
+// which hold any matches at all to fn, ordered as per walkWithin.
+//
+// The search starts close to n and widens until it has found enough
+// locations, runs out of dataset, or reaches n.MaxDistance
+func (m matcher) walkNearest(n *server.Nearest, each locations, fn visitFunc) {
+	limit := n.MaxDistance
+	if limit == 0 {
+		limit = math.Inf(1)
+	}
+
+	seen := make(map[cellKey]bool)
+
+	var candidates []candidate
+
+	for radius := float64(tileSize); ; radius *= 2 {
+		radius = min(radius, limit)
+
+		xMin, xMax, yMin, yMax := m.bounds(n.X, n.Y, radius)
+		each(xMin, xMax, yMin, yMax, func(x, y int32, visit func(visitFunc)) {
+			k := cellKey{x, y}
+			if seen[k] {
+				return
+			}
+
+			d := distance(n.X, n.Y, x, y)
+			if d > limit {
+				return
+			}
+
+			seen[k] = true
+
+			c := candidate{x: x, y: y, distance: d}
+			visit(func(r *server.Record, rolled *aggregator) {
+				c.matches = append(c.matches, match{r, rolled})
+			})
+
+			if len(c.matches) > 0 {
+				candidates = append(candidates, c)
+			}
+		})
+
+		// Only locations within radius are certain to be closer than
+		// anything we've yet to look at, since the square we've just
+		// searched reaches further than radius in places
+		var found uint32
+		for _, c := range candidates {
+			if c.distance <= radius {
+				found++
+			}
+		}
+
+		covered := xMin <= m.xMin && xMax >= m.xMax && yMin <= m.yMin && yMax >= m.yMax
+		if found >= n.K || covered || radius >= limit {
+			break
+		}
+	}
+
+	sortCandidates(candidates)
+
+	for _, c := range candidates[:min(len(candidates), int(n.K))] {
+		for _, b := range c.matches {
+			fn(b.r, b.rolled)
+		}
+	}
+}
+
+// sortCandidates sorts candidates by distance, keeping equally distant
+// candidates in X then Y order
+func sortCandidates(candidates []candidate) {
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		if c := cmp.Compare(a.distance, b.distance); c != 0 {
+			return c
+		}
+
+		if c := cmp.Compare(a.x, b.x); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.y, b.y)
+	})
+}
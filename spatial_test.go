@@ -0,0 +1,140 @@
+package xyt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// spatialDatabase returns a database holding temperature readings along
+// the X axis every 5 locations, and a single, old, wifi reading well away
+// from everything else
+func spatialDatabase(t *testing.T) *Database {
+	t.Helper()
+
+	var records []*server.Record
+	for x := int32(-50); x < 50; x += 5 {
+		records = append(records, &server.Record{
+			Meta:    &server.Metadata{When: timestamppb.New(time.Unix(1000, 0))},
+			Dataset: "site-a",
+			Name:    "temperature",
+			X:       x,
+			T:       90,
+		})
+	}
+
+	records = append(records, &server.Record{
+		Meta:    &server.Metadata{When: timestamppb.New(time.Unix(10, 0))},
+		Dataset: "site-a",
+		Name:    "wifi",
+		X:       40,
+		Y:       40,
+		T:       90,
+	})
+
+	return testDatabase(t, nil, []*server.Schema{{
+		Dataset:             "site-a",
+		XMin:                -50,
+		XMax:                50,
+		YMin:                -50,
+		YMax:                50,
+		SortOnInsert:        true,
+		LazyInitialAllocate: true,
+	}}, records)
+}
+
+func TestDatabase_RetrieveRecords_Spatial(t *testing.T) {
+	d := spatialDatabase(t)
+
+	since := func(s int64) *server.Query_TimeRange {
+		return &server.Query_TimeRange{TimeRange: &server.TimeRange{
+			Start: timestamppb.New(time.Unix(s, 0)),
+			End:   timestamppb.New(time.Unix(2000, 0)),
+		}}
+	}
+
+	for _, test := range []struct {
+		name        string
+		query       *server.Query
+		expectX     []int32
+		expectError error
+	}{
+		{"Within returns nearby locations, nearest first", &server.Query{Within: &server.Circle{X: 1, Radius: 7}}, []int32{0, 5, -5}, nil},
+		{"Within includes locations exactly radius away", &server.Query{Within: &server.Circle{X: 0, Radius: 5}}, []int32{0, -5, 5}, nil},
+		{"Within combines with other limits", &server.Query{Within: &server.Circle{X: 1, Radius: 7}, X: &server.Query_XRange{XRange: &server.QueryRange{Start: 1, End: 50}}}, []int32{5}, nil},
+		{"Within a radius of zero is a single location", &server.Query{Within: &server.Circle{X: 10, Y: 0}}, []int32{10}, nil},
+		{"Within off the edge of the dataset", &server.Query{Within: &server.Circle{X: -60, Radius: 11}}, []int32{-50}, nil},
+		{"Nearest returns the closest locations holding matches", &server.Query{Nearest: &server.Nearest{X: 12, Y: 1, K: 2}}, []int32{10, 15}, nil},
+		{"Nearest searches as far as it has to", &server.Query{Nearest: &server.Nearest{X: 0, Y: 0, K: 1}, Names: []string{"wifi"}}, []int32{40}, nil},
+		{"Nearest respects time ranges", &server.Query{Nearest: &server.Nearest{X: 0, Y: 0, K: 1}, Names: []string{"wifi"}, Time: since(100)}, nil, nil},
+		{"Nearest respects max distance", &server.Query{Nearest: &server.Nearest{X: 0, Y: 0, K: 1, MaxDistance: 50}, Names: []string{"wifi"}}, nil, nil},
+		{"Nearest returns what it can find", &server.Query{Nearest: &server.Nearest{X: 0, Y: 0, K: 100}}, nil, nil},
+		{"Negative radii fail", &server.Query{Within: &server.Circle{Radius: -1}}, nil, InvalidRadiusError},
+		{"Nearest nothing fails", &server.Query{Nearest: &server.Nearest{}}, nil, InvalidNearestError},
+		{"Within and nearest together fail", &server.Query{Within: &server.Circle{}, Nearest: &server.Nearest{K: 1}}, nil, ConflictingSpatialQueryError},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			test.query.Dataset = "site-a"
+
+			records, err := d.RetrieveRecords(test.query)
+			if err != test.expectError {
+				t.Fatalf("expected %#v, received %#v", test.expectError, err)
+			}
+
+			// A catch-all, so a nearest search with a huge k
+			// should find every record in the dataset
+			if test.query.Nearest != nil && test.query.Nearest.K == 100 {
+				if len(records) != 21 {
+					t.Errorf("expected 21 records, received %d", len(records))
+				}
+
+				return
+			}
+
+			if len(test.expectX) != len(records) {
+				t.Fatalf("expected %d records, received %d", len(test.expectX), len(records))
+			}
+
+			for i, r := range records {
+				if test.expectX[i] != r.X {
+					t.Errorf("record %d: expected X %d, received %d", i, test.expectX[i], r.X)
+				}
+			}
+		})
+	}
+}
+
+func TestDatabase_Subscribe_Spatial(t *testing.T) {
+	d := spatialDatabase(t)
+
+	_, err := d.Subscribe(&server.SubscribeRequest{Query: &server.Query{
+		Dataset: "site-a",
+		Nearest: &server.Nearest{K: 1},
+	}})
+	if err != NearestSubscriptionError {
+		t.Errorf("expected NearestSubscriptionError, received %#v", err)
+	}
+
+	s, err := d.Subscribe(&server.SubscribeRequest{Query: &server.Query{
+		Dataset: "site-a",
+		Within:  &server.Circle{X: 0, Y: 0, Radius: 3},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer s.Close()
+
+	for _, r := range []*server.Record{
+		{Dataset: "site-a", Name: "temperature", X: 3, Y: 3},
+		{Dataset: "site-a", Name: "temperature", X: 0, Y: 3},
+	} {
+		r.Meta = &server.Metadata{When: timestamppb.Now()}
+
+		if s.matches(r) != (r.X == 0) {
+			t.Errorf("(%d,%d): unexpected match result", r.X, r.Y)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package xyt
 
 import (
+	"slices"
 	"sync"
 	"unsafe"
 
@@ -17,6 +18,9 @@ type Stats struct {
 	Fields      []string
 }
 
+// recordSize is our best reckoning of the size of a single record
+const recordSize = uint64(unsafe.Sizeof(server.Record{})) + uint64(unsafe.Sizeof(server.Metadata{}))
+
 func newStats() *Stats {
 	return &Stats{
 		locker:      new(sync.Mutex),
@@ -25,12 +29,25 @@ func newStats() *Stats {
 	}
 }
 
+// clone returns a copy of s, which doesn't change as s does
+func (s *Stats) clone() *Stats {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	return &Stats{
+		locker:      new(sync.Mutex),
+		RecordCount: s.RecordCount,
+		TotalSize:   s.TotalSize,
+		Fields:      slices.Clone(s.Fields),
+	}
+}
+
 func (s *Stats) addRecord(r *server.Record) {
 	s.locker.Lock()
 	defer s.locker.Unlock()
 
 	s.RecordCount++
-	s.TotalSize += (uint64(unsafe.Sizeof(*r)) + uint64(unsafe.Sizeof(*r.Meta)))
+	s.TotalSize += recordSize
 
 	for _, f := range s.Fields {
 		if r.Name == f {
@@ -41,3 +58,12 @@ func (s *Stats) addRecord(r *server.Record) {
 	// If we get here, update field names
 	s.Fields = append(s.Fields, r.Name)
 }
+
+// removeRecords updates stats to account for n records being dropped
+func (s *Stats) removeRecords(n uint64) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	s.RecordCount -= uint32(n) // #nosec: G115
+	s.TotalSize -= n * recordSize
+}
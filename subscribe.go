@@ -0,0 +1,225 @@
+package xyt
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/xyt-db/xyt/server"
+)
+
+// DefaultSubscriberBufferSize is the number of records buffered for a
+// subscriber when a SubscribeRequest doesn't set BufferSize
+const DefaultSubscriberBufferSize = 1_024
+
+// A Subscription receives every newly inserted record matching a query,
+// as returned by Database.Subscribe.
+//
+// Records are buffered per subscription, and inserts never wait on a
+// subscriber; where a subscriber falls far enough behind to fill its
+// buffer, records are either dropped or the subscription ended, as per
+// the SlowSubscriberPolicy it was created with
+type Subscription struct {
+	d       *Database
+	dataset string
+
+	// query is kept so that m can be rebuilt
+	// should the dataset's schema change
+	query *server.Query
+
+	m                    matcher
+	indexKey, indexValue string
+	policy               server.SlowSubscriberPolicy
+
+	// replay holds historical records, which are
+	// passed on before anything from records
+	replay  []*server.Record
+	records chan *server.Record
+
+	done    chan struct{}
+	endOnce sync.Once
+	err     error
+
+	dropped atomic.Uint64
+}
+
+// Subscribe validates the query in sr and returns a Subscription to every
+// record subsequently inserted which matches it.
+//
+// Where sr.Replay is set, every record matching the query at the point of
+// subscribing is passed on first; no record is ever missed, or passed on twice,
+// between the end of the replay and the start of new records.
+//
+// Callers must Close the Subscription when done with it
+func (d *Database) Subscribe(sr *server.SubscribeRequest) (s *Subscription, err error) {
+	if sr == nil {
+		return nil, MissingDatasetError
+	}
+
+	if _, ok := server.SlowSubscriberPolicy_name[int32(sr.SlowPolicy)]; !ok {
+		return nil, UnknownSlowSubscriberPolicyError
+	}
+
+	bufferSize := int(sr.BufferSize)
+	if bufferSize == 0 {
+		bufferSize = DefaultSubscriberBufferSize
+	}
+
+	// Holding the lock across both the replay and registering the
+	// subscription means no insert can sneak in between the two
+	unlock := d.lock(sr.Query.GetDataset())
+	defer unlock()
+
+	err = d.validateQuery(sr.Query)
+	if err != nil {
+		return
+	}
+
+	q := sr.Query
+
+	// The nearest locations can change with every insert, which
+	// doesn't map onto a stream of individual records at all
+	if q.Nearest != nil {
+		return nil, NearestSubscriptionError
+	}
+
+	s = &Subscription{
+		d:          d,
+		dataset:    q.Dataset,
+		query:      q,
+		m:          newMatcher(d.schemata[q.Dataset], q),
+		indexKey:   q.IndexKey,
+		indexValue: q.IndexValue,
+		policy:     sr.SlowPolicy,
+		records:    make(chan *server.Record, bufferSize),
+		done:       make(chan struct{}),
+	}
+
+	s.m.names = q.Names
+
+	if sr.Replay {
+		err = d.walk(q, func(r *server.Record, _ *aggregator) {
+			s.replay = append(s.replay, r)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	d.subscriptions[q.Dataset][s] = struct{}{}
+
+	return
+}
+
+// Next returns the next record for this subscription, blocking until
+// one arrives, ctx is cancelled, or the subscription ends.
+//
+// Once a subscription has ended, Next returns any records still buffered
+// before returning SlowSubscriberError, where the subscriber fell too far
+// behind, or SubscriptionClosedError.
+//
+// Next is not safe to call from multiple goroutines at once
+func (s *Subscription) Next(ctx context.Context) (r *server.Record, err error) {
+	if len(s.replay) > 0 {
+		r, s.replay = s.replay[0], s.replay[1:]
+
+		return
+	}
+
+	select {
+	case r = <-s.records:
+		return
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
+	case <-s.done:
+	}
+
+	select {
+	case r = <-s.records:
+		return
+
+	default:
+	}
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return nil, SubscriptionClosedError
+}
+
+// Dropped returns the number of records dropped because
+// this subscription's buffer was full
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close ends the subscription, and stops any further records
+// being buffered for it. It is safe to call more than once
+func (s *Subscription) Close() {
+	unlock := s.d.lock(s.dataset)
+	defer unlock()
+
+	s.d.unsubscribe(s)
+	s.end(nil)
+}
+
+func (s *Subscription) end(err error) {
+	s.endOnce.Do(func() {
+		s.err = err
+		close(s.done)
+	})
+}
+
+func (s *Subscription) matches(r *server.Record) bool {
+	if s.indexKey != "" && r.Meta.Indices[s.indexKey] != s.indexValue {
+		return false
+	}
+
+	return s.m.matches(r)
+}
+
+// publish passes r to every subscription it matches, without ever
+// blocking; subscriptions with full buffers either drop r or are
+// ended, as per their policy.
+//
+// It must be called with r.Dataset's lock held for writing; see lock
+func (d *Database) publish(r *server.Record) {
+	var slow []*Subscription
+
+	for s := range d.subscriptions[r.Dataset] {
+		if !s.matches(r) {
+			continue
+		}
+
+		select {
+		case s.records <- r:
+			continue
+
+		default:
+		}
+
+		if s.policy == server.SlowSubscriberPolicy_Disconnect {
+			slow = append(slow, s)
+
+			continue
+		}
+
+		s.dropped.Add(1)
+	}
+
+	for _, s := range slow {
+		d.unsubscribe(s)
+		s.end(SlowSubscriberError)
+	}
+}
+
+// unsubscribe stops s receiving any further records.
+//
+// It must be called with s.dataset's lock held for writing; see lock.
+// Subscriptions to deleted datasets have nothing to be removed from
+func (d *Database) unsubscribe(s *Subscription) {
+	delete(d.subscriptions[s.dataset], s)
+}
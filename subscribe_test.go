@@ -0,0 +1,217 @@
+package xyt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// subscriptionDatabase returns a database holding a single, empty, dataset
+func subscriptionDatabase(t *testing.T) *Database {
+	t.Helper()
+
+	return testDatabase(t, nil, []*server.Schema{{Dataset: "site-a", XMax: 10, YMax: 10}}, nil)
+}
+
+func insertAt(t *testing.T, d *Database, name string, x int32, indices map[string]string) {
+	t.Helper()
+
+	err := d.InsertRecord(&server.Record{
+		Meta:    &server.Metadata{When: timestamppb.Now(), Indices: indices},
+		Dataset: "site-a",
+		Name:    name,
+		X:       x,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDatabase_Subscribe(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		sr   *server.SubscribeRequest
+	}{
+		{"Nil requests error", nil},
+		{"Missing queries error", &server.SubscribeRequest{}},
+		{"Unknown datasets error", &server.SubscribeRequest{Query: &server.Query{Dataset: "site-b"}}},
+		{"Incomplete index queries error", &server.SubscribeRequest{Query: &server.Query{Dataset: "site-a", IndexKey: "robot"}}},
+		{"Unknown policies error", &server.SubscribeRequest{Query: &server.Query{Dataset: "site-a"}, SlowPolicy: 100}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := subscriptionDatabase(t).Subscribe(test.sr)
+			if err == nil {
+				t.Errorf("expected error, received none")
+			}
+		})
+	}
+
+	t.Run("Replayed records are followed by matching new records", func(t *testing.T) {
+		d := subscriptionDatabase(t)
+
+		insertAt(t, d, "temperature", 0, nil)
+		insertAt(t, d, "temperature", 1, nil)
+		insertAt(t, d, "voltage", 1, nil)
+
+		s, err := d.Subscribe(&server.SubscribeRequest{
+			Query: &server.Query{
+				Dataset: "site-a",
+				Names:   []string{"temperature"},
+				X:       &server.Query_XRange{XRange: &server.QueryRange{Start: 0, End: 5}},
+			},
+			Replay: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer s.Close()
+
+		insertAt(t, d, "temperature", 7, nil)
+		insertAt(t, d, "voltage", 2, nil)
+		insertAt(t, d, "temperature", 2, nil)
+
+		for _, expect := range []int32{0, 1, 2} {
+			r, err := s.Next(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if r.Name != "temperature" || r.X != expect {
+				t.Errorf("expected temperature at x=%d, received %s at x=%d", expect, r.Name, r.X)
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = s.Next(ctx)
+		if err != context.DeadlineExceeded {
+			t.Errorf("expected context.DeadlineExceeded, received %#v", err)
+		}
+	})
+
+	t.Run("Index queries only receive indexed records", func(t *testing.T) {
+		d := subscriptionDatabase(t)
+
+		s, err := d.Subscribe(&server.SubscribeRequest{
+			Query: &server.Query{Dataset: "site-a", IndexKey: "robot", IndexValue: "robo-001"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer s.Close()
+
+		insertAt(t, d, "temperature", 1, map[string]string{"robot": "robo-002"})
+		insertAt(t, d, "temperature", 2, nil)
+		insertAt(t, d, "temperature", 3, map[string]string{"robot": "robo-001"})
+
+		r, err := s.Next(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if r.X != 3 {
+			t.Errorf("expected record at x=3, received x=%d", r.X)
+		}
+	})
+
+	t.Run("Slow subscribers drop records by default", func(t *testing.T) {
+		d := subscriptionDatabase(t)
+
+		s, err := d.Subscribe(&server.SubscribeRequest{
+			Query:      &server.Query{Dataset: "site-a"},
+			BufferSize: 1,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer s.Close()
+
+		for x := int32(0); x < 3; x++ {
+			insertAt(t, d, "temperature", x, nil)
+		}
+
+		if s.Dropped() != 2 {
+			t.Errorf("expected 2 dropped records, received %d", s.Dropped())
+		}
+
+		r, err := s.Next(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if r.X != 0 {
+			t.Errorf("expected the first record to survive, received x=%d", r.X)
+		}
+
+		insertAt(t, d, "temperature", 4, nil)
+
+		r, err = s.Next(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if r.X != 4 {
+			t.Errorf("expected new records once caught up, received x=%d", r.X)
+		}
+	})
+
+	t.Run("Slow subscribers can be disconnected", func(t *testing.T) {
+		d := subscriptionDatabase(t)
+
+		s, err := d.Subscribe(&server.SubscribeRequest{
+			Query:      &server.Query{Dataset: "site-a"},
+			BufferSize: 1,
+			SlowPolicy: server.SlowSubscriberPolicy_Disconnect,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer s.Close()
+
+		insertAt(t, d, "temperature", 0, nil)
+		insertAt(t, d, "temperature", 1, nil)
+
+		if len(d.subscriptions["site-a"]) != 0 {
+			t.Errorf("expected subscription to be removed, %d remain", len(d.subscriptions["site-a"]))
+		}
+
+		_, err = s.Next(context.Background())
+		if err != nil {
+			t.Errorf("expected buffered record, received %#v", err)
+		}
+
+		_, err = s.Next(context.Background())
+		if err != SlowSubscriberError {
+			t.Errorf("expected SlowSubscriberError, received %#v", err)
+		}
+	})
+
+	t.Run("Closed subscriptions stop receiving records", func(t *testing.T) {
+		d := subscriptionDatabase(t)
+
+		s, err := d.Subscribe(&server.SubscribeRequest{
+			Query: &server.Query{Dataset: "site-a"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s.Close()
+		s.Close()
+
+		insertAt(t, d, "temperature", 0, nil)
+
+		_, err = s.Next(context.Background())
+		if err != SubscriptionClosedError {
+			t.Errorf("expected SubscriptionClosedError, received %#v", err)
+		}
+	})
+}
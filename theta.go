@@ -0,0 +1,65 @@
+package xyt
+
+import (
+	"math"
+
+	"github.com/xyt-db/xyt/server"
+)
+
+// maxThetaBuckets caps how finely a schema may split a full turn, which
+// keeps bucket numbers comfortably within an int32; it works out as a
+// resolution of a ten-thousandth of a degree
+const maxThetaBuckets = 3_600_000
+
+// thetaBuckets returns the number of theta buckets in a full turn for s
+func thetaBuckets(s *server.Schema) int32 {
+	if s.ThetaResolution == 0 {
+		return 360
+	}
+
+	return int32(math.Round(360 / s.ThetaResolution))
+}
+
+// validThetaResolution returns whether res splits a full turn into a
+// whole number of buckets, or is unset
+func validThetaResolution(res float64) bool {
+	if res == 0 {
+		return true
+	}
+
+	// Checking for a positive resolution this way around also catches NaN
+	if !(res > 0) || res > 360 {
+		return false
+	}
+
+	n := 360 / res
+
+	return n <= maxThetaBuckets && math.Abs(n-math.Round(n)) < 1e-6
+}
+
+// recordTheta returns the theta bucket r belongs in, binning r.Theta where
+// set, and wrapping a full turn back around to 0
+func recordTheta(s *server.Schema, r *server.Record) int32 {
+	t := int64(r.T)
+
+	if r.Theta != nil {
+		res := s.ThetaResolution
+		if res == 0 {
+			res = 1
+		}
+
+		// A little slack stops headings such as 0.3, with a resolution of
+		// 0.1, landing in the bucket below thanks to floating point error
+		t = int64(math.Floor(*r.Theta/res + 1e-9))
+	}
+
+	return wrapTheta(t, thetaBuckets(s))
+}
+
+// wrapTheta wraps t into the range 0 to buckets, so that, with
+// one degree buckets, -10 becomes 350, and 370 becomes 10
+func wrapTheta(t int64, buckets int32) int32 {
+	n := int64(buckets)
+
+	return int32((t%n + n) % n) // #nosec: G115
+}
@@ -0,0 +1,128 @@
+package xyt
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestValidThetaResolution(t *testing.T) {
+	for _, test := range []struct {
+		res    float64
+		expect bool
+	}{
+		{0, true},
+		{1, true},
+		{0.1, true},
+		{0.25, true},
+		{15, true},
+		{360, true},
+		{0.7, false},
+		{7, false},
+		{-1, false},
+		{720, false},
+		{1e-9, false},
+		{math.NaN(), false},
+	} {
+		if rcvd := validThetaResolution(test.res); rcvd != test.expect {
+			t.Errorf("%v: expected %v, received %v", test.res, test.expect, rcvd)
+		}
+	}
+}
+
+func TestDatabase_ThetaResolution(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.CreateDataset(&server.Schema{Dataset: "site-b", XMax: 10, YMax: 10, ThetaResolution: 0.7})
+	if err != InvalidThetaResolutionError {
+		t.Errorf("expected InvalidThetaResolutionError, received %#v", err)
+	}
+
+	err = d.CreateDataset(&server.Schema{
+		Dataset:         "site-a",
+		XMax:            10,
+		YMax:            10,
+		SortOnInsert:    true,
+		ThetaResolution: 0.1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	theta := func(f float64) *float64 {
+		return &f
+	}
+
+	for _, test := range []struct {
+		name        string
+		t           int32
+		theta       *float64
+		expectT     int32
+		expectError bool
+	}{
+		{"Precise headings are binned", 0, theta(90.25), 902, false},
+		{"Headings on a bucket's edge stay in that bucket", 0, theta(0.3), 3, false},
+		{"A full turn is stored as 0", 0, theta(360), 0, false},
+		{"Precise headings replace T", 5, theta(1), 10, false},
+		{"T is in buckets", 3599, nil, 3599, false},
+		{"A full turn of buckets is stored as 0", 3600, nil, 0, false},
+		{"T beyond a full turn fails", 3601, nil, 0, true},
+		{"Negative headings fail", 0, theta(-1), 0, true},
+		{"Headings beyond 360 fail", 0, theta(361), 0, true},
+		{"NaN headings fail", 0, theta(math.NaN()), 0, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			r := &server.Record{
+				Meta:    &server.Metadata{When: timestamppb.New(time.Unix(1000, 0))},
+				Dataset: "site-a",
+				Name:    "range",
+				T:       test.t,
+				Theta:   test.theta,
+			}
+
+			err := d.InsertRecord(r)
+			if err == nil && test.expectError {
+				t.Errorf("expected error, received none")
+			} else if err != nil && !test.expectError {
+				t.Errorf("unexpected error %#v", err)
+			}
+
+			if err == nil && r.T != test.expectT {
+				t.Errorf("expected T %d, received %d", test.expectT, r.T)
+			}
+		})
+	}
+
+	// Records are now at buckets 902, 3, 0, 10, 3599, and 0
+	for _, test := range []struct {
+		name        string
+		query       *server.Query
+		expectCount int
+	}{
+		{"Single buckets", &server.Query{T: &server.Query_TValue{TValue: 902}}, 1},
+		{"Bucket ranges", &server.Query{T: &server.Query_TRange{TRange: &server.QueryRange{Start: 0, End: 11}}}, 4},
+		{"Bucket ranges wrap around a full turn", &server.Query{T: &server.Query_TRange{TRange: &server.QueryRange{Start: 3599, End: 4}}}, 4},
+		{"Headings use buckets", &server.Query{T: &server.Query_THeading{THeading: &server.Heading{Heading: 900, Tolerance: 2}}}, 1},
+		{"Headings wrap around a full turn", &server.Query{T: &server.Query_THeading{THeading: &server.Heading{Heading: 0, Tolerance: 1}}}, 3},
+		{"Everything", &server.Query{T: &server.Query_TAll{TAll: true}}, 6},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			test.query.Dataset = "site-a"
+
+			records, err := d.RetrieveRecords(test.query)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if test.expectCount != len(records) {
+				t.Errorf("expected %d records, received %d", test.expectCount, len(records))
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package xyt
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// MaxTimeSeriesBuckets is the largest number of buckets a single filled
+// series may span, to stop a tiny bucket width over a huge time range
+// from allocating millions of empty points
+const MaxTimeSeriesBuckets = 100_000
+
+// A bucketSeries holds the running aggregates for each populated
+// bucket of a single Name
+type bucketSeries struct {
+	name    string
+	buckets map[int64]*aggregator
+
+	// current caches the most recently used bucket. Sorted datasets
+	// pass records to us in `When` order, a location at a time, so
+	// consecutive records almost always land in the same bucket and
+	// we only touch the map when moving on to the next one
+	current    *aggregator
+	currentIdx int64
+}
+
+func newBucketSeries(name string) *bucketSeries {
+	return &bucketSeries{
+		name:    name,
+		buckets: make(map[int64]*aggregator),
+	}
+}
+
+func (bs *bucketSeries) add(idx int64, r *server.Record, rolled *aggregator) {
+	if bs.current == nil || bs.currentIdx != idx {
+		a, ok := bs.buckets[idx]
+		if !ok {
+			a = new(aggregator)
+			bs.buckets[idx] = a
+		}
+
+		bs.current, bs.currentIdx = a, idx
+	}
+
+	bs.current.visit(r, rolled)
+}
+
+// bucketing maps timestamps to buckets of width nanoseconds,
+// with boundaries aligned to align
+type bucketing struct {
+	align, width int64
+}
+
+func (b bucketing) index(t time.Time) int64 {
+	n := t.UnixNano() - b.align
+
+	// Round towards negative infinity, so that timestamps before the
+	// alignment point still land in the right bucket
+	i := n / b.width
+	if n%b.width < 0 {
+		i--
+	}
+
+	return i
+}
+
+// floor returns the start of the bucket t falls in, in
+// nanoseconds since the epoch
+func (b bucketing) floor(t time.Time) int64 {
+	return b.align + b.index(t)*b.width
+}
+
+func (b bucketing) start(idx int64) *timestamppb.Timestamp {
+	return timestamppb.New(time.Unix(0, b.align+idx*b.width))
+}
+
+// TimeSeries aggregates the records matching a query into fixed-width
+// buckets of time, returning a Series per Name.
+//
+// Where the query names specific Names, a Series is returned for each
+// of them in the order given, even where they hold no records. Otherwise
+// a Series is returned for every Name with matching records, sorted by name.
+//
+// Empty buckets are handled according to the Fill policy requested; where
+// the query carries a time range, filled series cover the whole of that range,
+// otherwise they run from the earliest to the latest populated bucket.
+//
+// Records are never sorted; on datasets with SortOnInsert set, records already
+// arrive in order and buckets are walked from one to the next.
+func (d *Database) TimeSeries(tr *server.TimeSeriesRequest) (series []*server.Series, err error) {
+	if tr == nil || tr.Query == nil {
+		return nil, MissingDatasetError
+	}
+
+	if !validAggregation(tr.Aggregation) {
+		return nil, UnknownAggregationError
+	}
+
+	if _, ok := server.FillPolicy_name[int32(tr.Fill)]; !ok {
+		return nil, UnknownFillPolicyError
+	}
+
+	if tr.BucketWidth.AsDuration() <= 0 {
+		return nil, InvalidBucketWidthError
+	}
+
+	if r := tr.Query.GetTimeRange(); r != nil && r.End.AsTime().Before(r.Start.AsTime()) {
+		return nil, InvalidTimeRangeError
+	}
+
+	b := bucketing{
+		width: int64(tr.BucketWidth.AsDuration()),
+	}
+
+	if tr.Alignment != nil {
+		b.align = tr.Alignment.AsTime().UnixNano()
+	}
+
+	byNames := make(map[string]*bucketSeries)
+	for _, name := range tr.Query.Names {
+		byNames[name] = newBucketSeries(name)
+	}
+
+	unlock := d.rlock(tr.Query.Dataset)
+	defer unlock()
+
+	var last *bucketSeries
+
+	err = d.walk(tr.Query, func(r *server.Record, rolled *aggregator) {
+		// Records are walked a series at a time, so the name
+		// rarely changes from one record to the next
+		if last == nil || last.name != r.Name {
+			bs, ok := byNames[r.Name]
+			if !ok {
+				bs = newBucketSeries(r.Name)
+				byNames[r.Name] = bs
+			}
+
+			last = bs
+		}
+
+		last.add(b.index(r.Meta.When.AsTime()), r, rolled)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := tr.Query.Names
+	if len(names) == 0 {
+		names = make([]string, 0, len(byNames))
+		for name := range byNames {
+			names = append(names, name)
+		}
+
+		slices.SortFunc(names, cmp.Compare)
+	}
+
+	var from, to int64
+	bounded := tr.Query.GetTimeRange() != nil
+	if bounded {
+		from = b.index(tr.Query.GetTimeRange().Start.AsTime())
+		to = b.index(tr.Query.GetTimeRange().End.AsTime())
+
+		if tr.Fill != server.FillPolicy_FillNone && to-from >= MaxTimeSeriesBuckets {
+			return nil, TooManyBucketsError
+		}
+	}
+
+	series = make([]*server.Series, 0, len(names))
+	for _, name := range names {
+		s := &server.Series{Name: name}
+
+		bs := byNames[name]
+
+		idxs := make([]int64, 0, len(bs.buckets))
+		for idx := range bs.buckets {
+			idxs = append(idxs, idx)
+		}
+
+		slices.Sort(idxs)
+
+		if tr.Fill == server.FillPolicy_FillNone {
+			s.Points = make([]*server.Point, len(idxs))
+			for i, idx := range idxs {
+				s.Points[i] = bucketPoint(b, idx, bs.buckets[idx], tr.Aggregation)
+			}
+
+			series = append(series, s)
+
+			continue
+		}
+
+		first, final := from, to
+		if !bounded {
+			if len(idxs) == 0 {
+				series = append(series, s)
+
+				continue
+			}
+
+			first, final = idxs[0], idxs[len(idxs)-1]
+			if final-first >= MaxTimeSeriesBuckets {
+				return nil, TooManyBucketsError
+			}
+		}
+
+		s.Points = make([]*server.Point, 0, final-first+1)
+		for idx := first; idx <= final; idx++ {
+			s.Points = append(s.Points, bucketPoint(b, idx, bs.buckets[idx], tr.Aggregation))
+		}
+
+		fill(s.Points, tr.Fill)
+
+		series = append(series, s)
+	}
+
+	return
+}
+
+// bucketPoint returns the point for bucket idx, which is null
+// where the bucket holds no records
+func bucketPoint(b bucketing, idx int64, a *aggregator, ag server.Aggregation) *server.Point {
+	p := &server.Point{
+		Start: b.start(idx),
+	}
+
+	if a == nil || a.count == 0 {
+		p.Null = true
+
+		return p
+	}
+
+	p.Value = a.value(ag)
+	p.Count = a.count
+
+	return p
+}
+
+// fill fills null points according to policy, leaving points which
+// can't be filled, such as those before the first populated point,
+// as null
+func fill(points []*server.Point, policy server.FillPolicy) {
+	switch policy {
+	case server.FillPolicy_FillPrevious:
+		var prev *server.Point
+		for _, p := range points {
+			if !p.Null {
+				prev = p
+
+				continue
+			}
+
+			if prev != nil {
+				p.Value, p.Null = prev.Value, false
+			}
+		}
+
+	case server.FillPolicy_FillLinear:
+		prev := -1
+		for i, p := range points {
+			if p.Null {
+				continue
+			}
+
+			if prev >= 0 && i-prev > 1 {
+				step := (p.Value - points[prev].Value) / float64(i-prev)
+				for j := prev + 1; j < i; j++ {
+					points[j].Value, points[j].Null = points[prev].Value+step*float64(j-prev), false
+				}
+			}
+
+			prev = i
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package xyt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type expectedPoint struct {
+	start time.Duration
+	value float64
+	null  bool
+}
+
+func TestDatabase_TimeSeries(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.CreateDataset(&server.Schema{
+		Dataset:      "site-a",
+		XMax:         10,
+		YMax:         10,
+		SortOnInsert: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, r := range []struct {
+		name   string
+		offset time.Duration
+		value  float64
+		x      int32
+	}{
+		{"temperature", 20 * time.Second, 3, 0},
+		{"temperature", 0, 1, 0},
+		{"temperature", 10 * time.Second, 2, 1},
+		{"temperature", 130 * time.Second, 9, 2},
+		{"voltage", 30 * time.Second, 5, 0},
+	} {
+		err = d.InsertRecord(&server.Record{
+			Meta:    &server.Metadata{When: timestamppb.New(base.Add(r.offset))},
+			Dataset: "site-a",
+			Name:    r.name,
+			Value:   r.value,
+			X:       r.x,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	query := func(names ...string) *server.Query {
+		return &server.Query{Dataset: "site-a", Names: names}
+	}
+
+	ranged := func(names ...string) *server.Query {
+		q := query(names...)
+		q.Time = &server.Query_TimeRange{TimeRange: &server.TimeRange{
+			Start: timestamppb.New(base.Add(-time.Minute)),
+			End:   timestamppb.New(base.Add(3 * time.Minute)),
+		}}
+
+		return q
+	}
+
+	reversed := func(names ...string) *server.Query {
+		q := query(names...)
+		q.Time = &server.Query_TimeRange{TimeRange: &server.TimeRange{
+			Start: timestamppb.New(base.Add(time.Hour)),
+			End:   timestamppb.New(base.Add(-time.Hour)),
+		}}
+
+		return q
+	}
+
+	minute := durationpb.New(time.Minute)
+	aligned := timestamppb.New(base)
+
+	for _, test := range []struct {
+		name        string
+		req         *server.TimeSeriesRequest
+		expect      map[string][]expectedPoint
+		expectError bool
+	}{
+		{"Nil requests error", nil, nil, true},
+		{"Invalid queries error", &server.TimeSeriesRequest{Query: &server.Query{}, BucketWidth: minute}, nil, true},
+		{"Missing bucket widths error", &server.TimeSeriesRequest{Query: query()}, nil, true},
+		{"Negative bucket widths error", &server.TimeSeriesRequest{Query: query(), BucketWidth: durationpb.New(-time.Minute)}, nil, true},
+		{"Unknown aggregations error", &server.TimeSeriesRequest{Query: query(), BucketWidth: minute, Aggregation: 100}, nil, true},
+		{"Unknown fill policies error", &server.TimeSeriesRequest{Query: query(), BucketWidth: minute, Fill: 100}, nil, true},
+		{"Reversed time ranges error", &server.TimeSeriesRequest{Query: reversed("n"), BucketWidth: minute, Fill: server.FillPolicy_FillNull}, nil, true},
+		{"Huge numbers of filled buckets error", &server.TimeSeriesRequest{Query: ranged(), BucketWidth: durationpb.New(time.Nanosecond), Fill: server.FillPolicy_FillNull}, nil, true},
+
+		{"Empty buckets are left out by default", &server.TimeSeriesRequest{Query: query(), BucketWidth: minute, Alignment: aligned, Aggregation: server.Aggregation_Mean},
+			map[string][]expectedPoint{
+				"temperature": {{0, 2, false}, {2 * time.Minute, 9, false}},
+				"voltage":     {{0, 5, false}},
+			}, false},
+		{"Buckets default to aligning with the epoch", &server.TimeSeriesRequest{Query: query("voltage"), BucketWidth: durationpb.New(7 * time.Hour), Aggregation: server.Aggregation_Count},
+			map[string][]expectedPoint{
+				// 2025-01-01 is 20089 days, or 482136 hours, after the epoch; 482132
+				// is the nearest multiple of 7 hours before that
+				"voltage": {{-4 * time.Hour, 1, false}},
+			}, false},
+		{"Buckets respect alignment", &server.TimeSeriesRequest{Query: query("temperature"), BucketWidth: minute, Alignment: timestamppb.New(base.Add(30 * time.Second)), Aggregation: server.Aggregation_Count},
+			map[string][]expectedPoint{
+				"temperature": {{-30 * time.Second, 3, false}, {90 * time.Second, 1, false}},
+			}, false},
+		{"Requested names with no data return empty series", &server.TimeSeriesRequest{Query: query("flurbles"), BucketWidth: minute, Fill: server.FillPolicy_FillLinear},
+			map[string][]expectedPoint{
+				"flurbles": {},
+			}, false},
+		{"Null fills mark empty buckets", &server.TimeSeriesRequest{Query: query("temperature"), BucketWidth: minute, Alignment: aligned, Aggregation: server.Aggregation_Mean, Fill: server.FillPolicy_FillNull},
+			map[string][]expectedPoint{
+				"temperature": {{0, 2, false}, {time.Minute, 0, true}, {2 * time.Minute, 9, false}},
+			}, false},
+		{"Previous fills carry values forward", &server.TimeSeriesRequest{Query: query("temperature"), BucketWidth: minute, Alignment: aligned, Aggregation: server.Aggregation_Mean, Fill: server.FillPolicy_FillPrevious},
+			map[string][]expectedPoint{
+				"temperature": {{0, 2, false}, {time.Minute, 2, false}, {2 * time.Minute, 9, false}},
+			}, false},
+		{"Linear fills interpolate", &server.TimeSeriesRequest{Query: query("temperature"), BucketWidth: minute, Alignment: aligned, Aggregation: server.Aggregation_Mean, Fill: server.FillPolicy_FillLinear},
+			map[string][]expectedPoint{
+				"temperature": {{0, 2, false}, {time.Minute, 5.5, false}, {2 * time.Minute, 9, false}},
+			}, false},
+		{"Time ranges are filled end to end", &server.TimeSeriesRequest{Query: ranged("temperature"), BucketWidth: minute, Alignment: aligned, Aggregation: server.Aggregation_Max, Fill: server.FillPolicy_FillPrevious},
+			map[string][]expectedPoint{
+				"temperature": {{-time.Minute, 0, true}, {0, 3, false}, {time.Minute, 3, false}, {2 * time.Minute, 9, false}, {3 * time.Minute, 9, false}},
+			}, false},
+		{"Linear fills leave the ends of ranges null", &server.TimeSeriesRequest{Query: ranged("temperature"), BucketWidth: minute, Alignment: aligned, Aggregation: server.Aggregation_Max, Fill: server.FillPolicy_FillLinear},
+			map[string][]expectedPoint{
+				"temperature": {{-time.Minute, 0, true}, {0, 3, false}, {time.Minute, 6, false}, {2 * time.Minute, 9, false}, {3 * time.Minute, 0, true}},
+			}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			series, err := d.TimeSeries(test.req)
+			if err == nil && test.expectError {
+				t.Errorf("expected error, received none")
+			} else if err != nil && !test.expectError {
+				t.Errorf("unexpected error %#v", err)
+			}
+
+			if len(test.expect) != len(series) {
+				t.Fatalf("expected %d series, received %d", len(test.expect), len(series))
+			}
+
+			for _, s := range series {
+				expect, ok := test.expect[s.Name]
+				if !ok {
+					t.Errorf("unexpected series %q", s.Name)
+
+					continue
+				}
+
+				if len(expect) != len(s.Points) {
+					t.Fatalf("%s: expected %d points, received %d", s.Name, len(expect), len(s.Points))
+				}
+
+				for i, p := range s.Points {
+					if start := base.Add(expect[i].start); !p.Start.AsTime().Equal(start) {
+						t.Errorf("%s: point %d: expected start %s, received %s", s.Name, i, start, p.Start.AsTime())
+					}
+
+					if expect[i].null != p.Null {
+						t.Errorf("%s: point %d: expected null %v, received %v", s.Name, i, expect[i].null, p.Null)
+					}
+
+					if !expect[i].null && !floatsMatch(expect[i].value, p.Value) {
+						t.Errorf("%s: point %d: expected %v, received %v", s.Name, i, expect[i].value, p.Value)
+					}
+				}
+			}
+		})
+	}
+}
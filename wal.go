@@ -0,0 +1,556 @@
+package xyt
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SyncPolicy determines how often a WAL is fsync'd to disk.
+//
+// Every entry is written to the underlying file as soon as it is appended,
+// and so will survive the xyt process dying; a SyncPolicy controls how much
+// data can be lost when the *machine* dies
+type SyncPolicy uint8
+
+const (
+	// SyncAlways fsyncs after every single entry; this is the safest, and
+	// slowest, policy
+	SyncAlways SyncPolicy = iota
+
+	// SyncBatch fsyncs after every WALOptions.BatchSize entries
+	SyncBatch
+
+	// SyncInterval fsyncs every WALOptions.Interval, if anything has been
+	// written since the last sync
+	SyncInterval
+)
+
+// ParseSyncPolicy turns a string, such as from a command line flag,
+// into a SyncPolicy
+func ParseSyncPolicy(s string) (SyncPolicy, error) {
+	switch strings.ToLower(s) {
+	case "always":
+		return SyncAlways, nil
+
+	case "batch":
+		return SyncBatch, nil
+
+	case "interval":
+		return SyncInterval, nil
+
+	default:
+		return 0, UnknownSyncPolicyError
+	}
+}
+
+// WALOptions holds the tunables for a WAL
+type WALOptions struct {
+	Policy SyncPolicy
+
+	// BatchSize is the number of entries written between fsyncs
+	// when Policy is SyncBatch
+	BatchSize int
+
+	// Interval is the time between fsyncs when Policy is SyncInterval
+	Interval time.Duration
+}
+
+type walEntryKind uint8
+
+const (
+	walEntryUnknown walEntryKind = iota
+	walEntrySchema
+	walEntryRecord
+	walEntryTruncate
+	walEntryExpire
+	walEntryRollup
+	walEntryZone
+	walEntryDeleteZone
+	walEntryDeleteDataset
+	walEntryDelete
+	walEntryAlterSchema
+)
+
+// walHeaderSize is the size of the header preceding each entry:
+//
+//	| crc32 (4 bytes) | payload length (4 bytes) | kind (1 byte) |
+//
+// Where the crc32 covers both the kind and the payload
+const walHeaderSize = 9
+
+// A WAL is an append-only log of every schema and record accepted by
+// a Database, which can be replayed on startup to rebuild that Database
+// after a restart.
+//
+// A WAL is attached to a Database with Database.UseWAL
+type WAL struct {
+	mutx sync.Mutex
+
+	f       *os.File
+	opts    WALOptions
+	pending int
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// OpenWAL opens, or creates, the WAL at path.
+//
+// Entries already in the WAL are left alone until the WAL is replayed
+func OpenWAL(path string, opts WALOptions) (w *WAL, err error) {
+	if opts.Policy == SyncBatch && opts.BatchSize <= 0 {
+		return nil, InvalidWALOptionsError
+	}
+
+	if opts.Policy == SyncInterval && opts.Interval <= 0 {
+		return nil, InvalidWALOptionsError
+	}
+
+	// #nosec: G304
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return
+	}
+
+	w = &WAL{
+		f:    f,
+		opts: opts,
+		done: make(chan struct{}),
+	}
+
+	if opts.Policy == SyncInterval {
+		w.wg.Add(1)
+		go w.syncEvery(opts.Interval)
+	}
+
+	return
+}
+
+// Close syncs any outstanding writes and closes the WAL
+func (w *WAL) Close() (err error) {
+	close(w.done)
+	w.wg.Wait()
+
+	w.mutx.Lock()
+	defer w.mutx.Unlock()
+
+	err = w.f.Sync()
+	if err != nil {
+		return
+	}
+
+	return w.f.Close()
+}
+
+// replay reads every entry in the WAL from the start, passing each one
+// to fn.
+//
+// A torn final entry (such as one half-written when the machine died), and
+// anything after it which holds no intact entries, such as a zero-filled tail,
+// is truncated away, leaving the WAL ready for new writes. Corruption anywhere
+// else in the log, such as an entry whose length runs past the end of the log
+// despite intact entries following it, is returned as a CorruptWALError,
+// because quietly skipping data in the middle of a log is a great way to end
+// up with a database that lies to you
+func (w *WAL) replay(fn func(walEntryKind, []byte) error) (err error) {
+	w.mutx.Lock()
+	defer w.mutx.Unlock()
+
+	fi, err := w.f.Stat()
+	if err != nil {
+		return
+	}
+
+	size := fi.Size()
+
+	_, err = w.f.Seek(0, io.SeekStart)
+	if err != nil {
+		return
+	}
+
+	var (
+		offset int64
+		header = make([]byte, walHeaderSize)
+		body   []byte
+	)
+
+	for {
+		_, err = io.ReadFull(w.f, header)
+		if err == io.EOF {
+			err = nil
+
+			break
+		}
+
+		if err == io.ErrUnexpectedEOF {
+			return w.truncate(offset)
+		}
+
+		if err != nil {
+			return
+		}
+
+		sum := binary.LittleEndian.Uint32(header[0:4])
+		length := int64(binary.LittleEndian.Uint32(header[4:8]))
+		end := offset + walHeaderSize + length
+
+		// A length running off the end of the log is only a torn write
+		// where nothing intact follows; otherwise, the length itself is
+		// what's been damaged, and everything after it would be lost
+		if end > size {
+			var intact bool
+
+			intact, err = w.intactAfter(offset, size)
+			if err != nil {
+				return
+			}
+
+			if intact {
+				return CorruptWALError{offset: offset}
+			}
+
+			return w.truncate(offset)
+		}
+
+		if int64(cap(body)) < length {
+			body = make([]byte, length)
+		}
+
+		body = body[:length]
+
+		_, err = io.ReadFull(w.f, body)
+		if err != nil {
+			return
+		}
+
+		crc := crc32.NewIEEE()
+		crc.Write(header[8:9])
+		crc.Write(body)
+
+		// Likewise, a bad checksum is only corruption where something
+		// intact follows; crashes often leave the end of a log zeroed,
+		// or otherwise filled with junk, rather than simply cut short
+		if crc.Sum32() != sum {
+			var intact bool
+
+			intact, err = w.intactAfter(offset, size)
+			if err != nil {
+				return
+			}
+
+			if intact {
+				return CorruptWALError{offset: offset}
+			}
+
+			return w.truncate(offset)
+		}
+
+		err = fn(walEntryKind(header[8]), body)
+		if err != nil {
+			return
+		}
+
+		offset = end
+	}
+
+	_, err = w.f.Seek(0, io.SeekEnd)
+
+	return
+}
+
+// intactAfter returns whether an intact entry starts anywhere after offset.
+//
+// A torn final write only ever leaves part of a single entry behind, so an
+// intact entry beyond offset means the entry at offset was damaged mid-log.
+// Entries are looked for byte by byte, since the damaged entry's length
+// can't say where the next one starts; this is only done when replay has
+// already gone wrong
+func (w *WAL) intactAfter(offset, size int64) (intact bool, err error) {
+	rest := make([]byte, size-offset)
+
+	_, err = w.f.ReadAt(rest, offset)
+	if err != nil {
+		return
+	}
+
+	for p := 1; p+walHeaderSize <= len(rest); p++ {
+		end := p + walHeaderSize + int(binary.LittleEndian.Uint32(rest[p+4:p+8]))
+		if end > len(rest) {
+			continue
+		}
+
+		// The kind and payload sit side by side, just as they're checksummed
+		if crc32.ChecksumIEEE(rest[p+8:end]) == binary.LittleEndian.Uint32(rest[p:p+4]) {
+			return true, nil
+		}
+	}
+
+	return
+}
+
+func (w *WAL) truncate(offset int64) (err error) {
+	err = w.f.Truncate(offset)
+	if err != nil {
+		return
+	}
+
+	_, err = w.f.Seek(offset, io.SeekStart)
+
+	return
+}
+
+func (w *WAL) appendSchema(s *server.Schema) error {
+	return w.appendMessage(walEntrySchema, s)
+}
+
+func (w *WAL) appendRecord(r *server.Record) error {
+	return w.appendMessage(walEntryRecord, r)
+}
+
+// appendTruncate logs a truncation of dataset; kind is one of walEntryTruncate,
+// which also truncates rollups, or walEntryExpire, which only truncates records
+func (w *WAL) appendTruncate(kind walEntryKind, dataset string, before time.Time) error {
+	return w.appendMessage(kind, &server.TruncateRequest{
+		Dataset: dataset,
+		Before:  timestamppb.New(before),
+	})
+}
+
+func (w *WAL) appendRollup(b *server.RollupBucket) error {
+	return w.appendMessage(walEntryRollup, b)
+}
+
+func (w *WAL) appendZone(z *server.Zone) error {
+	return w.appendMessage(walEntryZone, z)
+}
+
+func (w *WAL) appendDeleteZone(dataset, name string) error {
+	return w.appendMessage(walEntryDeleteZone, &server.DeleteZoneRequest{
+		Dataset: dataset,
+		Name:    name,
+	})
+}
+
+func (w *WAL) appendDeleteDataset(dataset string) error {
+	return w.appendMessage(walEntryDeleteDataset, &server.DeleteDatasetRequest{
+		Dataset: dataset,
+	})
+}
+
+func (w *WAL) appendDelete(q *server.Query) error {
+	return w.appendMessage(walEntryDelete, &server.DeleteRequest{
+		Query: q,
+	})
+}
+
+func (w *WAL) appendAlterSchema(a *server.AlterSchemaRequest) error {
+	return w.appendMessage(walEntryAlterSchema, a)
+}
+
+func (w *WAL) appendMessage(kind walEntryKind, m proto.Message) (err error) {
+	payload, err := proto.Marshal(m)
+	if err != nil {
+		return
+	}
+
+	return w.append(kind, payload)
+}
+
+func (w *WAL) append(kind walEntryKind, payload []byte) (err error) {
+	buf := make([]byte, walHeaderSize+len(payload))
+
+	buf[8] = byte(kind)
+	copy(buf[walHeaderSize:], payload)
+
+	// #nosec: G115
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(buf[0:4], crc32.ChecksumIEEE(buf[8:]))
+
+	w.mutx.Lock()
+	defer w.mutx.Unlock()
+
+	_, err = w.f.Write(buf)
+	if err != nil {
+		return
+	}
+
+	w.pending++
+
+	switch w.opts.Policy {
+	case SyncAlways:
+		return w.sync()
+
+	case SyncBatch:
+		if w.pending >= w.opts.BatchSize {
+			return w.sync()
+		}
+	}
+
+	return
+}
+
+// sync must be called with w.mutx held
+func (w *WAL) sync() (err error) {
+	err = w.f.Sync()
+	if err != nil {
+		return
+	}
+
+	w.pending = 0
+
+	return
+}
+
+func (w *WAL) syncEvery(interval time.Duration) {
+	defer w.wg.Done()
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+
+		case <-t.C:
+			w.mutx.Lock()
+			if w.pending > 0 {
+				// There's nobody to return this error to; the next
+				// sync, or Close, will surface persistent problems
+				_ = w.sync()
+			}
+			w.mutx.Unlock()
+		}
+	}
+}
+
+// UseWAL replays every entry in w into the Database, rebuilding datasets
+// and records, and then logs every subsequent schema and record to w.
+//
+// UseWAL should be called on an empty Database, before anything else
+// touches it
+func (d *Database) UseWAL(w *WAL) (err error) {
+	if d.wal != nil {
+		return WALAlreadyAttachedError
+	}
+
+	err = w.replay(func(kind walEntryKind, payload []byte) (err error) {
+		switch kind {
+		case walEntrySchema:
+			s := new(server.Schema)
+
+			err = proto.Unmarshal(payload, s)
+			if err != nil {
+				return
+			}
+
+			return d.CreateDataset(s)
+
+		case walEntryRecord:
+			r := new(server.Record)
+
+			err = proto.Unmarshal(payload, r)
+			if err != nil {
+				return
+			}
+
+			return d.InsertRecord(r)
+
+		case walEntryTruncate, walEntryExpire:
+			tr := new(server.TruncateRequest)
+
+			err = proto.Unmarshal(payload, tr)
+			if err != nil {
+				return
+			}
+
+			_, err = d.truncate(tr.Dataset, tr.Before.AsTime(), kind == walEntryTruncate)
+
+			return
+
+		case walEntryRollup:
+			b := new(server.RollupBucket)
+
+			err = proto.Unmarshal(payload, b)
+			if err != nil {
+				return
+			}
+
+			return d.restoreRollup(b)
+
+		case walEntryZone:
+			z := new(server.Zone)
+
+			err = proto.Unmarshal(payload, z)
+			if err != nil {
+				return
+			}
+
+			return d.AddZone(z)
+
+		case walEntryDeleteZone:
+			dz := new(server.DeleteZoneRequest)
+
+			err = proto.Unmarshal(payload, dz)
+			if err != nil {
+				return
+			}
+
+			return d.DeleteZone(dz.Dataset, dz.Name)
+
+		case walEntryDeleteDataset:
+			dd := new(server.DeleteDatasetRequest)
+
+			err = proto.Unmarshal(payload, dd)
+			if err != nil {
+				return
+			}
+
+			return d.DeleteDataset(dd.Dataset)
+
+		case walEntryDelete:
+			dr := new(server.DeleteRequest)
+
+			err = proto.Unmarshal(payload, dr)
+			if err != nil {
+				return
+			}
+
+			_, err = d.Delete(dr.Query, false)
+
+			return
+
+		case walEntryAlterSchema:
+			a := new(server.AlterSchemaRequest)
+
+			err = proto.Unmarshal(payload, a)
+			if err != nil {
+				return
+			}
+
+			return d.AlterSchema(a)
+
+		default:
+			return UnknownWALEntryError
+		}
+	})
+	if err != nil {
+		return
+	}
+
+	d.mutx.Lock()
+	defer d.mutx.Unlock()
+
+	d.wal = w
+
+	return
+}
@@ -0,0 +1,249 @@
+package xyt
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestParseSyncPolicy(t *testing.T) {
+	for _, test := range []struct {
+		in          string
+		expect      SyncPolicy
+		expectError bool
+	}{
+		{"always", SyncAlways, false},
+		{"Batch", SyncBatch, false},
+		{"INTERVAL", SyncInterval, false},
+		{"", 0, true},
+		{"sometimes", 0, true},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			rcvd, err := ParseSyncPolicy(test.in)
+			if err == nil && test.expectError {
+				t.Errorf("expected error, received none")
+			} else if err != nil && !test.expectError {
+				t.Errorf("unexpected error %#v", err)
+			}
+
+			if test.expect != rcvd {
+				t.Errorf("expected %v, received %v", test.expect, rcvd)
+			}
+		})
+	}
+}
+
+func TestOpenWAL(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		opts        WALOptions
+		expectError bool
+	}{
+		{"Always needs no tunables", WALOptions{Policy: SyncAlways}, false},
+		{"Batch without a batch size fails", WALOptions{Policy: SyncBatch}, true},
+		{"Batch with a batch size succeeds", WALOptions{Policy: SyncBatch, BatchSize: 10}, false},
+		{"Interval without an interval fails", WALOptions{Policy: SyncInterval}, true},
+		{"Interval with an interval succeeds", WALOptions{Policy: SyncInterval, Interval: time.Millisecond}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			w, err := OpenWAL(filepath.Join(t.TempDir(), "xyt.wal"), test.opts)
+			if err == nil && test.expectError {
+				t.Errorf("expected error, received none")
+			} else if err != nil && !test.expectError {
+				t.Errorf("unexpected error %#v", err)
+			}
+
+			if w != nil {
+				err = w.Close()
+				if err != nil {
+					t.Errorf("unexpected error %#v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestDatabase_UseWAL(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		mangle      func(string) error
+		expectCount int
+		expectError bool
+	}{
+		{"Untouched logs replay every record", func(string) error { return nil }, 10, false},
+		{"Torn final entries are truncated", tearWAL, 9, false},
+		{"Garbage on the end of the log is truncated", appendGarbage, 10, false},
+		{"Zeroes on the end of the log are truncated", appendZeroes, 10, false},
+		{"Corruption mid-log errors", corruptWAL, 0, true},
+		{"Lengths running off the end mid-log error", corruptWALLength, 0, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "xyt.wal")
+
+			writeTestWAL(t, path)
+
+			err := test.mangle(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			w, err := OpenWAL(path, WALOptions{Policy: SyncAlways})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer w.Close()
+
+			d, err := New()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = d.UseWAL(w)
+			if err == nil && test.expectError {
+				t.Errorf("expected error, received none")
+			} else if err != nil && !test.expectError {
+				t.Errorf("unexpected error %#v", err)
+			}
+
+			if test.expectError {
+				return
+			}
+
+			records, err := d.RetrieveRecords(&server.Query{Dataset: "site-a"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rcvd := len(records)
+			if test.expectCount != rcvd {
+				t.Errorf("expected %d records, received %d", test.expectCount, rcvd)
+			}
+
+			// Ensure the log is still usable once replayed
+			err = d.InsertRecord(testWALRecord(0))
+			if err != nil {
+				t.Errorf("unexpected error %#v", err)
+			}
+		})
+	}
+}
+
+func writeTestWAL(t *testing.T, path string) {
+	t.Helper()
+
+	w, err := OpenWAL(path, WALOptions{Policy: SyncBatch, BatchSize: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.UseWAL(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.CreateDataset(&server.Schema{
+		Dataset:      "site-a",
+		XMax:         10,
+		YMax:         10,
+		SortOnInsert: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := int32(0); i < 10; i++ {
+		err = d.InsertRecord(testWALRecord(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testWALRecord(i int32) *server.Record {
+	return &server.Record{
+		Meta: &server.Metadata{
+			When: timestamppb.New(time.Unix(int64(i), 0)),
+		},
+		Dataset: "site-a",
+		Name:    "temperature",
+		Value:   float64(i),
+		X:       i,
+		Y:       i,
+		T:       90,
+	}
+}
+
+func tearWAL(path string) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	return os.Truncate(path, fi.Size()-3)
+}
+
+func appendGarbage(path string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	_, err = f.Write([]byte{0xde, 0xad, 0xbe, 0xef, 0x01})
+
+	return err
+}
+
+func appendZeroes(path string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	_, err = f.Write(make([]byte, 4096))
+
+	return err
+}
+
+func corruptWALLength(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	// Point the very first entry's length well past the end of the log
+	binary.LittleEndian.PutUint32(b[4:8], uint32(len(b)))
+
+	return os.WriteFile(path, b, 0o600)
+}
+
+func corruptWAL(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	// Flip a bit in the payload of the very first entry
+	b[walHeaderSize+1] ^= 0xff
+
+	return os.WriteFile(path, b, 0o600)
+}
@@ -0,0 +1,119 @@
+package xyt
+
+import (
+	"math"
+
+	"github.com/xyt-db/xyt/server"
+)
+
+// validTransform returns whether t describes a usable mapping
+// between grid and world coordinates, or is unset
+func validTransform(t *server.Transform) bool {
+	if t == nil {
+		return true
+	}
+
+	for _, f := range []float64{t.CellSize, t.OriginX, t.OriginY, t.Rotation} {
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return false
+		}
+	}
+
+	return t.CellSize > 0
+}
+
+// toGrid converts a world location into (fractional) grid coordinates
+func toGrid(t *server.Transform, wx, wy float64) (gx, gy float64) {
+	sin, cos := math.Sincos(-t.Rotation * math.Pi / 180)
+	dx, dy := wx-t.OriginX, wy-t.OriginY
+
+	return (dx*cos - dy*sin) / t.CellSize, (dx*sin + dy*cos) / t.CellSize
+}
+
+// toWorld converts (fractional) grid coordinates into a world location
+func toWorld(t *server.Transform, gx, gy float64) (wx, wy float64) {
+	sin, cos := math.Sincos(t.Rotation * math.Pi / 180)
+	x, y := gx*t.CellSize, gy*t.CellSize
+
+	return x*cos - y*sin + t.OriginX, x*sin + y*cos + t.OriginY
+}
+
+// cellCentre returns the world location of the centre of the cell at (x,y)
+func cellCentre(t *server.Transform, x, y int32) *server.WorldPoint {
+	wx, wy := toWorld(t, float64(x)+0.5, float64(y)+0.5)
+
+	return &server.WorldPoint{X: wx, Y: wy}
+}
+
+// recordCell returns the cell r belongs in, quantising r's world
+// location, or latitude and longitude, where set
+func recordCell(s *server.Schema, r *server.Record) (x, y int32) {
+	w := recordWorld(s, r)
+	if w == nil || s.Transform == nil {
+		return r.X, r.Y
+	}
+
+	gx, gy := toGrid(s.Transform, w.X, w.Y)
+
+	return clampInt32(math.Floor(gx)), clampInt32(math.Floor(gy))
+}
+
+// validateWorld ensures any world coordinates r carries can be
+// quantised into the dataset described by s
+func validateWorld(s *server.Schema, r *server.Record) error {
+	if r.World == nil {
+		return nil
+	}
+
+	if s.Transform == nil {
+		return MissingTransformError
+	}
+
+	if math.IsNaN(r.World.X) || math.IsInf(r.World.X, 0) || math.IsNaN(r.World.Y) || math.IsInf(r.World.Y, 0) {
+		return InvalidWorldPointError
+	}
+
+	return nil
+}
+
+// gridPolygons converts polygons in world coordinates into grid polygons
+// which hold the same cells, where a cell is held by a polygon when its
+// centre is within that polygon
+func gridPolygons(t *server.Transform, polygons []*server.Polygon) (grid []*server.Polygon) {
+	ring := func(r *server.Ring) *server.Ring {
+		out := &server.Ring{Vertices: make([]*server.Vertex, len(r.Vertices))}
+		for i, v := range r.Vertices {
+			gx, gy := toGrid(t, v.X, v.Y)
+
+			// Polygons are tested against a cell's (x,y), rather than its
+			// centre, so shift the polygon rather than every cell
+			out.Vertices[i] = &server.Vertex{X: gx - 0.5, Y: gy - 0.5}
+		}
+
+		return out
+	}
+
+	for _, p := range polygons {
+		gp := &server.Polygon{Outline: ring(p.Outline)}
+		for _, h := range p.Holes {
+			gp.Holes = append(gp.Holes, ring(h))
+		}
+
+		grid = append(grid, gp)
+	}
+
+	return
+}
+
+// worldRecord sets r's world location to the centre of its cell, where
+// the matcher's dataset has a transform and r doesn't already have one.
+//
+// It's only for records built on the fly, such as rollups, rather than
+// stored records, which have their world location set on insert
+func (m matcher) worldRecord(r *server.Record) *server.Record {
+	if m.transform != nil && r.World == nil {
+		r.World = cellCentre(m.transform, r.X, r.Y)
+	}
+
+	return r
+}
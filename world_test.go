@@ -0,0 +1,140 @@
+package xyt
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestTransform(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		transform *server.Transform
+		wx, wy    float64
+		gx, gy    float64
+	}{
+		{"Scaling", &server.Transform{CellSize: 0.5}, 2, 3, 4, 6},
+		{"Offsetting", &server.Transform{CellSize: 1, OriginX: 10, OriginY: -10}, 12, -7, 2, 3},
+		{"Rotating", &server.Transform{CellSize: 1, Rotation: 90}, 0, 2, 2, 0},
+		{"Everything", &server.Transform{CellSize: 2, OriginX: 100, OriginY: 100, Rotation: 180}, 96, 90, 2, 5},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			gx, gy := toGrid(test.transform, test.wx, test.wy)
+			if math.Abs(gx-test.gx) > 1e-9 || math.Abs(gy-test.gy) > 1e-9 {
+				t.Errorf("expected grid (%v,%v), received (%v,%v)", test.gx, test.gy, gx, gy)
+			}
+
+			wx, wy := toWorld(test.transform, gx, gy)
+			if math.Abs(wx-test.wx) > 1e-9 || math.Abs(wy-test.wy) > 1e-9 {
+				t.Errorf("expected world (%v,%v), received (%v,%v)", test.wx, test.wy, wx, wy)
+			}
+		})
+	}
+}
+
+func TestDatabase_World(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.CreateDataset(&server.Schema{Dataset: "site-b", XMax: 10, YMax: 10, Transform: &server.Transform{}})
+	if err != InvalidTransformError {
+		t.Errorf("expected InvalidTransformError, received %#v", err)
+	}
+
+	// Half metre cells, with the grid's origin 100m east of the site's
+	err = d.CreateDataset(&server.Schema{
+		Dataset:   "site-a",
+		XMin:      -10,
+		XMax:      10,
+		YMin:      -10,
+		YMax:      10,
+		Transform: &server.Transform{CellSize: 0.5, OriginX: 100},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.CreateDataset(&server.Schema{Dataset: "site-c", XMax: 10, YMax: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name        string
+		record      *server.Record
+		expectX     int32
+		expectY     int32
+		expectWorld *server.WorldPoint
+		expectError error
+	}{
+		{"World locations are quantised", &server.Record{Dataset: "site-a", World: &server.WorldPoint{X: 101.3, Y: 0.9}}, 2, 1, &server.WorldPoint{X: 101.3, Y: 0.9}, nil},
+		{"World locations replace X and Y", &server.Record{Dataset: "site-a", X: 5, Y: 5, World: &server.WorldPoint{X: 99.9, Y: -0.1}}, -1, -1, &server.WorldPoint{X: 99.9, Y: -0.1}, nil},
+		{"Grid locations gain a world location", &server.Record{Dataset: "site-a", X: 4, Y: -2}, 4, -2, &server.WorldPoint{X: 102.25, Y: -0.75}, nil},
+		{"World locations outside the grid fail", &server.Record{Dataset: "site-a", World: &server.WorldPoint{X: 110, Y: 0}}, 0, 0, nil, PositionOutOfBoundsError{dataset: "site-a", position: positionX, min: -10, max: 9, received: 20}},
+		{"World locations must be finite", &server.Record{Dataset: "site-a", World: &server.WorldPoint{X: math.Inf(1)}}, 0, 0, nil, InvalidWorldPointError},
+		{"World locations need a transform", &server.Record{Dataset: "site-c", World: &server.WorldPoint{}}, 0, 0, nil, MissingTransformError},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			test.record.Name = "temperature"
+			test.record.Meta = &server.Metadata{When: timestamppb.New(time.Unix(1000, 0))}
+
+			err := d.InsertRecord(test.record)
+			if err != test.expectError {
+				t.Fatalf("expected %#v, received %#v", test.expectError, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if test.record.X != test.expectX || test.record.Y != test.expectY {
+				t.Errorf("expected (%d,%d), received (%d,%d)", test.expectX, test.expectY, test.record.X, test.record.Y)
+			}
+
+			w := test.record.World
+			if math.Abs(w.X-test.expectWorld.X) > 1e-9 || math.Abs(w.Y-test.expectWorld.Y) > 1e-9 {
+				t.Errorf("expected world %v, received %v", test.expectWorld, w)
+			}
+		})
+	}
+
+	// Records now sit at (2,1), (-1,-1), and (4,-2)
+	for _, test := range []struct {
+		name        string
+		query       *server.Query
+		expectCount int
+		expectError error
+	}{
+		{"World polygons match cell centres", &server.Query{WorldPolygons: []*server.Polygon{{Outline: ring(100.5, 0, 102, 0, 102, 1, 100.5, 1)}}}, 1, nil},
+		{"World polygons skip cells whose centres they miss", &server.Query{WorldPolygons: []*server.Polygon{{Outline: ring(101.3, 0, 101.4, 0, 101.4, 1, 101.3, 1)}}}, 0, nil},
+		{"World polygons cover negative cells", &server.Query{WorldPolygons: []*server.Polygon{{Outline: ring(90, -10, 110, -10, 110, 0, 90, 0)}}}, 2, nil},
+		{"World polygons need a transform", &server.Query{Dataset: "site-c", WorldPolygons: []*server.Polygon{{Outline: ring(0, 0, 1, 0, 1, 1)}}}, 0, MissingTransformError},
+		{"World polygons must be valid", &server.Query{WorldPolygons: []*server.Polygon{{}}}, 0, InvalidPolygonError},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if test.query.Dataset == "" {
+				test.query.Dataset = "site-a"
+			}
+
+			records, err := d.RetrieveRecords(test.query)
+			if err != test.expectError {
+				t.Fatalf("expected %#v, received %#v", test.expectError, err)
+			}
+
+			if test.expectCount != len(records) {
+				t.Errorf("expected %d records, received %d", test.expectCount, len(records))
+			}
+
+			for _, r := range records {
+				if r.World == nil {
+					t.Errorf("(%d,%d): expected a world location", r.X, r.Y)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package xyt
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/proto"
+)
+
+// AddZone stores z's polygon against its dataset under z's name, so that
+// queries can refer to it by name rather than repeating the polygon.
+//
+// Zones are part of a dataset's schema, and so are kept in both the WAL and
+// snapshots. Adding a zone with the name of an existing zone fails; delete
+// the old zone first
+func (d *Database) AddZone(z *server.Zone) (err error) {
+	if z == nil || z.Dataset == "" {
+		return MissingDatasetError
+	}
+
+	err = validateZone(z.Name, z.Polygon)
+	if err != nil {
+		return
+	}
+
+	unlock := d.lock(z.Dataset)
+	defer unlock()
+
+	schema, ok := d.schemata[z.Dataset]
+	if !ok {
+		return UnknownDatasetError
+	}
+
+	if _, ok := schema.Zones[z.Name]; ok {
+		return DuplicateZoneError
+	}
+
+	if d.wal != nil {
+		err = d.wal.appendZone(z)
+		if err != nil {
+			return
+		}
+	}
+
+	// Zones are swapped in wholesale, rather than updated in place,
+	// so that anything still holding the old map sees a consistent
+	// set of zones
+	zones := maps.Clone(schema.Zones)
+	if zones == nil {
+		zones = make(map[string]*server.Polygon)
+	}
+
+	zones[z.Name] = proto.Clone(z.Polygon).(*server.Polygon)
+	schema.Zones = zones
+
+	return
+}
+
+// Zones returns a copy of every zone stored against dataset,
+// ordered by name
+func (d *Database) Zones(dataset string) (zones []*server.Zone, err error) {
+	if dataset == "" {
+		return nil, MissingDatasetError
+	}
+
+	unlock := d.rlock(dataset)
+	defer unlock()
+
+	schema, ok := d.schemata[dataset]
+	if !ok {
+		return nil, UnknownDatasetError
+	}
+
+	zones = make([]*server.Zone, 0, len(schema.Zones))
+	for _, name := range slices.Sorted(maps.Keys(schema.Zones)) {
+		zones = append(zones, &server.Zone{
+			Dataset: dataset,
+			Name:    name,
+			Polygon: proto.Clone(schema.Zones[name]).(*server.Polygon),
+		})
+	}
+
+	return
+}
+
+// DeleteZone removes a zone from its dataset.
+//
+// Subscriptions already using the zone carry on matching
+// against it; new queries using it fail
+func (d *Database) DeleteZone(dataset, name string) (err error) {
+	if dataset == "" {
+		return MissingDatasetError
+	}
+
+	unlock := d.lock(dataset)
+	defer unlock()
+
+	schema, ok := d.schemata[dataset]
+	if !ok {
+		return UnknownDatasetError
+	}
+
+	if _, ok := schema.Zones[name]; !ok {
+		return UnknownZoneError
+	}
+
+	if d.wal != nil {
+		err = d.wal.appendDeleteZone(dataset, name)
+		if err != nil {
+			return
+		}
+	}
+
+	zones := maps.Clone(schema.Zones)
+	delete(zones, name)
+
+	schema.Zones = zones
+
+	return
+}
+
+// validateZone ensures a zone has a name and a valid polygon
+func validateZone(name string, p *server.Polygon) error {
+	if name == "" {
+		return MissingZoneNameError
+	}
+
+	return validatePolygon(p)
+}
+
+// zonePolygons returns the polygons for each of the named zones in s,
+// erroring where any don't exist
+func zonePolygons(s *server.Schema, names []string) (polygons []*server.Polygon, err error) {
+	for _, name := range names {
+		p, ok := s.Zones[name]
+		if !ok {
+			return nil, UnknownZoneError
+		}
+
+		polygons = append(polygons, p)
+	}
+
+	return
+}
@@ -0,0 +1,237 @@
+package xyt
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xyt-db/xyt/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// zoneTestDatabase returns a database holding a single dataset, site-a,
+// with a temperature reading at each of (0,0) to (9,9), along its diagonal
+func zoneTestDatabase(t *testing.T) *Database {
+	t.Helper()
+
+	var records []*server.Record
+	for x := int32(0); x < 10; x++ {
+		records = append(records, &server.Record{
+			Meta:    &server.Metadata{When: timestamppb.New(time.Unix(1000, 0))},
+			Dataset: "site-a",
+			Name:    "temperature",
+			X:       x,
+			Y:       x,
+		})
+	}
+
+	return testDatabase(t, nil, []*server.Schema{{
+		Dataset:             "site-a",
+		XMin:                0,
+		XMax:                10,
+		YMin:                0,
+		YMax:                10,
+		SortOnInsert:        true,
+		LazyInitialAllocate: true,
+	}}, records)
+}
+
+func TestDatabase_AddZone(t *testing.T) {
+	d := zoneTestDatabase(t)
+
+	dock := &server.Polygon{Outline: ring(0, 0, 3, 0, 3, 3, 0, 3)}
+
+	for _, test := range []struct {
+		name        string
+		zone        *server.Zone
+		expectError error
+	}{
+		{"Nil zones fail", nil, MissingDatasetError},
+		{"Zones need a dataset", &server.Zone{Name: "dock-3", Polygon: dock}, MissingDatasetError},
+		{"Zones need a name", &server.Zone{Dataset: "site-a", Polygon: dock}, MissingZoneNameError},
+		{"Zones need a polygon", &server.Zone{Dataset: "site-a", Name: "dock-3"}, InvalidPolygonError},
+		{"Zones need a known dataset", &server.Zone{Dataset: "site-b", Name: "dock-3", Polygon: dock}, UnknownDatasetError},
+		{"Valid zones are added", &server.Zone{Dataset: "site-a", Name: "dock-3", Polygon: dock}, nil},
+		{"Duplicate zones fail", &server.Zone{Dataset: "site-a", Name: "dock-3", Polygon: dock}, DuplicateZoneError},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := d.AddZone(test.zone)
+			if err != test.expectError {
+				t.Errorf("expected %#v, received %#v", test.expectError, err)
+			}
+		})
+	}
+}
+
+func TestDatabase_Zones(t *testing.T) {
+	d := zoneTestDatabase(t)
+
+	for _, z := range []*server.Zone{
+		{Dataset: "site-a", Name: "dock-3", Polygon: &server.Polygon{Outline: ring(0, 0, 3, 0, 3, 3, 0, 3)}},
+		{Dataset: "site-a", Name: "aisle-1", Polygon: &server.Polygon{Outline: ring(5, 5, 9, 5, 9, 9, 5, 9)}},
+	} {
+		err := d.AddZone(z)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	zones, err := d.Zones("site-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(zones) != 2 || zones[0].Name != "aisle-1" || zones[1].Name != "dock-3" {
+		t.Fatalf("expected aisle-1 and dock-3, received %v", zones)
+	}
+
+	for _, test := range []struct {
+		name        string
+		query       *server.Query
+		expect      int
+		expectError error
+	}{
+		{"Zones limit results", &server.Query{Zones: []string{"dock-3"}}, 4, nil},
+		{"Multiple zones are a union", &server.Query{Zones: []string{"dock-3", "aisle-1"}}, 9, nil},
+		{"Zones combine with polygons", &server.Query{Zones: []string{"dock-3"}, Polygons: []*server.Polygon{{Outline: ring(9, 9, 10, 9, 10, 10)}}}, 5, nil},
+		{"Unknown zones fail", &server.Query{Zones: []string{"dock-4"}}, 0, UnknownZoneError},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			test.query.Dataset = "site-a"
+
+			records, err := d.RetrieveRecords(test.query)
+			if err != test.expectError {
+				t.Fatalf("expected %#v, received %#v", test.expectError, err)
+			}
+
+			if len(records) != test.expect {
+				t.Errorf("expected %d records, received %d", test.expect, len(records))
+			}
+		})
+	}
+
+	t.Run("Aggregations use zones", func(t *testing.T) {
+		results, err := d.Aggregate(&server.Query{Dataset: "site-a", Zones: []string{"aisle-1"}}, []server.Aggregation{server.Aggregation_Count}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if results[0].Count != 5 {
+			t.Errorf("expected 5, received %d", results[0].Count)
+		}
+	})
+
+	t.Run("Deleted zones can't be queried", func(t *testing.T) {
+		err := d.DeleteZone("site-a", "dock-3")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = d.DeleteZone("site-a", "dock-3")
+		if err != UnknownZoneError {
+			t.Errorf("expected UnknownZoneError, received %#v", err)
+		}
+
+		_, err = d.RetrieveRecords(&server.Query{Dataset: "site-a", Zones: []string{"dock-3"}})
+		if err != UnknownZoneError {
+			t.Errorf("expected UnknownZoneError, received %#v", err)
+		}
+	})
+}
+
+func TestDatabase_Zones_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "xyt.wal")
+
+	w, err := OpenWAL(path, WALOptions{Policy: SyncAlways})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.UseWAL(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.CreateDataset(&server.Schema{Dataset: "site-a", XMax: 10, YMax: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"dock-3", "dock-4"} {
+		err = d.AddZone(&server.Zone{Dataset: "site-a", Name: name, Polygon: &server.Polygon{Outline: ring(0, 0, 3, 0, 3, 3)}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = d.DeleteZone("site-a", "dock-4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectZones := func(t *testing.T, d *Database) {
+		t.Helper()
+
+		zones, err := d.Zones("site-a")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(zones) != 1 || zones[0].Name != "dock-3" {
+			t.Errorf("expected dock-3, received %v", zones)
+		}
+	}
+
+	t.Run("WAL", func(t *testing.T) {
+		w, err := OpenWAL(path, WALOptions{Policy: SyncAlways})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer w.Close()
+
+		replayed, err := New()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = replayed.UseWAL(w)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expectZones(t, replayed)
+	})
+
+	t.Run("Snapshot", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+
+		err := d.Snapshot(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		restored, err := New()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = restored.Restore(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expectZones(t, restored)
+	})
+}